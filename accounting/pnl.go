@@ -0,0 +1,273 @@
+package accounting
+
+import (
+	"math/big"
+	"openseasync/models"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	METHOD_FIFO     = "fifo"     // disposals consume the oldest lots of the token first
+	METHOD_SPECIFIC = "specific" // disposals consume the lots named by the caller, then fall back to fifo
+)
+
+// Value an amount in ETH and in USD
+type Value struct {
+	Eth float64 `json:"eth"`
+	Usd float64 `json:"usd"`
+}
+
+func (v Value) add(o Value) Value {
+	return Value{Eth: v.Eth + o.Eth, Usd: v.Usd + o.Usd}
+}
+
+func (v Value) sub(o Value) Value {
+	return Value{Eth: v.Eth - o.Eth, Usd: v.Usd - o.Usd}
+}
+
+func (v Value) scale(f float64) Value {
+	return Value{Eth: v.Eth * f, Usd: v.Usd * f}
+}
+
+// Lot units of a token acquired by one purchase
+type Lot struct {
+	ActivityId   int     `json:"activityId"`
+	Contract     string  `json:"contract"`
+	TokenId      string  `json:"tokenId"`
+	CollectionId string  `json:"collectionId"`
+	Quantity     float64 `json:"quantity"`
+	Remaining    float64 `json:"remaining"`
+	AcquiredAt   int64   `json:"acquiredAt"`
+	TxHash       string  `json:"txHash"`
+	Cost         Value   `json:"cost"`                  // cost of the whole lot
+	MarketValue  *Value  `json:"marketValue,omitempty"` // remaining units at the collection floor, nil without a floor
+	Unrealized   *Value  `json:"unrealized,omitempty"`  // market value less the cost of the remaining units
+}
+
+// LotMatch the part of a lot consumed by a disposal
+type LotMatch struct {
+	ActivityId int     `json:"activityId"`
+	Quantity   float64 `json:"quantity"`
+	Cost       Value   `json:"cost"`
+}
+
+// Disposal units of a token sold by one sale
+type Disposal struct {
+	ActivityId        int        `json:"activityId"`
+	Contract          string     `json:"contract"`
+	TokenId           string     `json:"tokenId"`
+	CollectionId      string     `json:"collectionId"`
+	Quantity          float64    `json:"quantity"`
+	DisposedAt        int64      `json:"disposedAt"`
+	TxHash            string     `json:"txHash"`
+	Proceeds          Value      `json:"proceeds"`
+	Cost              Value      `json:"cost"`
+	Gain              Value      `json:"gain"`
+	Lots              []LotMatch `json:"lots"`
+	UnmatchedQuantity float64    `json:"unmatchedQuantity,omitempty"` // units sold without a known purchase, minted or received, at zero cost
+}
+
+// YearSummary realized results of the disposals of a calendar year, UTC
+type YearSummary struct {
+	Year      int   `json:"year"`
+	Disposals int   `json:"disposals"`
+	Proceeds  Value `json:"proceeds"`
+	Cost      Value `json:"cost"`
+	Gain      Value `json:"gain"`
+}
+
+// Report cost basis and profit and loss of a wallet
+type Report struct {
+	Method     string        `json:"method"`
+	OpenLots   []*Lot        `json:"openLots"`
+	Disposals  []Disposal    `json:"disposals"`
+	Years      []YearSummary `json:"years"`
+	CostBasis  Value         `json:"costBasis"` // cost of the units still held
+	Realized   Value         `json:"realized"`
+	Unrealized Value         `json:"unrealized"` // of the open lots whose collection has a floor
+	Unpriced   int           `json:"unpriced"`   // sales valued at zero, their token's rates never having been recorded
+	// sales valued at the rates recorded closest to them but more than an hour away, rates being recorded from
+	// the first sync on, the sales before it are valued at the earliest ones
+	Approximate int `json:"approximate"`
+}
+
+// Prices market data used to value the open lots
+type Prices struct {
+	Floors map[string]*big.Int // collection id to floor price in wei
+	EthUsd float64
+}
+
+// Compute rebuild the lots of user from its sales, oldest first, and match every disposal against them.
+// matches names, for METHOD_SPECIFIC, the purchase activity a sale activity disposes of.
+func Compute(user string, sales []models.ItemActivity, method string, matches map[int]int, prices Prices) *Report {
+	report := &Report{Method: method, OpenLots: make([]*Lot, 0), Disposals: make([]Disposal, 0), Years: make([]YearSummary, 0)}
+	lots := make(map[string][]*Lot)
+	years := make(map[int]*YearSummary)
+
+	for _, v := range sales {
+		bought := strings.EqualFold(v.BuyerMetamaskId, user)
+		sold := strings.EqualFold(v.SellerMetamaskId, user)
+		if bought == sold {
+			continue
+		}
+		key := v.ContractAddress + "/" + v.TokenId
+		quantity := parseQuantity(v.Quantity)
+		value, priced := SaleValue(v)
+		if !priced {
+			report.Unpriced++
+		} else if v.TradeRatesApproximate() {
+			report.Approximate++
+		}
+
+		if bought {
+			lots[key] = append(lots[key], &Lot{
+				ActivityId:   v.Id,
+				Contract:     v.ContractAddress,
+				TokenId:      v.TokenId,
+				CollectionId: v.CollectionId,
+				Quantity:     quantity,
+				Remaining:    quantity,
+				AcquiredAt:   v.CreateDate,
+				TxHash:       v.Transaction.TransactionHash,
+				Cost:         value,
+			})
+			continue
+		}
+
+		disposal := Disposal{
+			ActivityId:   v.Id,
+			Contract:     v.ContractAddress,
+			TokenId:      v.TokenId,
+			CollectionId: v.CollectionId,
+			Quantity:     quantity,
+			DisposedAt:   v.CreateDate,
+			TxHash:       v.Transaction.TransactionHash,
+			Proceeds:     value,
+			Lots:         make([]LotMatch, 0),
+		}
+		remaining := quantity
+		if lotId, ok := matches[v.Id]; ok && method == METHOD_SPECIFIC {
+			for _, lot := range lots[key] {
+				if lot.ActivityId == lotId {
+					remaining = consume(&disposal, lot, remaining)
+				}
+			}
+		}
+		for _, lot := range lots[key] {
+			if remaining <= 0 {
+				break
+			}
+			remaining = consume(&disposal, lot, remaining)
+		}
+		disposal.UnmatchedQuantity = remaining
+		disposal.Gain = disposal.Proceeds.sub(disposal.Cost)
+		report.Disposals = append(report.Disposals, disposal)
+		report.Realized = report.Realized.add(disposal.Gain)
+
+		year := time.UnixMilli(disposal.DisposedAt).UTC().Year()
+		summary, ok := years[year]
+		if !ok {
+			summary = &YearSummary{Year: year}
+			years[year] = summary
+		}
+		summary.Disposals++
+		summary.Proceeds = summary.Proceeds.add(disposal.Proceeds)
+		summary.Cost = summary.Cost.add(disposal.Cost)
+		summary.Gain = summary.Gain.add(disposal.Gain)
+	}
+
+	for _, tokenLots := range lots {
+		for _, lot := range tokenLots {
+			if lot.Remaining <= 0 {
+				continue
+			}
+			cost := lot.Cost.scale(lot.Remaining / lot.Quantity)
+			report.CostBasis = report.CostBasis.add(cost)
+			if floor, ok := prices.Floors[lot.CollectionId]; ok {
+				eth := weiToEth(floor) * lot.Remaining
+				market := Value{Eth: eth, Usd: eth * prices.EthUsd}
+				unrealized := market.sub(cost)
+				lot.MarketValue, lot.Unrealized = &market, &unrealized
+				report.Unrealized = report.Unrealized.add(unrealized)
+			}
+			report.OpenLots = append(report.OpenLots, lot)
+		}
+	}
+	sort.Slice(report.OpenLots, func(i, j int) bool {
+		return report.OpenLots[i].AcquiredAt < report.OpenLots[j].AcquiredAt
+	})
+	for _, summary := range years {
+		report.Years = append(report.Years, *summary)
+	}
+	sort.Slice(report.Years, func(i, j int) bool {
+		return report.Years[i].Year < report.Years[j].Year
+	})
+	return report
+}
+
+// consume take up to quantity units out of lot for disposal, return the units still to match
+func consume(disposal *Disposal, lot *Lot, quantity float64) float64 {
+	if lot.Remaining <= 0 || quantity <= 0 {
+		return quantity
+	}
+	take := quantity
+	if lot.Remaining < take {
+		take = lot.Remaining
+	}
+	cost := lot.Cost.scale(take / lot.Quantity)
+	lot.Remaining -= take
+	disposal.Cost = disposal.Cost.add(cost)
+	disposal.Lots = append(disposal.Lots, LotMatch{ActivityId: lot.ActivityId, Quantity: take, Cost: cost})
+	return quantity - take
+}
+
+// SaleValue value of a sale in ETH and USD at the rates of its payment token recorded closest to the sale,
+// false when the amount or either rate is unknown, that part of the value is then 0
+func SaleValue(activity models.ItemActivity) (Value, bool) {
+	amount, ok := models.TokenAmount(activity.Price, activity.PayTokenContract.TokenDecimals())
+	if !ok {
+		return Value{}, false
+	}
+	var value Value
+	ethPrice := activity.TradeEthPrice
+	if models.IsEthToken(activity.PayTokenContract.Symbol) {
+		ethPrice = "1"
+	}
+	eth, err := strconv.ParseFloat(ethPrice, 64)
+	if err == nil {
+		value.Eth = amount * eth
+	}
+	usd, usdErr := strconv.ParseFloat(activity.TradeUsdPrice, 64)
+	if usdErr == nil {
+		value.Usd = amount * usd
+	}
+	return value, err == nil && usdErr == nil
+}
+
+// LatestEthUsd usd price of ETH at the most recent sale paid in ETH or WETH, 0 when there is none
+func LatestEthUsd(sales []models.ItemActivity) float64 {
+	for i := len(sales) - 1; i >= 0; i-- {
+		if !models.IsEthToken(sales[i].PayTokenContract.Symbol) {
+			continue
+		}
+		if usdPrice, err := strconv.ParseFloat(sales[i].PayTokenContract.UsdPrice, 64); err == nil && usdPrice > 0 {
+			return usdPrice
+		}
+	}
+	return 0
+}
+
+func weiToEth(wei *big.Int) float64 {
+	eth, _ := new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(1e18)).Float64()
+	return eth
+}
+
+func parseQuantity(quantity string) float64 {
+	if q, err := strconv.ParseFloat(quantity, 64); err == nil && q > 0 {
+		return q
+	}
+	return 1
+}
@@ -0,0 +1,214 @@
+package accounting
+
+import (
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"openseasync/models"
+)
+
+const (
+	testUser   = "0x1111111111111111111111111111111111111111"
+	testOther  = "0x2222222222222222222222222222222222222222"
+	testToken  = "0x00000000000000000000000000000000000c0de1"
+	testEthUsd = 1000
+)
+
+var testStart = time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC)
+
+// trade a sale of quantity units of token 1 for eth ETH at testEthUsd, day days after testStart, bought by
+// the test user or sold by it
+func trade(id int, bought bool, quantity string, eth string, day int) models.ItemActivity {
+	at := testStart.AddDate(0, 0, day).UnixMilli()
+	price, _ := new(big.Float).Mul(parseFloat(eth), big.NewFloat(1e18)).Int(nil)
+	activity := models.ItemActivity{Id: id, ContractAddress: testToken, TokenId: "1", CollectionId: "fixtures",
+		Quantity: quantity, CreateDate: at, Price: price.String(), SellerMetamaskId: testOther, BuyerMetamaskId: testUser,
+		PayTokenContract: models.PayTokenContract{Symbol: "ETH"}, TradeEthPrice: "1", TradeUsdPrice: "1000",
+		TradePriceTime: at}
+	if !bought {
+		activity.SellerMetamaskId, activity.BuyerMetamaskId = testUser, testOther
+	}
+	return activity
+}
+
+func parseFloat(s string) *big.Float {
+	f, _ := new(big.Float).SetString(s)
+	return f
+}
+
+type disposalWant struct {
+	lots      []int
+	cost      float64
+	gain      float64
+	unmatched float64
+}
+
+func TestCompute(t *testing.T) {
+	unpriced := trade(2, false, "1", "5", 1)
+	unpriced.PayTokenContract.Symbol, unpriced.TradeEthPrice, unpriced.TradeUsdPrice, unpriced.TradePriceTime = "USDC", "", "", 0
+	approximate := trade(2, false, "1", "3", 400)
+	approximate.TradePriceTime = testStart.UnixMilli()
+
+	tests := []struct {
+		name        string
+		sales       []models.ItemActivity
+		method      string
+		matches     map[int]int
+		floors      map[string]*big.Int
+		disposals   []disposalWant
+		costBasis   float64
+		realized    float64
+		unrealized  float64
+		unpriced    int
+		approximate int
+	}{
+		{
+			name:      "fifo consumes the oldest lot",
+			sales:     []models.ItemActivity{trade(1, true, "1", "1", 0), trade(2, true, "1", "2", 1), trade(3, false, "1", "3", 2)},
+			method:    METHOD_FIFO,
+			disposals: []disposalWant{{lots: []int{1}, cost: 1, gain: 2}},
+			costBasis: 2,
+			realized:  2,
+		},
+		{
+			name:      "specific consumes the named lot",
+			sales:     []models.ItemActivity{trade(1, true, "1", "1", 0), trade(2, true, "1", "2", 1), trade(3, false, "1", "3", 2)},
+			method:    METHOD_SPECIFIC,
+			matches:   map[int]int{3: 2},
+			disposals: []disposalWant{{lots: []int{2}, cost: 2, gain: 1}},
+			costBasis: 1,
+			realized:  1,
+		},
+		{
+			name:      "specific falls back to fifo past the named lot",
+			sales:     []models.ItemActivity{trade(1, true, "1", "1", 0), trade(2, true, "1", "2", 1), trade(3, false, "2", "6", 2)},
+			method:    METHOD_SPECIFIC,
+			matches:   map[int]int{3: 2},
+			disposals: []disposalWant{{lots: []int{2, 1}, cost: 3, gain: 3}},
+			realized:  3,
+		},
+		{
+			name:       "partial lots of an erc1155 token",
+			sales:      []models.ItemActivity{trade(1, true, "10", "10", 0), trade(2, false, "4", "6", 1), trade(3, false, "2", "1", 2)},
+			method:     METHOD_FIFO,
+			floors:     map[string]*big.Int{"fixtures": big.NewInt(2e18)},
+			disposals:  []disposalWant{{lots: []int{1}, cost: 4, gain: 2}, {lots: []int{1}, cost: 2, gain: -1}},
+			costBasis:  4,
+			realized:   1,
+			unrealized: 4*2 - 4,
+		},
+		{
+			name:      "units sold without a purchase are unmatched at zero cost",
+			sales:     []models.ItemActivity{trade(1, true, "1", "1", 0), trade(2, false, "3", "6", 1)},
+			method:    METHOD_FIFO,
+			disposals: []disposalWant{{lots: []int{1}, cost: 1, gain: 5, unmatched: 2}},
+			realized:  5,
+		},
+		{
+			name:      "a sale without rates is valued at zero",
+			sales:     []models.ItemActivity{trade(1, true, "1", "1", 0), unpriced},
+			method:    METHOD_FIFO,
+			disposals: []disposalWant{{lots: []int{1}, cost: 1, gain: -1}},
+			realized:  -1,
+			unpriced:  1,
+		},
+		{
+			name:        "a sale valued at rates recorded far from it is approximate",
+			sales:       []models.ItemActivity{trade(1, true, "1", "1", 0), approximate},
+			method:      METHOD_FIFO,
+			disposals:   []disposalWant{{lots: []int{1}, cost: 1, gain: 2}},
+			realized:    2,
+			approximate: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := Compute(testUser, tt.sales, tt.method, tt.matches, Prices{Floors: tt.floors, EthUsd: testEthUsd})
+			if len(report.Disposals) != len(tt.disposals) {
+				t.Fatalf("got %d disposals, want %d", len(report.Disposals), len(tt.disposals))
+			}
+			for i, want := range tt.disposals {
+				got := report.Disposals[i]
+				lots := make([]int, 0, len(got.Lots))
+				for _, match := range got.Lots {
+					lots = append(lots, match.ActivityId)
+				}
+				if !equalInts(lots, want.lots) {
+					t.Errorf("disposal %d: matched lots %v, want %v", i, lots, want.lots)
+				}
+				checkValue(t, "disposal cost", got.Cost, want.cost)
+				checkValue(t, "disposal gain", got.Gain, want.gain)
+				if !near(got.UnmatchedQuantity, want.unmatched) {
+					t.Errorf("disposal %d: unmatched %v, want %v", i, got.UnmatchedQuantity, want.unmatched)
+				}
+			}
+			checkValue(t, "cost basis", report.CostBasis, tt.costBasis)
+			checkValue(t, "realized", report.Realized, tt.realized)
+			checkValue(t, "unrealized", report.Unrealized, tt.unrealized)
+			if report.Unpriced != tt.unpriced {
+				t.Errorf("unpriced %d, want %d", report.Unpriced, tt.unpriced)
+			}
+			if report.Approximate != tt.approximate {
+				t.Errorf("approximate %d, want %d", report.Approximate, tt.approximate)
+			}
+		})
+	}
+}
+
+func TestComputeYears(t *testing.T) {
+	sales := []models.ItemActivity{trade(1, true, "2", "2", 0), trade(2, false, "1", "3", 1), trade(3, false, "1", "4", 45)}
+	report := Compute(testUser, sales, METHOD_FIFO, nil, Prices{})
+	if len(report.Years) != 2 || report.Years[0].Year != 2021 || report.Years[1].Year != 2022 {
+		t.Fatalf("got years %+v, want 2021 and 2022", report.Years)
+	}
+	checkValue(t, "2021 gain", report.Years[0].Gain, 2)
+	checkValue(t, "2022 gain", report.Years[1].Gain, 3)
+}
+
+func TestLatestEthUsd(t *testing.T) {
+	sale := func(symbol, usdPrice string) models.ItemActivity {
+		return models.ItemActivity{PayTokenContract: models.PayTokenContract{Symbol: symbol, UsdPrice: usdPrice}}
+	}
+	tests := []struct {
+		name  string
+		sales []models.ItemActivity
+		want  float64
+	}{
+		{"no sales", nil, 0},
+		{"the latest eth sale", []models.ItemActivity{sale("ETH", "3000"), sale("WETH", "3100")}, 3100},
+		{"other tokens are skipped", []models.ItemActivity{sale("weth", "3100"), sale("USDC", "1")}, 3100},
+		{"sales without a price are skipped", []models.ItemActivity{sale("ETH", "3000"), sale("ETH", "")}, 3000},
+		{"no eth sale", []models.ItemActivity{sale("USDC", "1")}, 0},
+	}
+	for _, tt := range tests {
+		if got := LatestEthUsd(tt.sales); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// checkValue v is eth ETH, worth eth at testEthUsd in USD
+func checkValue(t *testing.T, name string, v Value, eth float64) {
+	t.Helper()
+	if !near(v.Eth, eth) || !near(v.Usd, eth*testEthUsd) {
+		t.Errorf("%s: got %+v, want %v ETH", name, v, eth)
+	}
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,272 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"openseasync/config"
+	"openseasync/events"
+	"openseasync/metrics"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	DEFAULT_MAX_ENTRIES = 10000
+
+	TAG_WALLET     = "wallet"     // responses about a wallet, its collections or profile
+	TAG_COLLECTION = "collection" // responses about a collection or its assets and activity
+	TAG_ASSET      = "asset"      // responses about an asset, its orders or activity
+
+	HeaderCache = "X-Cache" // HIT or MISS
+)
+
+// Entry a cached response
+type Entry struct {
+	Status      int       `json:"status"`
+	ContentType string    `json:"contentType"`
+	Body        []byte    `json:"body"`
+	ETag        string    `json:"etag"`
+	Tags        []string  `json:"tags"` // what the response depends on, see Tag
+	Expires     time.Time `json:"expires"`
+}
+
+// Backend where entries are kept, the in-process LRU by default. A shared backend such as
+// redis lets several instances reuse and invalidate each other's entries.
+type Backend interface {
+	// Get the entry of key, false when missing or expired
+	Get(key string) (*Entry, bool)
+	Set(key string, entry *Entry)
+	// Invalidate drop every entry carrying tag, the number dropped
+	Invalidate(tag string) int
+}
+
+var (
+	backend     Backend
+	backendLock sync.RWMutex
+)
+
+// Start cache responses in b, nil builds the in-process LRU from the configuration, and drop
+// entries when the sync pipeline touches what they depend on
+func Start(b Backend) {
+	if b == nil {
+		b = NewLRU(config.GetConfig().Cache.MaxEntries)
+	}
+	backendLock.Lock()
+	backend = b
+	backendLock.Unlock()
+	events.Subscribe(invalidate)
+}
+
+func getBackend() Backend {
+	backendLock.RLock()
+	defer backendLock.RUnlock()
+	return backend
+}
+
+// Tag the tag of a dependency, wallets are lower case like they are stored
+func Tag(kind, id string) string {
+	if kind == TAG_WALLET {
+		id = strings.ToLower(id)
+	}
+	return kind + ":" + id
+}
+
+// Invalidate drop every entry carrying one of tags
+func Invalidate(tags ...string) {
+	b := getBackend()
+	if b == nil {
+		return
+	}
+	for _, tag := range tags {
+		b.Invalidate(tag)
+	}
+}
+
+// invalidate drop the entries depending on the wallets, collection or asset of an event
+func invalidate(ctx context.Context, event events.Event) {
+	var tags []string
+	for _, wallet := range event.Wallets {
+		if wallet != "" {
+			tags = append(tags, Tag(TAG_WALLET, wallet))
+		}
+	}
+	if event.CollectionId != "" {
+		tags = append(tags, Tag(TAG_COLLECTION, event.CollectionId))
+	}
+	if event.CollectibleId != 0 {
+		tags = append(tags, Tag(TAG_ASSET, strconv.Itoa(event.CollectibleId)))
+	}
+	Invalidate(tags...)
+}
+
+// TagFunc the dependencies of a request
+type TagFunc func(c *gin.Context) []string
+
+// Param tag the request with kind and the value of a path parameter
+func Param(kind, name string) TagFunc {
+	return func(c *gin.Context) []string {
+		if value := c.Param(name); value != "" {
+			return []string{Tag(kind, value)}
+		}
+		return nil
+	}
+}
+
+// TTL the time to live of route, configured under [cache.ttl] by route name, fallback otherwise
+func TTL(route string, fallback time.Duration) time.Duration {
+	if seconds, ok := config.GetConfig().Cache.Ttl[route]; ok {
+		return time.Duration(seconds) * time.Second
+	}
+	return fallback
+}
+
+// Handler cache the 200 responses of a GET route for the TTL of route, tagged by tags.
+// Responses carry an ETag, a request whose If-None-Match matches it is answered with 304.
+func Handler(route string, fallback time.Duration, tags ...TagFunc) gin.HandlerFunc {
+	var (
+		ttl     time.Duration
+		ttlOnce sync.Once
+	)
+	return func(c *gin.Context) {
+		b := getBackend()
+		if b == nil || c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+		ttlOnce.Do(func() { ttl = TTL(route, fallback) })
+		if ttl <= 0 {
+			c.Next()
+			return
+		}
+		key := requestKey(c.Request)
+		if entry, ok := b.Get(key); ok {
+			metrics.ObserveCache(route, "hit")
+			serve(c, entry, "HIT")
+			c.Abort()
+			return
+		}
+		metrics.ObserveCache(route, "miss")
+
+		buffer := &bufferWriter{ResponseWriter: c.Writer, status: http.StatusOK}
+		c.Writer = buffer
+		c.Next()
+		c.Writer = buffer.ResponseWriter
+		if !buffer.written {
+			// nothing was written, a later middleware such as the error handler answers
+			return
+		}
+		if buffer.status != http.StatusOK {
+			c.Writer.WriteHeader(buffer.status)
+			_, _ = c.Writer.Write(buffer.body.Bytes())
+			return
+		}
+
+		entry := &Entry{
+			Status:      buffer.status,
+			ContentType: c.Writer.Header().Get("Content-Type"),
+			Body:        buffer.body.Bytes(),
+			ETag:        etag(buffer.body.Bytes()),
+			Expires:     time.Now().Add(ttl),
+		}
+		for _, fn := range tags {
+			entry.Tags = append(entry.Tags, fn(c)...)
+		}
+		b.Set(key, entry)
+		serve(c, entry, "MISS")
+	}
+}
+
+// requestKey the path and the sorted query of a request
+func requestKey(r *http.Request) string {
+	query := r.URL.Query().Encode()
+	if query == "" {
+		return r.URL.Path
+	}
+	return r.URL.Path + "?" + query
+}
+
+func etag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// serve write entry, or 304 when the client already holds it
+func serve(c *gin.Context, entry *Entry, result string) {
+	maxAge := int(time.Until(entry.Expires).Seconds())
+	if maxAge < 0 {
+		maxAge = 0
+	}
+	c.Header("ETag", entry.ETag)
+	c.Header("Cache-Control", "max-age="+strconv.Itoa(maxAge))
+	c.Header(HeaderCache, result)
+	if matches(c.GetHeader("If-None-Match"), entry.ETag) {
+		c.Status(http.StatusNotModified)
+		c.Writer.WriteHeaderNow()
+		return
+	}
+	c.Data(entry.Status, entry.ContentType, entry.Body)
+}
+
+// matches whether an If-None-Match header names etag, weak validators included
+func matches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// bufferWriter hold the response back so it can be stored and answered with an ETag
+type bufferWriter struct {
+	gin.ResponseWriter
+	status  int
+	written bool
+	body    bytes.Buffer
+}
+
+func (w *bufferWriter) WriteHeader(code int) {
+	if code > 0 {
+		w.status = code
+	}
+}
+
+func (w *bufferWriter) WriteHeaderNow() {
+	w.written = true
+}
+
+func (w *bufferWriter) Write(data []byte) (int, error) {
+	w.written = true
+	return w.body.Write(data)
+}
+
+func (w *bufferWriter) WriteString(s string) (int, error) {
+	w.written = true
+	return w.body.WriteString(s)
+}
+
+func (w *bufferWriter) Status() int {
+	return w.status
+}
+
+func (w *bufferWriter) Size() int {
+	if !w.written {
+		return -1
+	}
+	return w.body.Len()
+}
+
+func (w *bufferWriter) Written() bool {
+	return w.written
+}
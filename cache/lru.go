@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// LRU an in-process Backend holding at most capacity entries, the least recently used is evicted first
+type LRU struct {
+	capacity int
+
+	mu      sync.Mutex
+	order   *list.List // front is the most recently used
+	entries map[string]*list.Element
+	tags    map[string]map[string]struct{} // tag to the keys of the entries carrying it
+}
+
+type lruItem struct {
+	key   string
+	entry *Entry
+}
+
+func NewLRU(capacity int) *LRU {
+	if capacity < 1 {
+		capacity = DEFAULT_MAX_ENTRIES
+	}
+	return &LRU{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+		tags:     make(map[string]map[string]struct{}),
+	}
+}
+
+func (l *LRU) Get(key string) (*Entry, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	element, ok := l.entries[key]
+	if !ok {
+		return nil, false
+	}
+	item := element.Value.(*lruItem)
+	if time.Now().After(item.entry.Expires) {
+		l.remove(element)
+		return nil, false
+	}
+	l.order.MoveToFront(element)
+	return item.entry, true
+}
+
+func (l *LRU) Set(key string, entry *Entry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if element, ok := l.entries[key]; ok {
+		l.remove(element)
+	}
+	l.entries[key] = l.order.PushFront(&lruItem{key: key, entry: entry})
+	for _, tag := range entry.Tags {
+		if l.tags[tag] == nil {
+			l.tags[tag] = make(map[string]struct{})
+		}
+		l.tags[tag][key] = struct{}{}
+	}
+	for l.order.Len() > l.capacity {
+		l.remove(l.order.Back())
+	}
+}
+
+func (l *LRU) Invalidate(tag string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	keys := l.tags[tag]
+	for key := range keys {
+		if element, ok := l.entries[key]; ok {
+			l.remove(element)
+		}
+	}
+	return len(keys)
+}
+
+func (l *LRU) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.order.Len()
+}
+
+// remove drop element and its tag references, called with mu held
+func (l *LRU) remove(element *list.Element) {
+	item := element.Value.(*lruItem)
+	l.order.Remove(element)
+	delete(l.entries, item.key)
+	for _, tag := range item.entry.Tags {
+		delete(l.tags[tag], item.key)
+		if len(l.tags[tag]) == 0 {
+			delete(l.tags, tag)
+		}
+	}
+}
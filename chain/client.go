@@ -0,0 +1,40 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"openseasync/logs"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+var (
+	clients    = make(map[string]*ethclient.Client)
+	clientLock sync.Mutex
+)
+
+// GetClient dial the rpc endpoint of network once and reuse the connection
+func GetClient(network string) (*ethclient.Client, error) {
+	network = NormalizeNetwork(network)
+	clientLock.Lock()
+	defer clientLock.Unlock()
+	if client, ok := clients[network]; ok {
+		return client, nil
+	}
+
+	url := rpcURL(network)
+	if url == "" {
+		return nil, errors.New("no rpc_url is configured for network " + network)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	client, err := ethclient.DialContext(ctx, url)
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	clients[network] = client
+	return client, nil
+}
@@ -0,0 +1,65 @@
+package chain
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ReceiptBackend what the confirmation tracker needs from a node
+type ReceiptBackend interface {
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// CanonicalHash hash of the block at number on the canonical chain
+func CanonicalHash(ctx context.Context, backend Backend, number uint64) (common.Hash, error) {
+	header, err := backend.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return header.Hash(), nil
+}
+
+// Confirmations number of blocks on top of and including number, 0 when number is above head
+func Confirmations(head, number uint64) uint64 {
+	if number > head {
+		return 0
+	}
+	return head - number + 1
+}
+
+// WaitForConfirmations poll the receipt of txHash until its block is canonical and has at least confirmations blocks,
+// a receipt whose block was reorged out is fetched again. It gives up when ctx is done.
+func WaitForConfirmations(ctx context.Context, backend ReceiptBackend, txHash common.Hash, confirmations uint64, interval time.Duration) (*types.Receipt, error) {
+	for {
+		receipt, err := backend.TransactionReceipt(ctx, txHash)
+		if err != nil && err != ethereum.NotFound {
+			return nil, err
+		}
+		if err == nil {
+			head, err := backend.HeaderByNumber(ctx, nil)
+			if err != nil {
+				return nil, err
+			}
+			block, err := backend.HeaderByNumber(ctx, receipt.BlockNumber)
+			if err != nil && err != ethereum.NotFound {
+				return nil, err
+			}
+			if block != nil && block.Hash() == receipt.BlockHash &&
+				Confirmations(head.Number.Uint64(), receipt.BlockNumber.Uint64()) >= confirmations {
+				return receipt, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package chain
+
+import (
+	"math/big"
+	"openseasync/common/constants"
+	"openseasync/config"
+	"strings"
+)
+
+// DefaultNetwork network used when a request names none
+func DefaultNetwork() string {
+	conf := config.GetConfig()
+	if conf.Chain.Network != "" {
+		return strings.ToLower(conf.Chain.Network)
+	}
+	if conf.Dev {
+		return constants.NETWORK_TYPE_GOERLI
+	}
+	return constants.NETWORK_TYPE_ETH
+}
+
+// IsSupportedNetwork ethereum, goerli and the networks configured under [networks]
+func IsSupportedNetwork(network string) bool {
+	switch network {
+	case constants.NETWORK_TYPE_ETH, constants.NETWORK_TYPE_GOERLI:
+		return true
+	}
+	_, ok := config.GetConfig().Networks[network]
+	return ok
+}
+
+// NormalizeNetwork lower case network, the default one when empty
+func NormalizeNetwork(network string) string {
+	network = strings.ToLower(strings.TrimSpace(network))
+	if network == "" {
+		return DefaultNetwork()
+	}
+	return network
+}
+
+// ChainID the eip-155 chain id of network, nil when neither built in nor configured
+func ChainID(network string) *big.Int {
+	if n, ok := config.GetConfig().Networks[network]; ok && n.ChainId > 0 {
+		return big.NewInt(n.ChainId)
+	}
+	switch network {
+	case constants.NETWORK_TYPE_ETH:
+		return big.NewInt(1)
+	case constants.NETWORK_TYPE_GOERLI:
+		return big.NewInt(5)
+	}
+	return nil
+}
+
+// rpcURL json-rpc endpoint of network
+func rpcURL(network string) string {
+	conf := config.GetConfig()
+	if n, ok := conf.Networks[network]; ok && n.RpcUrl != "" {
+		return n.RpcUrl
+	}
+	if network == DefaultNetwork() {
+		return conf.Chain.RpcUrl
+	}
+	return ""
+}
+
+// RpcNetworks networks with a json-rpc endpoint, the default one first
+func RpcNetworks() []string {
+	var networks []string
+	defaultNetwork := DefaultNetwork()
+	if rpcURL(defaultNetwork) != "" {
+		networks = append(networks, defaultNetwork)
+	}
+	for name, n := range config.GetConfig().Networks {
+		name = strings.ToLower(name)
+		if name != defaultNetwork && n.RpcUrl != "" {
+			networks = append(networks, name)
+		}
+	}
+	return networks
+}
@@ -0,0 +1,401 @@
+package chain
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	SIGNATURE_VALID      = "valid"      // the order hash matches the order and the maker signed it
+	SIGNATURE_INVALID    = "invalid"    // the order hash does not match or someone else signed it
+	SIGNATURE_UNVERIFIED = "unverified" // the order cannot be checked locally, unknown exchange or chain, or a bulk signature
+
+	PROTOCOL_WYVERN  = "wyvern"
+	PROTOCOL_SEAPORT = "seaport"
+)
+
+var (
+	eip712DomainTypeHash = crypto.Keccak256Hash([]byte("EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)"))
+
+	wyvernOrderTypeHash = crypto.Keccak256Hash([]byte("Order(address exchange,address maker,address taker,uint256 makerRelayerFee," +
+		"uint256 takerRelayerFee,uint256 makerProtocolFee,uint256 takerProtocolFee,address feeRecipient,uint8 feeMethod,uint8 side," +
+		"uint8 saleKind,address target,uint8 howToCall,bytes calldata,bytes replacementPattern,address staticTarget," +
+		"bytes staticExtradata,address paymentToken,uint256 basePrice,uint256 extra,uint256 listingTime,uint256 expirationTime," +
+		"uint256 salt,uint256 nonce)"))
+
+	seaportOfferItemType         = "OfferItem(uint8 itemType,address token,uint256 identifierOrCriteria,uint256 startAmount,uint256 endAmount)"
+	seaportConsiderationItemType = "ConsiderationItem(uint8 itemType,address token,uint256 identifierOrCriteria,uint256 startAmount," +
+		"uint256 endAmount,address recipient)"
+	seaportOfferItemTypeHash         = crypto.Keccak256Hash([]byte(seaportOfferItemType))
+	seaportConsiderationItemTypeHash = crypto.Keccak256Hash([]byte(seaportConsiderationItemType))
+	seaportOrderTypeHash             = crypto.Keccak256Hash([]byte("OrderComponents(address offerer,address zone,OfferItem[] offer," +
+		"ConsiderationItem[] consideration,uint8 orderType,uint256 startTime,uint256 endTime,bytes32 zoneHash,uint256 salt," +
+		"bytes32 conduitKey,uint256 counter)" + seaportConsiderationItemType + seaportOfferItemType))
+
+	// seaportVersions version of the eip-712 domain of each seaport deployment
+	seaportVersions = map[string]string{
+		"0x00000000006c3852cbef3e08e8df289169ede581": "1.1",
+		"0x00000000000001ad428e4906ae43d8f9852d0dd6": "1.4",
+		"0x00000000000000adc04c56bf30ac9d3c0aaf14dc": "1.5",
+	}
+
+	// wyvernEip712Version version of the eip-712 domain of wyvern exchanges signing typed data, older ones sign the packed hash
+	wyvernEip712Version = "2.3"
+)
+
+// OrderSignature what checking the hash and signature of an order found
+type OrderSignature struct {
+	Status string // valid invalid unverified
+	Signer string // address recovered from the signature, lower case
+	Reason string // why it is not valid
+}
+
+// WyvernOrder the signed fields of a wyvern order, numbers in decimal, bytes in hex
+type WyvernOrder struct {
+	Exchange           string
+	Maker              string
+	Taker              string
+	MakerRelayerFee    string
+	TakerRelayerFee    string
+	MakerProtocolFee   string
+	TakerProtocolFee   string
+	FeeRecipient       string
+	FeeMethod          uint8
+	Side               uint8
+	SaleKind           uint8
+	Target             string
+	HowToCall          uint8
+	Calldata           string
+	ReplacementPattern string
+	StaticTarget       string
+	StaticExtradata    string
+	PaymentToken       string
+	BasePrice          string
+	Extra              string
+	ListingTime        string
+	ExpirationTime     string
+	Salt               string
+	Nonce              string // exchanges signing typed data only
+}
+
+// SeaportOrder the components of a seaport order as opensea sends them in protocol_data.parameters
+type SeaportOrder struct {
+	Offerer       string                     `json:"offerer"`
+	Zone          string                     `json:"zone"`
+	Offer         []SeaportOfferItem         `json:"offer"`
+	Consideration []SeaportConsiderationItem `json:"consideration"`
+	OrderType     json.Number                `json:"orderType"`
+	StartTime     json.Number                `json:"startTime"`
+	EndTime       json.Number                `json:"endTime"`
+	ZoneHash      string                     `json:"zoneHash"`
+	Salt          string                     `json:"salt"`
+	ConduitKey    string                     `json:"conduitKey"`
+	Counter       json.Number                `json:"counter"`
+}
+
+type SeaportOfferItem struct {
+	ItemType             json.Number `json:"itemType"`
+	Token                string      `json:"token"`
+	IdentifierOrCriteria string      `json:"identifierOrCriteria"`
+	StartAmount          string      `json:"startAmount"`
+	EndAmount            string      `json:"endAmount"`
+}
+
+type SeaportConsiderationItem struct {
+	SeaportOfferItem
+	Recipient string `json:"recipient"`
+}
+
+// VerifyWyvernOrder check orderHash is the hash of order on chainId and that its maker signed it with v, r and s.
+// The packed hash signed as a personal message by older exchanges is tried first, then the typed data hash,
+// orderHash may be either the hash of the order or the one signed.
+func VerifyWyvernOrder(chainId *big.Int, order WyvernOrder, orderHash string, v int, r, s string) *OrderSignature {
+	if r == "" || s == "" {
+		return &OrderSignature{Status: SIGNATURE_UNVERIFIED, Reason: "no signature"}
+	}
+	packed, err := wyvernPackedHash(order)
+	if err != nil {
+		return &OrderSignature{Status: SIGNATURE_INVALID, Reason: err.Error()}
+	}
+	signed := crypto.Keccak256Hash([]byte(fmt.Sprintf("\x19Ethereum Signed Message:\n%d", len(packed))), packed.Bytes())
+	if strings.EqualFold(packed.Hex(), orderHash) || strings.EqualFold(signed.Hex(), orderHash) {
+		return recoverSigner(signed, joinSignature(v, r, s), order.Maker)
+	}
+
+	structHash, err := wyvernStructHash(order)
+	if err != nil {
+		return &OrderSignature{Status: SIGNATURE_INVALID, Reason: err.Error()}
+	}
+	// without the chain only the struct hash can be matched, and the signature, covering the chain, not checked
+	if chainId == nil {
+		if !strings.EqualFold(structHash.Hex(), orderHash) {
+			return &OrderSignature{Status: SIGNATURE_INVALID, Reason: "order hash does not match the order"}
+		}
+		return &OrderSignature{Status: SIGNATURE_UNVERIFIED, Reason: "unknown chain id"}
+	}
+	digest := typedDataHash(domainSeparator("Wyvern Exchange Contract", wyvernEip712Version, chainId, order.Exchange), structHash)
+	if !strings.EqualFold(structHash.Hex(), orderHash) && !strings.EqualFold(digest.Hex(), orderHash) {
+		return &OrderSignature{Status: SIGNATURE_INVALID, Reason: "order hash does not match the order"}
+	}
+	return recoverSigner(digest, joinSignature(v, r, s), order.Maker)
+}
+
+// VerifySeaportOrder check orderHash is the hash of order on the seaport deployment at protocolAddress on chainId,
+// and that signature, 65 bytes or 64 in the compact form, is the offerer's
+func VerifySeaportOrder(chainId *big.Int, protocolAddress string, order SeaportOrder, orderHash, signature string) *OrderSignature {
+	structHash, err := seaportStructHash(order)
+	if err != nil {
+		return &OrderSignature{Status: SIGNATURE_INVALID, Reason: err.Error()}
+	}
+	// the order hash seaport reports is the struct hash, the same on every chain and deployment,
+	// the signature covers the typed data hash
+	if !strings.EqualFold(structHash.Hex(), orderHash) {
+		return &OrderSignature{Status: SIGNATURE_INVALID, Reason: "order hash does not match the order"}
+	}
+	version, ok := seaportVersions[strings.ToLower(protocolAddress)]
+	if !ok {
+		return &OrderSignature{Status: SIGNATURE_UNVERIFIED, Reason: "unknown seaport deployment " + protocolAddress}
+	}
+	if chainId == nil {
+		return &OrderSignature{Status: SIGNATURE_UNVERIFIED, Reason: "unknown chain id"}
+	}
+	if signature == "" {
+		return &OrderSignature{Status: SIGNATURE_UNVERIFIED, Reason: "no signature"}
+	}
+	sig, err := hexutil.Decode(signature)
+	if err != nil {
+		return &OrderSignature{Status: SIGNATURE_INVALID, Reason: "malformed signature"}
+	}
+	if len(sig) > 65 {
+		return &OrderSignature{Status: SIGNATURE_UNVERIFIED, Reason: "bulk order signature"}
+	}
+	digest := typedDataHash(domainSeparator("Seaport", version, chainId, protocolAddress), structHash)
+	return recoverSigner(digest, sig, order.Offerer)
+}
+
+// recoverSigner compare the signer of hash with maker, sig is r || s || v or the compact r || yParityAndS
+func recoverSigner(hash common.Hash, sig []byte, maker string) *OrderSignature {
+	switch len(sig) {
+	case 64:
+		vs := new(big.Int).SetBytes(sig[32:])
+		v := byte(vs.Bit(255))
+		s := vs.SetBit(vs, 255, 0)
+		sig = append(append(append([]byte{}, sig[:32]...), common.LeftPadBytes(s.Bytes(), 32)...), v)
+	case 65:
+		sig = append([]byte{}, sig...)
+		if sig[64] >= 27 {
+			sig[64] -= 27
+		}
+	default:
+		return &OrderSignature{Status: SIGNATURE_INVALID, Reason: "malformed signature"}
+	}
+	pub, err := crypto.SigToPub(hash.Bytes(), sig)
+	if err != nil {
+		return &OrderSignature{Status: SIGNATURE_INVALID, Reason: "signature does not recover: " + err.Error()}
+	}
+	signer := strings.ToLower(crypto.PubkeyToAddress(*pub).Hex())
+	if signer != strings.ToLower(maker) {
+		return &OrderSignature{Status: SIGNATURE_INVALID, Signer: signer, Reason: "signed by " + signer + ", not the maker"}
+	}
+	return &OrderSignature{Status: SIGNATURE_VALID, Signer: signer}
+}
+
+// joinSignature r || s || v of a signature given in parts, nil when they are malformed
+func joinSignature(v int, r, s string) []byte {
+	rb, err := hexutil.Decode(r)
+	if err != nil || len(rb) > 32 {
+		return nil
+	}
+	sb, err := hexutil.Decode(s)
+	if err != nil || len(sb) > 32 {
+		return nil
+	}
+	return append(append(common.LeftPadBytes(rb, 32), common.LeftPadBytes(sb, 32)...), byte(v))
+}
+
+func domainSeparator(name, version string, chainId *big.Int, verifyingContract string) common.Hash {
+	return crypto.Keccak256Hash(
+		eip712DomainTypeHash.Bytes(),
+		crypto.Keccak256([]byte(name)),
+		crypto.Keccak256([]byte(version)),
+		common.LeftPadBytes(chainId.Bytes(), 32),
+		common.LeftPadBytes(common.HexToAddress(verifyingContract).Bytes(), 32),
+	)
+}
+
+func typedDataHash(domainSeparator, structHash common.Hash) common.Hash {
+	return crypto.Keccak256Hash([]byte("\x19\x01"), domainSeparator.Bytes(), structHash.Bytes())
+}
+
+// wyvernPackedHash the hash of an order as exchanges before typed data computed it, its fields tightly packed
+func wyvernPackedHash(order WyvernOrder) (common.Hash, error) {
+	var (
+		e   encoder
+		buf []byte
+	)
+	address := func(v string) { buf = append(buf, common.HexToAddress(v).Bytes()...) }
+	uint256 := func(v string) { buf = append(buf, e.uint256(v)...) }
+	raw := func(v string) { buf = append(buf, e.bytes(v)...) }
+
+	address(order.Exchange)
+	address(order.Maker)
+	address(order.Taker)
+	uint256(order.MakerRelayerFee)
+	uint256(order.TakerRelayerFee)
+	uint256(order.MakerProtocolFee)
+	uint256(order.TakerProtocolFee)
+	address(order.FeeRecipient)
+	buf = append(buf, order.FeeMethod, order.Side, order.SaleKind)
+	address(order.Target)
+	buf = append(buf, order.HowToCall)
+	raw(order.Calldata)
+	raw(order.ReplacementPattern)
+	address(order.StaticTarget)
+	raw(order.StaticExtradata)
+	address(order.PaymentToken)
+	uint256(order.BasePrice)
+	uint256(order.Extra)
+	uint256(order.ListingTime)
+	uint256(order.ExpirationTime)
+	uint256(order.Salt)
+	return crypto.Keccak256Hash(buf), e.err
+}
+
+func wyvernStructHash(order WyvernOrder) (common.Hash, error) {
+	var e encoder
+	hash := crypto.Keccak256Hash(
+		wyvernOrderTypeHash.Bytes(),
+		e.address(order.Exchange),
+		e.address(order.Maker),
+		e.address(order.Taker),
+		e.uint256(order.MakerRelayerFee),
+		e.uint256(order.TakerRelayerFee),
+		e.uint256(order.MakerProtocolFee),
+		e.uint256(order.TakerProtocolFee),
+		e.address(order.FeeRecipient),
+		e.uint8(order.FeeMethod),
+		e.uint8(order.Side),
+		e.uint8(order.SaleKind),
+		e.address(order.Target),
+		e.uint8(order.HowToCall),
+		crypto.Keccak256(e.bytes(order.Calldata)),
+		crypto.Keccak256(e.bytes(order.ReplacementPattern)),
+		e.address(order.StaticTarget),
+		crypto.Keccak256(e.bytes(order.StaticExtradata)),
+		e.address(order.PaymentToken),
+		e.uint256(order.BasePrice),
+		e.uint256(order.Extra),
+		e.uint256(order.ListingTime),
+		e.uint256(order.ExpirationTime),
+		e.uint256(order.Salt),
+		e.uint256(order.Nonce),
+	)
+	return hash, e.err
+}
+
+func seaportStructHash(order SeaportOrder) (common.Hash, error) {
+	var (
+		e             encoder
+		offer         []byte
+		consideration []byte
+	)
+	for _, v := range order.Offer {
+		offer = append(offer, crypto.Keccak256(
+			seaportOfferItemTypeHash.Bytes(),
+			e.uint256(v.ItemType.String()),
+			e.address(v.Token),
+			e.uint256(v.IdentifierOrCriteria),
+			e.uint256(v.StartAmount),
+			e.uint256(v.EndAmount),
+		)...)
+	}
+	for _, v := range order.Consideration {
+		consideration = append(consideration, crypto.Keccak256(
+			seaportConsiderationItemTypeHash.Bytes(),
+			e.uint256(v.ItemType.String()),
+			e.address(v.Token),
+			e.uint256(v.IdentifierOrCriteria),
+			e.uint256(v.StartAmount),
+			e.uint256(v.EndAmount),
+			e.address(v.Recipient),
+		)...)
+	}
+	hash := crypto.Keccak256Hash(
+		seaportOrderTypeHash.Bytes(),
+		e.address(order.Offerer),
+		e.address(order.Zone),
+		crypto.Keccak256(offer),
+		crypto.Keccak256(consideration),
+		e.uint256(order.OrderType.String()),
+		e.uint256(order.StartTime.String()),
+		e.uint256(order.EndTime.String()),
+		e.bytes32(order.ZoneHash),
+		e.uint256(order.Salt),
+		e.bytes32(order.ConduitKey),
+		e.uint256(order.Counter.String()),
+	)
+	return hash, e.err
+}
+
+// encoder abi encode the values of a struct being hashed, keeping the first malformed one
+type encoder struct {
+	err error
+}
+
+func (e *encoder) fail(kind, value string) {
+	if e.err == nil {
+		e.err = fmt.Errorf("malformed %s %q", kind, value)
+	}
+}
+
+func (e *encoder) address(v string) []byte {
+	if v != "" && !common.IsHexAddress(v) {
+		e.fail("address", v)
+	}
+	return common.LeftPadBytes(common.HexToAddress(v).Bytes(), 32)
+}
+
+// uint256 a decimal or 0x prefixed hex number, empty is 0
+func (e *encoder) uint256(v string) []byte {
+	n, base := new(big.Int), 10
+	if strings.HasPrefix(v, "0x") {
+		v, base = v[2:], 16
+	}
+	if v != "" {
+		if _, ok := n.SetString(v, base); !ok || n.Sign() < 0 || n.BitLen() > 256 {
+			e.fail("uint256", v)
+			return make([]byte, 32)
+		}
+	}
+	return common.LeftPadBytes(n.Bytes(), 32)
+}
+
+func (e *encoder) uint8(v uint8) []byte {
+	return common.LeftPadBytes([]byte{v}, 32)
+}
+
+func (e *encoder) bytes(v string) []byte {
+	if v == "" || v == "0x" {
+		return []byte{}
+	}
+	b, err := hexutil.Decode(v)
+	if err != nil {
+		e.fail("bytes", v)
+	}
+	return b
+}
+
+func (e *encoder) bytes32(v string) []byte {
+	b := e.bytes(v)
+	if len(b) > 32 {
+		e.fail("bytes32", v)
+		return make([]byte, 32)
+	}
+	return common.LeftPadBytes(b, 32)
+}
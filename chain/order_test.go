@@ -0,0 +1,260 @@
+package chain
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// testKey the key of the web3.js documentation, signing as testSigner
+const (
+	testKey    = "4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"
+	testSigner = "0x2c7536e3605d9c16a7a3d7b1898e529396a65c23"
+)
+
+func signingKey(t *testing.T) *ecdsa.PrivateKey {
+	key, err := crypto.HexToECDSA(testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if address := strings.ToLower(crypto.PubkeyToAddress(key.PublicKey).Hex()); address != testSigner {
+		t.Fatalf("test key signs as %s, want %s", address, testSigner)
+	}
+	return key
+}
+
+// sign hash with key, r || s || v with v 27 or 28 as wallets return it
+func sign(t *testing.T, key *ecdsa.PrivateKey, hash common.Hash) []byte {
+	sig, err := crypto.Sign(hash.Bytes(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig[64] += 27
+	return sig
+}
+
+func TestTypeHashes(t *testing.T) {
+	tests := []struct {
+		name string
+		got  common.Hash
+		want string
+	}{
+		{"EIP712Domain", eip712DomainTypeHash, "0x8b73c3c69bb8fe3d512ecc4cf759cc79239f7b179b0ffacaa9a75d522b39400f"},
+		{"seaport OrderComponents", seaportOrderTypeHash, "0xfa445660b7e21515a59617fcd68910b487aa5808b8abda3d78bc85df364b2c2f"},
+		{"seaport OfferItem", seaportOfferItemTypeHash, "0xa66999307ad1bb4fde44d13a5d710bd7718e0c87c1eef68a571629fbf5b93d02"},
+		{"seaport ConsiderationItem", seaportConsiderationItemTypeHash, "0x42d81c6929ffdc4eb27a0808e40e82516ad42296c166065de7f812492304ff6e"},
+		{"wyvern Order", wyvernOrderTypeHash, "0xdba08a88a748f356e8faf8578488343eab21b1741728779c9dcfdc782bc800f8"},
+	}
+	for _, tt := range tests {
+		if tt.got.Hex() != tt.want {
+			t.Errorf("%s type hash %s, want %s", tt.name, tt.got.Hex(), tt.want)
+		}
+	}
+}
+
+// TestTypedDataHash the example of EIP-712, a Mail from Cow to Bob signed with the key keccak256("cow")
+func TestTypedDataHash(t *testing.T) {
+	domain := domainSeparator("Ether Mail", "1", big.NewInt(1), "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC")
+	if want := "0xf2cee375fa42b42143804025fc449deafd50cc031ca257e0b194a650a912090f"; domain.Hex() != want {
+		t.Fatalf("domain separator %s, want %s", domain.Hex(), want)
+	}
+	mail := common.HexToHash("0xc52c0ee5d84264471806290a3f2c4cecfc5490626bf912d01f240d7a274b371e")
+	digest := typedDataHash(domain, mail)
+	if want := "0xbe609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2"; digest.Hex() != want {
+		t.Fatalf("typed data hash %s, want %s", digest.Hex(), want)
+	}
+
+	sig := joinSignature(28, "0x4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d",
+		"0x07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b91562")
+	cow := "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"
+	if got := recoverSigner(digest, sig, cow); got.Status != SIGNATURE_VALID || got.Signer != strings.ToLower(cow) {
+		t.Fatalf("signature recovered as %+v, want valid by %s", got, cow)
+	}
+}
+
+func TestSeaportDomainSeparator(t *testing.T) {
+	// DOMAIN_SEPARATOR of seaport 1.1 on ethereum mainnet
+	got := domainSeparator("Seaport", "1.1", big.NewInt(1), "0x00000000006c3852cbef3e08e8df289169ede581")
+	if want := "0xb50c8913581289bd2e066aeef89fceb9615d490d673131fd1a7047436706834e"; got.Hex() != want {
+		t.Fatalf("domain separator %s, want %s", got.Hex(), want)
+	}
+}
+
+func TestRecoverSigner(t *testing.T) {
+	key := signingKey(t)
+	hash := crypto.Keccak256Hash([]byte("openseasync"))
+	sig := sign(t, key, hash)
+
+	if got := recoverSigner(hash, sig, common.HexToAddress(testSigner).Hex()); got.Status != SIGNATURE_VALID || got.Signer != testSigner {
+		t.Fatalf("65 byte signature recovered as %+v", got)
+	}
+
+	// the compact form folds v into the top bit of s
+	vs := new(big.Int).SetBytes(sig[32:64])
+	if sig[64] == 28 {
+		vs.SetBit(vs, 255, 1)
+	}
+	compact := append(append([]byte{}, sig[:32]...), common.LeftPadBytes(vs.Bytes(), 32)...)
+	if got := recoverSigner(hash, compact, testSigner); got.Status != SIGNATURE_VALID || got.Signer != testSigner {
+		t.Fatalf("64 byte signature recovered as %+v", got)
+	}
+
+	if got := recoverSigner(hash, sig, "0x2222222222222222222222222222222222222222"); got.Status != SIGNATURE_INVALID || got.Signer != testSigner {
+		t.Fatalf("a signature by someone else than the maker is %+v", got)
+	}
+	if got := recoverSigner(hash, sig[:63], testSigner); got.Status != SIGNATURE_INVALID {
+		t.Fatalf("a truncated signature is %+v", got)
+	}
+}
+
+func testSeaportOrder(t *testing.T) SeaportOrder {
+	var order SeaportOrder
+	err := json.Unmarshal([]byte(`{
+		"offerer": "`+testSigner+`",
+		"zone": "0x004c00500000ad104d7dbd00e3ae0a5c00560c00",
+		"offer": [{"itemType": 2, "token": "0x00000000000000000000000000000000000c0de1", "identifierOrCriteria": "42",
+			"startAmount": "1", "endAmount": "1"}],
+		"consideration": [{"itemType": 0, "token": "0x0000000000000000000000000000000000000000", "identifierOrCriteria": "0",
+			"startAmount": "975000000000000000", "endAmount": "975000000000000000", "recipient": "`+testSigner+`"},
+			{"itemType": 0, "token": "0x0000000000000000000000000000000000000000", "identifierOrCriteria": "0",
+			"startAmount": "25000000000000000", "endAmount": "25000000000000000",
+			"recipient": "0x0000a26b00c1f0df003000390027140000faa719"}],
+		"orderType": 2,
+		"startTime": "1660000000",
+		"endTime": "1670000000",
+		"zoneHash": "0x0000000000000000000000000000000000000000000000000000000000000000",
+		"salt": "0x360c6ebe",
+		"conduitKey": "0x0000007b02230091a7ed01230072f7006a004d60a8d4e71d599b8104250f0000",
+		"counter": 0
+	}`), &order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return order
+}
+
+func TestVerifySeaportOrder(t *testing.T) {
+	key := signingKey(t)
+	exchange := "0x00000000006c3852CbEf3e08E8dF289169EdE581"
+	order := testSeaportOrder(t)
+	structHash, err := seaportStructHash(order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := typedDataHash(domainSeparator("Seaport", "1.1", big.NewInt(1), exchange), structHash)
+	signature := hexutil.Encode(sign(t, key, digest))
+
+	tests := []struct {
+		name      string
+		chainId   *big.Int
+		exchange  string
+		orderHash string
+		signature string
+		status    string
+	}{
+		{"signed by the offerer", big.NewInt(1), exchange, structHash.Hex(), signature, SIGNATURE_VALID},
+		{"signed for another chain", big.NewInt(5), exchange, structHash.Hex(), signature, SIGNATURE_INVALID},
+		{"hash of another order", big.NewInt(1), exchange, digest.Hex(), signature, SIGNATURE_INVALID},
+		{"hash mismatch on an unknown chain", nil, exchange, digest.Hex(), signature, SIGNATURE_INVALID},
+		{"hash mismatch on an unknown deployment", big.NewInt(1), "0x0000000000000000000000000000000000000bad", digest.Hex(), signature, SIGNATURE_INVALID},
+		{"unknown chain", nil, exchange, structHash.Hex(), signature, SIGNATURE_UNVERIFIED},
+		{"unknown deployment", big.NewInt(1), "0x0000000000000000000000000000000000000bad", structHash.Hex(), signature, SIGNATURE_UNVERIFIED},
+		{"bulk signature", big.NewInt(1), exchange, structHash.Hex(), signature + strings.Repeat("00", 35), SIGNATURE_UNVERIFIED},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := VerifySeaportOrder(tt.chainId, tt.exchange, order, tt.orderHash, tt.signature)
+			if got.Status != tt.status {
+				t.Fatalf("status %s (%s), want %s", got.Status, got.Reason, tt.status)
+			}
+		})
+	}
+}
+
+func testWyvernOrder() WyvernOrder {
+	return WyvernOrder{
+		Exchange:           "0x7f268357a8c2552623316e2562d90e642bb538e5",
+		Maker:              testSigner,
+		Taker:              "0x0000000000000000000000000000000000000000",
+		MakerRelayerFee:    "250",
+		TakerRelayerFee:    "0",
+		MakerProtocolFee:   "0",
+		TakerProtocolFee:   "0",
+		FeeRecipient:       "0x5b3256965e7c3cf26e11fcaf296dfc8807c01073",
+		FeeMethod:          1,
+		Side:               1,
+		SaleKind:           0,
+		Target:             "0xbaf2127b49fc93cbca6269fade0f7f31df4c88a7",
+		HowToCall:          1,
+		Calldata:           "0xfb16a595",
+		ReplacementPattern: "0x000000ff",
+		StaticTarget:       "0x0000000000000000000000000000000000000000",
+		StaticExtradata:    "0x",
+		PaymentToken:       "0x0000000000000000000000000000000000000000",
+		BasePrice:          "1000000000000000000",
+		Extra:              "0",
+		ListingTime:        "1640000000",
+		ExpirationTime:     "0",
+		Salt:               "12345678901234567890",
+		Nonce:              "0",
+	}
+}
+
+// splitSignature v, r and s of a 65 byte signature as opensea sends them for wyvern orders
+func splitSignature(sig []byte) (int, string, string) {
+	return int(sig[64]), hexutil.Encode(sig[:32]), hexutil.Encode(sig[32:64])
+}
+
+func TestVerifyWyvernOrder(t *testing.T) {
+	key := signingKey(t)
+	order := testWyvernOrder()
+
+	packed, err := wyvernPackedHash(order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	personal := crypto.Keccak256Hash([]byte("\x19Ethereum Signed Message:\n32"), packed.Bytes())
+	v, r, s := splitSignature(sign(t, key, personal))
+	if got := VerifyWyvernOrder(nil, order, packed.Hex(), v, r, s); got.Status != SIGNATURE_VALID || got.Signer != testSigner {
+		t.Fatalf("packed hash signed as a personal message is %+v", got)
+	}
+
+	structHash, err := wyvernStructHash(order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := typedDataHash(domainSeparator("Wyvern Exchange Contract", wyvernEip712Version, big.NewInt(1), order.Exchange), structHash)
+	v, r, s = splitSignature(sign(t, key, digest))
+
+	tests := []struct {
+		name      string
+		chainId   *big.Int
+		orderHash string
+		status    string
+	}{
+		{"typed data signed by the maker", big.NewInt(1), structHash.Hex(), SIGNATURE_VALID},
+		{"typed data hash reported", big.NewInt(1), digest.Hex(), SIGNATURE_VALID},
+		{"signed for another chain", big.NewInt(5), structHash.Hex(), SIGNATURE_INVALID},
+		{"hash of another order", big.NewInt(1), crypto.Keccak256Hash([]byte("other")).Hex(), SIGNATURE_INVALID},
+		{"hash mismatch on an unknown chain", nil, crypto.Keccak256Hash([]byte("other")).Hex(), SIGNATURE_INVALID},
+		{"unknown chain", nil, structHash.Hex(), SIGNATURE_UNVERIFIED},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := VerifyWyvernOrder(tt.chainId, order, tt.orderHash, v, r, s)
+			if got.Status != tt.status {
+				t.Fatalf("status %s (%s), want %s", got.Status, got.Reason, tt.status)
+			}
+		})
+	}
+
+	if got := VerifyWyvernOrder(big.NewInt(1), order, structHash.Hex(), 0, "", ""); got.Status != SIGNATURE_UNVERIFIED {
+		t.Fatalf("an order without signature is %+v", got)
+	}
+}
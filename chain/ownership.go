@@ -0,0 +1,98 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	SCHEMA_ERC721  = "ERC721"
+	SCHEMA_ERC1155 = "ERC1155"
+
+	erc721OwnerOfABI    = `[{"constant":true,"inputs":[{"name":"tokenId","type":"uint256"}],"name":"ownerOf","outputs":[{"name":"","type":"address"}],"type":"function"}]`
+	erc1155BalanceOfABI = `[{"constant":true,"inputs":[{"name":"account","type":"address"},{"name":"id","type":"uint256"}],"name":"balanceOf","outputs":[{"name":"","type":"uint256"}],"type":"function"}]`
+)
+
+var (
+	erc721ABI  abi.ABI
+	erc1155ABI abi.ABI
+)
+
+func init() {
+	var err error
+	if erc721ABI, err = abi.JSON(strings.NewReader(erc721OwnerOfABI)); err != nil {
+		panic(err)
+	}
+	if erc1155ABI, err = abi.JSON(strings.NewReader(erc1155BalanceOfABI)); err != nil {
+		panic(err)
+	}
+}
+
+// Ownership what the chain says about a token held by a wallet
+type Ownership struct {
+	Owner   string   // ERC721 owner, lower case
+	Balance *big.Int // number of copies held by the wallet
+}
+
+// OwnerOf call ERC721 ownerOf on contract
+func OwnerOf(ctx context.Context, caller bind.ContractCaller, contract, tokenId string) (string, error) {
+	id, ok := new(big.Int).SetString(tokenId, 10)
+	if !ok {
+		return "", fmt.Errorf("invalid token id %s", tokenId)
+	}
+	var out []interface{}
+	c := bind.NewBoundContract(common.HexToAddress(contract), erc721ABI, caller, nil, nil)
+	if err := c.Call(&bind.CallOpts{Context: ctx}, &out, "ownerOf", id); err != nil {
+		return "", err
+	}
+	owner := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+	return strings.ToLower(owner.Hex()), nil
+}
+
+// BalanceOf call ERC1155 balanceOf on contract
+func BalanceOf(ctx context.Context, caller bind.ContractCaller, contract, account, tokenId string) (*big.Int, error) {
+	id, ok := new(big.Int).SetString(tokenId, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid token id %s", tokenId)
+	}
+	var out []interface{}
+	c := bind.NewBoundContract(common.HexToAddress(contract), erc1155ABI, caller, nil, nil)
+	if err := c.Call(&bind.CallOpts{Context: ctx}, &out, "balanceOf", common.HexToAddress(account), id); err != nil {
+		return nil, err
+	}
+	return *abi.ConvertType(out[0], new(*big.Int)).(**big.Int), nil
+}
+
+// VerifyOwnership read the ownership of tokenId held by user according to the contract schema
+func VerifyOwnership(ctx context.Context, caller bind.ContractCaller, schemaName, contract, tokenId, user string) (*Ownership, error) {
+	switch strings.ToUpper(schemaName) {
+	case SCHEMA_ERC721:
+		owner, err := OwnerOf(ctx, caller, contract, tokenId)
+		if err != nil {
+			return nil, err
+		}
+		ownership := &Ownership{Owner: owner, Balance: big.NewInt(0)}
+		if owner == strings.ToLower(user) {
+			ownership.Balance.SetInt64(1)
+		}
+		return ownership, nil
+	case SCHEMA_ERC1155:
+		balance, err := BalanceOf(ctx, caller, contract, user, tokenId)
+		if err != nil {
+			return nil, err
+		}
+		ownership := &Ownership{Balance: balance}
+		if balance.Sign() > 0 {
+			ownership.Owner = strings.ToLower(user)
+		}
+		return ownership, nil
+	}
+	return nil, errors.New("unsupported contract schema " + schemaName)
+}
@@ -0,0 +1,105 @@
+package chain
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+)
+
+var (
+	testContract = common.HexToAddress("0x00000000000000000000000000000000000c0de1")
+	testOwner    = common.HexToAddress("0x1111111111111111111111111111111111111111")
+	testOther    = common.HexToAddress("0x2222222222222222222222222222222222222222")
+)
+
+// returnWord runtime code answering every call with word
+func returnWord(word common.Hash) []byte {
+	code := append([]byte{0x7f}, word.Bytes()...)                       // PUSH32 word
+	return append(code, 0x60, 0x00, 0x52, 0x60, 0x20, 0x60, 0x00, 0xf3) // MSTORE at 0, RETURN 32 bytes
+}
+
+// newSimulatedBackend a simulated chain with code deployed at the addresses of contracts
+func newSimulatedBackend(t *testing.T, contracts map[common.Address][]byte) *backends.SimulatedBackend {
+	alloc := core.GenesisAlloc{}
+	for address, code := range contracts {
+		alloc[address] = core.GenesisAccount{Code: code, Balance: big.NewInt(0)}
+	}
+	backend := backends.NewSimulatedBackend(alloc, 8000000)
+	t.Cleanup(func() { backend.Close() })
+	return backend
+}
+
+func TestOwnerOf(t *testing.T) {
+	backend := newSimulatedBackend(t, map[common.Address][]byte{testContract: returnWord(testOwner.Hash())})
+
+	owner, err := OwnerOf(context.Background(), backend, testContract.Hex(), "42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if owner != strings.ToLower(testOwner.Hex()) {
+		t.Fatalf("owner %s, want %s", owner, strings.ToLower(testOwner.Hex()))
+	}
+	if _, err := OwnerOf(context.Background(), backend, testContract.Hex(), "0x2a"); err == nil {
+		t.Fatal("a token id that is not decimal was accepted")
+	}
+}
+
+func TestBalanceOf(t *testing.T) {
+	backend := newSimulatedBackend(t, map[common.Address][]byte{testContract: returnWord(common.BigToHash(big.NewInt(3)))})
+
+	balance, err := BalanceOf(context.Background(), backend, testContract.Hex(), testOwner.Hex(), "7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance.Cmp(big.NewInt(3)) != 0 {
+		t.Fatalf("balance %s, want 3", balance)
+	}
+}
+
+func TestVerifyOwnership(t *testing.T) {
+	erc721 := common.HexToAddress("0x0000000000000000000000000000000000000721")
+	erc1155 := common.HexToAddress("0x0000000000000000000000000000000000001155")
+	empty := common.HexToAddress("0x0000000000000000000000000000000000000e11")
+	backend := newSimulatedBackend(t, map[common.Address][]byte{
+		erc721:  returnWord(testOwner.Hash()),
+		erc1155: returnWord(common.BigToHash(big.NewInt(5))),
+		empty:   returnWord(common.Hash{}),
+	})
+
+	tests := []struct {
+		name     string
+		schema   string
+		contract common.Address
+		user     common.Address
+		owner    string
+		balance  int64
+	}{
+		{"erc721 held", SCHEMA_ERC721, erc721, testOwner, strings.ToLower(testOwner.Hex()), 1},
+		{"erc721 held by another", SCHEMA_ERC721, erc721, testOther, strings.ToLower(testOwner.Hex()), 0},
+		{"erc1155 copies", "erc1155", erc1155, testOther, strings.ToLower(testOther.Hex()), 5},
+		{"erc1155 none", SCHEMA_ERC1155, empty, testOther, "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ownership, err := VerifyOwnership(context.Background(), backend, tt.schema, tt.contract.Hex(), "1", tt.user.Hex())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ownership.Owner != tt.owner {
+				t.Errorf("owner %q, want %q", ownership.Owner, tt.owner)
+			}
+			if ownership.Balance.Cmp(big.NewInt(tt.balance)) != 0 {
+				t.Errorf("balance %s, want %d", ownership.Balance, tt.balance)
+			}
+		})
+	}
+
+	if _, err := VerifyOwnership(context.Background(), backend, "CRYPTOPUNKS", erc721.Hex(), "1", testOwner.Hex()); err == nil {
+		t.Fatal("an unsupported schema was verified")
+	}
+}
@@ -0,0 +1,173 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const erc1155TransferABI = `[
+{"anonymous":false,"inputs":[{"indexed":true,"name":"operator","type":"address"},{"indexed":true,"name":"from","type":"address"},{"indexed":true,"name":"to","type":"address"},{"indexed":false,"name":"id","type":"uint256"},{"indexed":false,"name":"value","type":"uint256"}],"name":"TransferSingle","type":"event"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"operator","type":"address"},{"indexed":true,"name":"from","type":"address"},{"indexed":true,"name":"to","type":"address"},{"indexed":false,"name":"ids","type":"uint256[]"},{"indexed":false,"name":"values","type":"uint256[]"}],"name":"TransferBatch","type":"event"}
+]`
+
+var (
+	TransferTopic       = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+	TransferSingleTopic = crypto.Keccak256Hash([]byte("TransferSingle(address,address,address,uint256,uint256)"))
+	TransferBatchTopic  = crypto.Keccak256Hash([]byte("TransferBatch(address,address,address,uint256[],uint256[])"))
+
+	erc1155EventABI abi.ABI
+)
+
+func init() {
+	var err error
+	if erc1155EventABI, err = abi.JSON(strings.NewReader(erc1155TransferABI)); err != nil {
+		panic(err)
+	}
+}
+
+// Backend what the indexer needs from a node, satisfied by ethclient.Client and the simulated backend
+type Backend interface {
+	ethereum.LogFilterer
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// Transfer one token moved by an ERC721 or ERC1155 transfer log
+type Transfer struct {
+	ContractAddress string
+	From            string
+	To              string
+	TokenId         string
+	Quantity        string
+	BlockNumber     uint64
+	BlockHash       string
+	BlockTime       int64 // milliseconds
+	TxHash          string
+	TxIndex         uint
+	LogIndex        uint
+}
+
+// ParseTransferLog decode a Transfer, TransferSingle or TransferBatch log.
+// ERC20 Transfer logs, which have no indexed token id, return nothing.
+func ParseTransferLog(log types.Log) ([]Transfer, error) {
+	if len(log.Topics) == 0 {
+		return nil, nil
+	}
+	base := Transfer{
+		ContractAddress: strings.ToLower(log.Address.Hex()),
+		BlockNumber:     log.BlockNumber,
+		BlockHash:       log.BlockHash.Hex(),
+		TxHash:          log.TxHash.Hex(),
+		TxIndex:         log.TxIndex,
+		LogIndex:        log.Index,
+	}
+
+	switch log.Topics[0] {
+	case TransferTopic:
+		if len(log.Topics) != 4 {
+			return nil, nil
+		}
+		base.From = topicAddress(log.Topics[1])
+		base.To = topicAddress(log.Topics[2])
+		base.TokenId = log.Topics[3].Big().String()
+		base.Quantity = "1"
+		return []Transfer{base}, nil
+	case TransferSingleTopic:
+		if len(log.Topics) != 4 {
+			return nil, errors.New("malformed TransferSingle log in tx " + base.TxHash)
+		}
+		var event struct {
+			Id    *big.Int
+			Value *big.Int
+		}
+		if err := erc1155EventABI.UnpackIntoInterface(&event, "TransferSingle", log.Data); err != nil {
+			return nil, err
+		}
+		base.From = topicAddress(log.Topics[2])
+		base.To = topicAddress(log.Topics[3])
+		base.TokenId = event.Id.String()
+		base.Quantity = event.Value.String()
+		return []Transfer{base}, nil
+	case TransferBatchTopic:
+		if len(log.Topics) != 4 {
+			return nil, errors.New("malformed TransferBatch log in tx " + base.TxHash)
+		}
+		var event struct {
+			Ids    []*big.Int
+			Values []*big.Int
+		}
+		if err := erc1155EventABI.UnpackIntoInterface(&event, "TransferBatch", log.Data); err != nil {
+			return nil, err
+		}
+		if len(event.Ids) != len(event.Values) {
+			return nil, errors.New("TransferBatch ids and values differ in length in tx " + base.TxHash)
+		}
+		base.From = topicAddress(log.Topics[2])
+		base.To = topicAddress(log.Topics[3])
+		transfers := make([]Transfer, 0, len(event.Ids))
+		for i := range event.Ids {
+			transfer := base
+			transfer.TokenId = event.Ids[i].String()
+			transfer.Quantity = event.Values[i].String()
+			transfers = append(transfers, transfer)
+		}
+		return transfers, nil
+	}
+	return nil, nil
+}
+
+// FetchTransfers get the token transfers of contracts between blocks from and to, both included
+func FetchTransfers(ctx context.Context, backend Backend, contracts []common.Address, from, to uint64) ([]Transfer, error) {
+	query := ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(from),
+		ToBlock:   new(big.Int).SetUint64(to),
+		Addresses: contracts,
+		Topics:    [][]common.Hash{{TransferTopic, TransferSingleTopic, TransferBatchTopic}},
+	}
+	logs, err := backend.FilterLogs(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		transfers  []Transfer
+		blockTimes = make(map[uint64]int64)
+	)
+	for _, log := range logs {
+		if log.Removed {
+			continue
+		}
+		parsed, err := ParseTransferLog(log)
+		if err != nil {
+			return nil, err
+		}
+		if len(parsed) == 0 {
+			continue
+		}
+		blockTime, ok := blockTimes[log.BlockNumber]
+		if !ok {
+			header, err := backend.HeaderByNumber(ctx, new(big.Int).SetUint64(log.BlockNumber))
+			if err != nil {
+				return nil, err
+			}
+			blockTime = int64(header.Time) * 1000
+			blockTimes[log.BlockNumber] = blockTime
+		}
+		for i := range parsed {
+			parsed[i].BlockTime = blockTime
+		}
+		transfers = append(transfers, parsed...)
+	}
+	return transfers, nil
+}
+
+func topicAddress(topic common.Hash) string {
+	return strings.ToLower(common.BytesToAddress(topic.Bytes()).Hex())
+}
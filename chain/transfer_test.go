@@ -0,0 +1,145 @@
+package chain
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// emitterCode runtime code logging the first four calldata words as topics and the rest as data
+var emitterCode = []byte{
+	0x60, 0x60, 0x35, 0x60, 0x40, 0x35, 0x60, 0x20, 0x35, 0x60, 0x00, 0x35, // CALLDATALOAD the topics
+	0x60, 0x80, 0x36, 0x03, 0x80, 0x60, 0x80, 0x60, 0x00, 0x37, // CALLDATACOPY the data to memory
+	0x60, 0x00, 0xa4, 0x00, // LOG4, STOP
+}
+
+// emitter sends the logs of a test to the emitter contracts of a simulated chain
+type emitter struct {
+	t       *testing.T
+	backend *backends.SimulatedBackend
+	opts    *bind.TransactOpts
+}
+
+func newEmitter(t *testing.T, contracts ...common.Address) *emitter {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	if err != nil {
+		t.Fatal(err)
+	}
+	alloc := core.GenesisAlloc{opts.From: {Balance: new(big.Int).Lsh(big.NewInt(1), 100)}}
+	for _, contract := range contracts {
+		alloc[contract] = core.GenesisAccount{Code: emitterCode, Balance: big.NewInt(0)}
+	}
+	backend := backends.NewSimulatedBackend(alloc, 8000000)
+	t.Cleanup(func() { backend.Close() })
+	return &emitter{t: t, backend: backend, opts: opts}
+}
+
+// emit log topics and data from contract in a new block
+func (e *emitter) emit(contract common.Address, topics [4]common.Hash, data []byte) {
+	var input []byte
+	for _, topic := range topics {
+		input = append(input, topic.Bytes()...)
+	}
+	c := bind.NewBoundContract(contract, abi.ABI{}, e.backend, e.backend, e.backend)
+	if _, err := c.RawTransact(e.opts, append(input, data...)); err != nil {
+		e.t.Fatal(err)
+	}
+	e.backend.Commit()
+}
+
+func (e *emitter) head() uint64 {
+	header, err := e.backend.HeaderByNumber(context.Background(), nil)
+	if err != nil {
+		e.t.Fatal(err)
+	}
+	return header.Number.Uint64()
+}
+
+func packTransferData(t *testing.T, event string, values ...interface{}) []byte {
+	data, err := erc1155EventABI.Events[event].Inputs.NonIndexed().Pack(values...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func lower(address common.Address) string {
+	return strings.ToLower(address.Hex())
+}
+
+func TestFetchTransfers(t *testing.T) {
+	erc721 := common.HexToAddress("0x0000000000000000000000000000000000000721")
+	erc1155 := common.HexToAddress("0x0000000000000000000000000000000000001155")
+	ignored := common.HexToAddress("0x0000000000000000000000000000000000000bad")
+	e := newEmitter(t, erc721, erc1155, ignored)
+	from := e.head() + 1
+
+	e.emit(erc721, [4]common.Hash{TransferTopic, testOwner.Hash(), testOther.Hash(), common.BigToHash(big.NewInt(42))}, nil)
+	e.emit(erc1155, [4]common.Hash{TransferSingleTopic, testOwner.Hash(), testOwner.Hash(), testOther.Hash()},
+		packTransferData(t, "TransferSingle", big.NewInt(7), big.NewInt(3)))
+	e.emit(erc1155, [4]common.Hash{TransferBatchTopic, testOther.Hash(), testOther.Hash(), testOwner.Hash()},
+		packTransferData(t, "TransferBatch", []*big.Int{big.NewInt(8), big.NewInt(9)}, []*big.Int{big.NewInt(1), big.NewInt(2)}))
+	e.emit(ignored, [4]common.Hash{TransferTopic, testOwner.Hash(), testOther.Hash(), common.BigToHash(big.NewInt(1))}, nil)
+	e.emit(erc721, [4]common.Hash{crypto.Keccak256Hash([]byte("Approval(address,address,uint256)")), testOwner.Hash(),
+		testOther.Hash(), common.BigToHash(big.NewInt(42))}, nil)
+
+	transfers, err := FetchTransfers(context.Background(), e.backend, []common.Address{erc721, erc1155}, from, e.head())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Transfer{
+		{ContractAddress: lower(erc721), From: lower(testOwner), To: lower(testOther), TokenId: "42", Quantity: "1"},
+		{ContractAddress: lower(erc1155), From: lower(testOwner), To: lower(testOther), TokenId: "7", Quantity: "3"},
+		{ContractAddress: lower(erc1155), From: lower(testOther), To: lower(testOwner), TokenId: "8", Quantity: "1"},
+		{ContractAddress: lower(erc1155), From: lower(testOther), To: lower(testOwner), TokenId: "9", Quantity: "2"},
+	}
+	if len(transfers) != len(want) {
+		t.Fatalf("%d transfers, want %d: %+v", len(transfers), len(want), transfers)
+	}
+	for i, transfer := range transfers {
+		if transfer.ContractAddress != want[i].ContractAddress || transfer.From != want[i].From || transfer.To != want[i].To ||
+			transfer.TokenId != want[i].TokenId || transfer.Quantity != want[i].Quantity {
+			t.Errorf("transfer %d is %+v, want %+v", i, transfer, want[i])
+		}
+		header, err := e.backend.HeaderByNumber(context.Background(), new(big.Int).SetUint64(transfer.BlockNumber))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if transfer.BlockHash != header.Hash().Hex() || transfer.BlockTime != int64(header.Time)*1000 {
+			t.Errorf("transfer %d block %s at %d, want %s at %d", i, transfer.BlockHash, transfer.BlockTime,
+				header.Hash().Hex(), header.Time*1000)
+		}
+	}
+}
+
+func TestParseTransferLog(t *testing.T) {
+	erc20 := types.Log{Topics: []common.Hash{TransferTopic, testOwner.Hash(), testOther.Hash()},
+		Data: common.BigToHash(big.NewInt(100)).Bytes()}
+	if transfers, err := ParseTransferLog(erc20); err != nil || len(transfers) != 0 {
+		t.Fatalf("erc20 transfer parsed as %+v, %v", transfers, err)
+	}
+
+	malformed := types.Log{Topics: []common.Hash{TransferSingleTopic, testOwner.Hash()}}
+	if _, err := ParseTransferLog(malformed); err == nil {
+		t.Fatal("a TransferSingle log without its indexed addresses was parsed")
+	}
+
+	uneven := types.Log{Topics: []common.Hash{TransferBatchTopic, testOwner.Hash(), testOwner.Hash(), testOther.Hash()},
+		Data: packTransferData(t, "TransferBatch", []*big.Int{big.NewInt(1), big.NewInt(2)}, []*big.Int{big.NewInt(1)})}
+	if _, err := ParseTransferLog(uneven); err == nil {
+		t.Fatal("a TransferBatch log with more ids than values was parsed")
+	}
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"openseasync/chain"
+	"openseasync/config"
+	"openseasync/database"
+	"openseasync/logs"
+	"openseasync/models"
+	routers "openseasync/routers/common"
+	"openseasync/server"
+	"openseasync/syncer"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/sirupsen/logrus"
+)
+
+type command struct {
+	args int // positional arguments after the command name
+	run  func(ctx context.Context, opts options, args []string) error
+}
+
+var commands = map[string]command{
+	"sync wallet":     {args: 1, run: syncWallet},
+	"sync collection": {args: 1, run: syncCollection},
+	"refresh asset":   {args: 2, run: refreshAsset},
+	"migrate":         {args: 0, run: migrate},
+	"purge-deleted":   {args: 0, run: purgeDeleted},
+	"export wallet":   {args: 1, run: exportWallet},
+	"serve":           {args: 0, run: serve},
+}
+
+// connect load the configuration and open mongo, the returned func closes it
+func connect(opts options) (func(), error) {
+	config.InitConfig(opts.config)
+	if network := strings.ToLower(strings.TrimSpace(opts.chain)); network != "" && !chain.IsSupportedNetwork(network) {
+		return nil, fmt.Errorf("unsupported chain %s", network)
+	}
+	db := database.InitMongo()
+	return func() {
+		if err := db.Disconnect(context.TODO()); err != nil {
+			logs.GetLogger().Error(err)
+		}
+	}, nil
+}
+
+func syncWallet(ctx context.Context, opts options, args []string) error {
+	if !common.IsHexAddress(args[0]) {
+		return fmt.Errorf("%s is not a wallet address", args[0])
+	}
+	closeDb, err := connect(opts)
+	if err != nil {
+		return err
+	}
+	defer closeDb()
+
+	user, network := normalizeAddress(args[0]), chain.NormalizeNetwork(opts.chain)
+	ctx = logs.WithFields(ctx, logrus.Fields{"wallet": user, "chain": network})
+	if err := syncer.Wallet(ctx, network, user, models.SYNC_TRIGGER_CLI); err != nil {
+		return err
+	}
+	fmt.Printf("synced %s on %s\n", user, network)
+	return nil
+}
+
+func syncCollection(ctx context.Context, opts options, args []string) error {
+	closeDb, err := connect(opts)
+	if err != nil {
+		return err
+	}
+	defer closeDb()
+
+	network := chain.NormalizeNetwork(opts.chain)
+	synced, err := syncer.Collection(ctx, network, args[0], models.SYNC_TRIGGER_CLI)
+	printSynced(synced, network)
+	return err
+}
+
+func refreshAsset(ctx context.Context, opts options, args []string) error {
+	if !common.IsHexAddress(args[0]) {
+		return fmt.Errorf("%s is not a contract address", args[0])
+	}
+	closeDb, err := connect(opts)
+	if err != nil {
+		return err
+	}
+	defer closeDb()
+
+	network := chain.NormalizeNetwork(opts.chain)
+	synced, err := syncer.Asset(ctx, network, normalizeAddress(args[0]), args[1], models.SYNC_TRIGGER_CLI)
+	printSynced(synced, network)
+	return err
+}
+
+func printSynced(wallets []string, network string) {
+	if len(wallets) == 0 {
+		fmt.Println("no wallet synced")
+	}
+	for _, v := range wallets {
+		fmt.Printf("synced %s on %s\n", v, network)
+	}
+}
+
+func migrate(ctx context.Context, opts options, args []string) error {
+	closeDb, err := connect(opts)
+	if err != nil {
+		return err
+	}
+	defer closeDb()
+
+	if err := server.Migrate(); err != nil {
+		return err
+	}
+	fmt.Println("migrated")
+	return nil
+}
+
+// purgeDeleted every chain unless --chain is given, like the admin api
+func purgeDeleted(ctx context.Context, opts options, args []string) error {
+	age, err := parseAge(opts.olderThan)
+	if err != nil {
+		return err
+	}
+	closeDb, err := connect(opts)
+	if err != nil {
+		return err
+	}
+	defer closeDb()
+
+	deleted, err := models.PurgeDeleted(strings.ToLower(strings.TrimSpace(opts.chain)), time.Now().Add(-age).UnixMilli())
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(deleted))
+	for name := range deleted {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Printf("%s: %d purged\n", name, deleted[name])
+	}
+	return nil
+}
+
+// parseAge a number of days such as 30d, or a duration such as 720h
+func parseAge(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, fmt.Errorf("%w: --older-than is required", errUsage)
+	}
+	var (
+		age time.Duration
+		err error
+	)
+	if days := strings.TrimSuffix(value, "d"); days != value {
+		var n int64
+		n, err = strconv.ParseInt(days, 10, 64)
+		age = time.Duration(n) * 24 * time.Hour
+	} else {
+		age, err = time.ParseDuration(value)
+	}
+	if err != nil || age <= 0 {
+		return 0, fmt.Errorf("--older-than %s is not a positive age such as 30d or 720h", value)
+	}
+	return age, nil
+}
+
+// exportWallet every chain unless --chain is given, like the export routes
+func exportWallet(ctx context.Context, opts options, args []string) error {
+	if !common.IsHexAddress(args[0]) {
+		return fmt.Errorf("%s is not a wallet address", args[0])
+	}
+	if opts.format != routers.EXPORT_FORMAT_CSV && opts.format != routers.EXPORT_FORMAT_NDJSON {
+		return fmt.Errorf("--format %s is neither csv nor ndjson", opts.format)
+	}
+	closeDb, err := connect(opts)
+	if err != nil {
+		return err
+	}
+	defer closeDb()
+
+	var out io.Writer = os.Stdout
+	if opts.out != "" {
+		file, err := os.Create(opts.out)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	columns, export := models.ExportColumns, models.ExportWalletAssets
+	if opts.activity {
+		columns, export = models.ActivityExportColumns, models.ExportWalletActivity
+	}
+	var write func(models.ExportRow) error
+	if opts.format == routers.EXPORT_FORMAT_CSV {
+		writer := csv.NewWriter(out)
+		defer writer.Flush()
+		if err := writer.Write(columns); err != nil {
+			return err
+		}
+		write = func(row models.ExportRow) error {
+			return writer.Write(row.Values(columns))
+		}
+	} else {
+		encoder := json.NewEncoder(out)
+		write = func(row models.ExportRow) error {
+			return encoder.Encode(row)
+		}
+	}
+	return export(ctx, strings.ToLower(strings.TrimSpace(opts.chain)), normalizeAddress(args[0]), func(row models.ExportRow) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return write(row)
+	})
+}
+
+func serve(ctx context.Context, opts options, args []string) error {
+	closeDb, err := connect(opts)
+	if err != nil {
+		return err
+	}
+	defer closeDb()
+	return server.Run()
+}
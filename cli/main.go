@@ -0,0 +1,112 @@
+// Command openseasync-cli runs one-off syncs and maintenance against the database of the server, or the server itself.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	routers "openseasync/routers/common"
+	"os"
+	"os/signal"
+	"strings"
+)
+
+const usage = `usage: openseasync-cli [--config file] <command> [arguments]
+
+commands:
+  sync wallet <address>             sync the collections and assets of a wallet
+  sync collection <slug>            sync every wallet holding a collection
+  refresh asset <contract> <token>  sync the wallets holding an asset, its current owner included
+  migrate                           backfill the chain of old documents and create the indexes
+  purge-deleted --older-than <age>  remove the documents soft deleted more than age ago, 30d or 720h
+  export wallet <address>           write the assets, or with --activity the activity, of a wallet
+                                    as --format csv or ndjson to --out, stdout by default
+  serve                             run the api and its background jobs
+
+Every command takes --config, the commands reading or writing a chain take --chain, the default
+network when omitted. Events raised by a command are not sent to webhooks or streams, and a running
+server keeps its cached responses until they expire.
+`
+
+// errUsage a command line that cannot be run, usage is printed
+var errUsage = errors.New("invalid command line")
+
+// options the flags of every command, each command reads the ones it takes
+type options struct {
+	config    string
+	chain     string
+	olderThan string
+	format    string
+	activity  bool
+	out       string
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	err := run(ctx, os.Args[1:])
+	stop()
+	if errors.Is(err, errUsage) {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	} else if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string) error {
+	var opts options
+	fs := flag.NewFlagSet("openseasync-cli", flag.ContinueOnError)
+	fs.Usage = func() {}
+	fs.StringVar(&opts.config, "config", "", "configuration file, ./config/config.toml by default")
+	fs.StringVar(&opts.chain, "chain", "", "chain of the command, the default network when empty")
+	fs.StringVar(&opts.olderThan, "older-than", "", "age of the soft deleted documents purged")
+	fs.StringVar(&opts.format, "format", routers.EXPORT_FORMAT_CSV, "export format, csv or ndjson")
+	fs.BoolVar(&opts.activity, "activity", false, "export the activity rather than the assets")
+	fs.StringVar(&opts.out, "out", "", "export file, stdout when empty")
+
+	positional, err := parse(fs, args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return errUsage
+		}
+		return fmt.Errorf("%w: %s", errUsage, err)
+	}
+	if len(positional) == 0 {
+		return errUsage
+	}
+
+	name, rest := positional[0], positional[1:]
+	if name == "sync" || name == "refresh" || name == "export" {
+		if len(rest) == 0 {
+			return errUsage
+		}
+		name, rest = name+" "+rest[0], rest[1:]
+	}
+	cmd, ok := commands[name]
+	if !ok || len(rest) != cmd.args {
+		return errUsage
+	}
+	return cmd.run(ctx, opts, rest)
+}
+
+// parse the flags wherever they are, before, between or after the positional arguments
+func parse(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// normalizeAddress a wallet or contract as stored, lower case
+func normalizeAddress(address string) string {
+	return strings.ToLower(strings.TrimSpace(address))
+}
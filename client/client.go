@@ -0,0 +1,1368 @@
+// Code generated by openapi/clientgen. DO NOT EDIT.
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Version the version of the specification the client was generated from
+const Version = "swan-miner-v2.5.0"
+
+type AssetChange struct {
+	Chain          string      `json:"chain"`
+	Kind           string      `json:"kind"`
+	CollectibleId  int         `json:"collectibleId"`
+	CollectionId   string      `json:"collectionId"`
+	UserMetamaskId string      `json:"userMetamaskId"`
+	Field          string      `json:"field"`
+	OldValue       interface{} `json:"oldValue"`
+	NewValue       interface{} `json:"newValue"`
+	RefreshTime    int64       `json:"refreshTime"`
+	CreateDate     int64       `json:"createDate"`
+}
+
+type Collection struct {
+	Id                 string  `json:"id"`
+	Chain              string  `json:"chain"`
+	UserMetamaskId     string  `json:"userMetamaskId"`
+	CreatorMetamaskId  string  `json:"creatorMetamaskId"`
+	CollectionName     string  `json:"collectionName"`
+	UserCoverUrl       string  `json:"userCoverUrl"`
+	Description        string  `json:"description"`
+	CoverImageUrl      string  `json:"coverImageUrl"`
+	CoverLargeImageURL string  `json:"coverLargeImageURL"`
+	IsDelete           int     `json:"isDelete"`
+	CreateDate         int64   `json:"createDate"`
+	RefreshTime        int64   `json:"refreshTime"`
+	ItemsCount         int     `json:"itemsCount"`
+	TotalVolume        float64 `json:"totalVolume"`
+	FloorPrice         string  `json:"floorPrice"`
+	HighestPrice       string  `json:"highestPrice"`
+	OwnersCount        int     `json:"ownersCount"`
+	LikesCount         int     `json:"likesCount"`
+	ViewsCount         int     `json:"viewsCount"`
+}
+
+type CollectionHolder struct {
+	Owner         string  `json:"owner"`
+	ProfileImgUrl string  `json:"profileImgUrl"`
+	Quantity      int64   `json:"quantity"`
+	Tokens        int64   `json:"tokens"`
+	Percentage    float64 `json:"percentage"`
+}
+
+type CollectionRanking struct {
+	Chain          string  `json:"chain"`
+	Window         string  `json:"window"`
+	CollectionId   string  `json:"collectionId"`
+	CollectionName string  `json:"collectionName"`
+	Volume         string  `json:"volume"`
+	VolumeEth      float64 `json:"volumeEth"`
+	Sales          int64   `json:"sales"`
+	FloorPrice     string  `json:"floorPrice"`
+	FloorChange    float64 `json:"floorChange"`
+	UpdateTime     int64   `json:"updateTime"`
+}
+
+type CreatedWebhook struct {
+	Webhook *Webhook `json:"webhook"`
+	Secret  string   `json:"secret"`
+}
+
+type Disposal struct {
+	ActivityId        int        `json:"activityId"`
+	Contract          string     `json:"contract"`
+	TokenId           string     `json:"tokenId"`
+	CollectionId      string     `json:"collectionId"`
+	Quantity          float64    `json:"quantity"`
+	DisposedAt        int64      `json:"disposedAt"`
+	TxHash            string     `json:"txHash"`
+	Proceeds          Value      `json:"proceeds"`
+	Cost              Value      `json:"cost"`
+	Gain              Value      `json:"gain"`
+	Lots              []LotMatch `json:"lots"`
+	UnmatchedQuantity float64    `json:"unmatchedQuantity,omitempty"`
+}
+
+type Erasure struct {
+	Id          string           `json:"id"`
+	WalletHash  string           `json:"walletHash"`
+	Reason      string           `json:"reason"`
+	Deleted     map[string]int64 `json:"deleted"`
+	SyncBlocked bool             `json:"syncBlocked"`
+	CreateDate  int64            `json:"createDate"`
+	OptInDate   int64            `json:"optInDate"`
+}
+
+type GraphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+type HealthStatus struct {
+	Version string `json:"version"`
+}
+
+type HolderAnalytics struct {
+	Chain                  string             `json:"chain"`
+	CollectionId           string             `json:"collectionId"`
+	OwnersCount            int                `json:"ownersCount"`
+	Holders                int64              `json:"holders"`
+	Items                  int64              `json:"items"`
+	Quantity               int64              `json:"quantity"`
+	UniqueHolderPercentage float64            `json:"uniqueHolderPercentage"`
+	TopHolders             []CollectionHolder `json:"topHolders"`
+	Distribution           []HolderBucket     `json:"distribution"`
+	Churn                  *HolderChurn       `json:"churn"`
+	SyncTime               int64              `json:"syncTime"`
+}
+
+type HolderBucket struct {
+	Range      string  `json:"range"`
+	Wallets    int64   `json:"wallets"`
+	Percentage float64 `json:"percentage"`
+}
+
+type HolderChurn struct {
+	PreviousSyncTime int64   `json:"previousSyncTime"`
+	PreviousHolders  int64   `json:"previousHolders"`
+	Joined           int64   `json:"joined"`
+	Left             int64   `json:"left"`
+	Retained         int64   `json:"retained"`
+	ChurnRate        float64 `json:"churnRate"`
+}
+
+type HostInfo struct {
+	SwanMinerVersion string `json:"swan_miner_version"`
+	OperatingSystem  string `json:"operating_system"`
+	Architecture     string `json:"architecture"`
+	CpuNumber        int    `json:"cpu_number"`
+}
+
+type Lot struct {
+	ActivityId   int     `json:"activityId"`
+	Contract     string  `json:"contract"`
+	TokenId      string  `json:"tokenId"`
+	CollectionId string  `json:"collectionId"`
+	Quantity     float64 `json:"quantity"`
+	Remaining    float64 `json:"remaining"`
+	AcquiredAt   int64   `json:"acquiredAt"`
+	TxHash       string  `json:"txHash"`
+	Cost         Value   `json:"cost"`
+	MarketValue  *Value  `json:"marketValue,omitempty"`
+	Unrealized   *Value  `json:"unrealized,omitempty"`
+}
+
+type LotMatch struct {
+	ActivityId int     `json:"activityId"`
+	Quantity   float64 `json:"quantity"`
+	Cost       Value   `json:"cost"`
+}
+
+type Portfolio struct {
+	Valuation   string          `json:"valuation"`
+	Total       PortfolioPart   `json:"total"`
+	Collections []PortfolioPart `json:"collections"`
+}
+
+type PortfolioPart struct {
+	CollectionId   string           `json:"collectionId,omitempty"`
+	CollectionName string           `json:"collectionName,omitempty"`
+	Count          int              `json:"count"`
+	ValuedCount    int              `json:"valuedCount"`
+	Value          string           `json:"value"`
+	StatusCounts   map[string]int64 `json:"statusCounts"`
+}
+
+type PurgeResult struct {
+	Before  int64            `json:"before"`
+	Deleted map[string]int64 `json:"deleted"`
+}
+
+type Report struct {
+	Method      string        `json:"method"`
+	OpenLots    []*Lot        `json:"openLots"`
+	Disposals   []Disposal    `json:"disposals"`
+	Years       []YearSummary `json:"years"`
+	CostBasis   Value         `json:"costBasis"`
+	Realized    Value         `json:"realized"`
+	Unrealized  Value         `json:"unrealized"`
+	Unpriced    int           `json:"unpriced"`
+	Approximate int           `json:"approximate"`
+}
+
+type ResponseAsset struct {
+	Id                   int         `json:"id"`
+	Chain                string      `json:"chain"`
+	CollectibleName      string      `json:"collectibleName"`
+	CollectionId         string      `json:"collectionId"`
+	CollectionName       string      `json:"collectionName"`
+	CreatorMetamaskId    string      `json:"creatorMetamaskId"`
+	CreatorName          string      `json:"creatorName"`
+	CreatorPersonalSite  string      `json:"creatorPersonalSite"`
+	Description          string      `json:"description"`
+	FileUrl              string      `json:"fileUrl"`
+	OwnerMetamaskId      string      `json:"ownerMetamaskId"`
+	OwnerName            string      `json:"ownerName"`
+	Price                string      `json:"price"`
+	Status               string      `json:"status"`
+	ThumbnailUrl         string      `json:"thumbnailUrl"`
+	AnimationUrl         string      `json:"animationUrl"`
+	AnimationOriginalUrl string      `json:"animationOriginalUrl"`
+	CollectibleTokenId   string      `json:"collectibleTokenId"`
+	RecordId             string      `json:"recordId"`
+	StartTime            interface{} `json:"startTime"`
+	EndTime              interface{} `json:"endTime"`
+	OnChainOwner         string      `json:"onChainOwner"`
+	OwnershipMismatch    bool        `json:"ownershipMismatch"`
+}
+
+type ResponseAssetItem struct {
+	Id                   int         `json:"id"`
+	CoverImageUrl        string      `json:"coverImageUrl"`
+	CollectibleName      string      `json:"collectibleName"`
+	CreatorMetamaskId    string      `json:"creatorMetamaskId"`
+	CreatorName          string      `json:"creatorName"`
+	Price                string      `json:"price"`
+	LikesCount           int         `json:"likesCount"`
+	ViewsCount           int         `json:"viewsCount"`
+	NumOfCopies          int         `json:"numOfCopies"`
+	TotalCopies          int         `json:"totalCopies"`
+	Status               string      `json:"status"`
+	OwnerMetamaskId      string      `json:"ownerMetamaskId"`
+	CreateDate           int64       `json:"createDate"`
+	EndTime              interface{} `json:"endTime"`
+	AnimationUrl         string      `json:"animationUrl"`
+	AnimationOriginalUrl string      `json:"animationOriginalUrl"`
+}
+
+type ResponseColl struct {
+	Id             string `json:"id"`
+	UserId         string `json:"userId"`
+	UserMetamaskId string `json:"userMetamaskId"`
+	CoverImageUrl  string `json:"coverImageUrl"`
+	AvatarUrl      string `json:"avatarUrl"`
+	UserName       string `json:"userName"`
+	CollectionName string `json:"collectionName"`
+	Description    string `json:"description"`
+}
+
+type ResponseCollection struct {
+	Id             string `json:"id"`
+	Chain          string `json:"chain"`
+	UserId         string `json:"userId"`
+	UserMetamaskId string `json:"userMetamaskId"`
+	UserCoverUrl   string `json:"userCoverUrl"`
+	AvatarUrl      string `json:"avatarUrl"`
+	UserName       string `json:"userName"`
+	ItemsCount     int    `json:"itemsCount"`
+	OwnersCount    int    `json:"ownersCount"`
+	LikesCount     int    `json:"likesCount"`
+	ViewsCount     int    `json:"viewsCount"`
+	FloorPrice     string `json:"floorPrice"`
+	HighestPrice   string `json:"highestPrice"`
+	CollectionName string `json:"collectionName"`
+	Description    string `json:"description"`
+}
+
+type ResponseItemActivity struct {
+	Id                int      `json:"id"`
+	Chain             string   `json:"chain"`
+	TradeType         string   `json:"tradeType"`
+	Price             string   `json:"price"`
+	PriceInUsd        string   `json:"priceInUsd"`
+	CollectibleId     int      `json:"collectibleId"`
+	CollectibleName   string   `json:"collectibleName"`
+	Quantity          string   `json:"quantity"`
+	BuyerId           int      `json:"buyerId"`
+	BuyerMetamaskId   string   `json:"buyerMetamaskId"`
+	BuyerName         string   `json:"buyerName"`
+	SellerId          int      `json:"sellerId"`
+	SellerMetamaskId  string   `json:"sellerMetamaskId"`
+	SellerName        string   `json:"sellerName"`
+	CreateDate        int64    `json:"createDate"`
+	Suspicious        bool     `json:"suspicious"`
+	SuspiciousReasons []string `json:"suspiciousReasons"`
+}
+
+type ResponseOrder struct {
+	Id                string `json:"id"`
+	AuctionUserId     string `json:"auctionUserId"`
+	AuctionMetamaskId string `json:"auctionMetamaskId"`
+	AuctionUserName   string `json:"auctionUserName"`
+	Price             string `json:"price"`
+	TradeType         string `json:"tradeType"`
+	BidTime           int64  `json:"bidTime"`
+	StartTime         int64  `json:"startTime"`
+	EndTime           int64  `json:"endTime"`
+	Status            string `json:"status"`
+	StatusTime        int64  `json:"statusTime"`
+	Protocol          string `json:"protocol"`
+	SignatureStatus   string `json:"signatureStatus"`
+	Signer            string `json:"signer"`
+}
+
+type SearchAsset struct {
+	Id                 int     `json:"id"`
+	Chain              string  `json:"chain"`
+	ContractAddress    string  `json:"contractAddress"`
+	CollectibleTokenId string  `json:"collectibleTokenId"`
+	CollectibleName    string  `json:"collectibleName"`
+	CoverImageUrl      string  `json:"coverImageUrl"`
+	CollectionId       string  `json:"collectionId"`
+	CollectionName     string  `json:"collectionName"`
+	Score              float64 `json:"score"`
+}
+
+type SearchCollection struct {
+	Id             string  `json:"id"`
+	Chain          string  `json:"chain"`
+	CollectionName string  `json:"collectionName"`
+	CoverImageUrl  string  `json:"coverImageUrl"`
+	Score          float64 `json:"score"`
+}
+
+type SearchResult struct {
+	Assets      []SearchAsset      `json:"assets"`
+	Collections []SearchCollection `json:"collections"`
+	Users       []SearchUser       `json:"users"`
+}
+
+type SearchUser struct {
+	UserMetamaskId string  `json:"userMetamaskId"`
+	UserName       string  `json:"userName"`
+	AvatarUrl      string  `json:"avatarUrl"`
+	Score          float64 `json:"score"`
+}
+
+type SuspiciousResult struct {
+	Changed int64 `json:"changed"`
+}
+
+type SyncRun struct {
+	Id             string `json:"id"`
+	Chain          string `json:"chain"`
+	UserMetamaskId string `json:"userMetamaskId"`
+	Trigger        string `json:"trigger"`
+	Status         string `json:"status"`
+	Error          string `json:"error"`
+	StartTime      int64  `json:"startTime"`
+	EndTime        int64  `json:"endTime"`
+}
+
+type User struct {
+	Id               string `json:"id"`
+	UserMetamaskId   string `json:"userMetamaskId"`
+	UserName         string `json:"userName"`
+	AvatarUrl        string `json:"avatarUrl"`
+	DiscordLink      string `json:"discordLink"`
+	TelegramLink     string `json:"telegramLink"`
+	InstagramLink    string `json:"instagramLink"`
+	TwitterLink      string `json:"twitterLink"`
+	PersonalPageLink string `json:"personalPageLink"`
+}
+
+type Value struct {
+	Eth float64 `json:"eth"`
+	Usd float64 `json:"usd"`
+}
+
+type WalletSync struct {
+	Chain          string   `json:"chain"`
+	UserMetamaskId string   `json:"userMetamaskId"`
+	RefreshTime    int64    `json:"refreshTime"`
+	LastRun        *SyncRun `json:"lastRun"`
+}
+
+type Webhook struct {
+	Id            string   `json:"id"`
+	Url           string   `json:"url"`
+	Chain         string   `json:"chain"`
+	Wallets       []string `json:"wallets"`
+	CollectionIds []string `json:"collectionIds"`
+	EventTypes    []string `json:"eventTypes"`
+	IsDelete      int      `json:"isDelete"`
+	CreateDate    int64    `json:"createDate"`
+}
+
+type WebhookDelivery struct {
+	Id           string `json:"id"`
+	WebhookId    string `json:"webhookId"`
+	Url          string `json:"url"`
+	EventType    string `json:"eventType"`
+	Payload      string `json:"payload"`
+	Status       string `json:"status"`
+	Attempts     int    `json:"attempts"`
+	ResponseCode int    `json:"responseCode"`
+	LastError    string `json:"lastError"`
+	CreateDate   int64  `json:"createDate"`
+	UpdateDate   int64  `json:"updateDate"`
+}
+
+type WebhookParam struct {
+	Url           string   `json:"url"`
+	Secret        string   `json:"secret"`
+	Chain         string   `json:"chain"`
+	Wallets       []string `json:"wallets"`
+	CollectionIds []string `json:"collectionIds"`
+	EventTypes    []string `json:"eventTypes"`
+}
+
+type YearSummary struct {
+	Year      int   `json:"year"`
+	Disposals int   `json:"disposals"`
+	Proceeds  Value `json:"proceeds"`
+	Cost      Value `json:"cost"`
+	Gain      Value `json:"gain"`
+}
+
+// CancelSyncParams the parameters of CancelSync
+type CancelSyncParams struct {
+	User  string // path, required
+	Chain string // query
+}
+
+// CancelSync cancel the running sync of a wallet
+func (c *Client) CancelSync(ctx context.Context, params CancelSyncParams) (SyncRun, error) {
+	path := "/api/admin/syncs/{user}"
+	query := url.Values{}
+	path = strings.Replace(path, "{user}", url.PathEscape(params.User), 1)
+	if params.Chain != "" {
+		query.Set("chain", params.Chain)
+	}
+	var data SyncRun
+	err := c.do(ctx, "DELETE", path, query, nil, &data, nil)
+	return data, err
+}
+
+// CreateWebhook subscribe a url to events
+func (c *Client) CreateWebhook(ctx context.Context, body WebhookParam) (CreatedWebhook, error) {
+	path := "/api/public/webhooks"
+	query := url.Values{}
+	var data CreatedWebhook
+	err := c.do(ctx, "POST", path, query, body, &data, nil)
+	return data, err
+}
+
+// DeleteAssetParams the parameters of DeleteAsset
+type DeleteAssetParams struct {
+	User            string // path, required
+	ContractAddress string // path, required
+	TokenId         string // path, required
+	Chain           string // query
+}
+
+// DeleteAsset soft delete an asset of a wallet
+func (c *Client) DeleteAsset(ctx context.Context, params DeleteAssetParams) error {
+	path := "/api/public/assets/{user}/{contract_address}/{token_id}"
+	query := url.Values{}
+	path = strings.Replace(path, "{user}", url.PathEscape(params.User), 1)
+	path = strings.Replace(path, "{contract_address}", url.PathEscape(params.ContractAddress), 1)
+	path = strings.Replace(path, "{token_id}", url.PathEscape(params.TokenId), 1)
+	if params.Chain != "" {
+		query.Set("chain", params.Chain)
+	}
+	return c.do(ctx, "DELETE", path, query, nil, nil, nil)
+}
+
+// DeleteCollectionParams the parameters of DeleteCollection
+type DeleteCollectionParams struct {
+	User  string // path, required
+	Slug  string // path, required
+	Chain string // query
+}
+
+// DeleteCollection soft delete an empty collection of a wallet
+func (c *Client) DeleteCollection(ctx context.Context, params DeleteCollectionParams) error {
+	path := "/api/public/collections/{user}/{slug}"
+	query := url.Values{}
+	path = strings.Replace(path, "{user}", url.PathEscape(params.User), 1)
+	path = strings.Replace(path, "{slug}", url.PathEscape(params.Slug), 1)
+	if params.Chain != "" {
+		query.Set("chain", params.Chain)
+	}
+	return c.do(ctx, "DELETE", path, query, nil, nil, nil)
+}
+
+// DeleteWebhookParams the parameters of DeleteWebhook
+type DeleteWebhookParams struct {
+	Id string // path, required
+}
+
+// DeleteWebhook remove a subscription
+func (c *Client) DeleteWebhook(ctx context.Context, params DeleteWebhookParams) error {
+	path := "/api/public/webhooks/{id}"
+	query := url.Values{}
+	path = strings.Replace(path, "{id}", url.PathEscape(params.Id), 1)
+	return c.do(ctx, "DELETE", path, query, nil, nil, nil)
+}
+
+// Docs api documentation
+func (c *Client) Docs(ctx context.Context) (io.ReadCloser, error) {
+	path := "/api/docs"
+	query := url.Values{}
+	return c.stream(ctx, "GET", path, query, nil)
+}
+
+// EraseWalletParams the parameters of EraseWallet
+type EraseWalletParams struct {
+	User   string // path, required
+	Reason string // query
+}
+
+// EraseWallet erase every document synced for a wallet and block syncing it again
+func (c *Client) EraseWallet(ctx context.Context, params EraseWalletParams) (Erasure, error) {
+	path := "/api/admin/wallets/{user}"
+	query := url.Values{}
+	path = strings.Replace(path, "{user}", url.PathEscape(params.User), 1)
+	if params.Reason != "" {
+		query.Set("reason", params.Reason)
+	}
+	var data Erasure
+	err := c.do(ctx, "DELETE", path, query, nil, &data, nil)
+	return data, err
+}
+
+// ExportWalletActivityParams the parameters of ExportWalletActivity
+type ExportWalletActivityParams struct {
+	User   string // path, required
+	Chain  string // query
+	Format string // query
+}
+
+// ExportWalletActivity sales and transfers of a wallet as csv or ndjson
+func (c *Client) ExportWalletActivity(ctx context.Context, params ExportWalletActivityParams) (io.ReadCloser, error) {
+	path := "/api/public/export/wallet/{user}/activity"
+	query := url.Values{}
+	path = strings.Replace(path, "{user}", url.PathEscape(params.User), 1)
+	if params.Chain != "" {
+		query.Set("chain", params.Chain)
+	}
+	if params.Format != "" {
+		query.Set("format", params.Format)
+	}
+	return c.stream(ctx, "GET", path, query, nil)
+}
+
+// ExportWalletAssetsParams the parameters of ExportWalletAssets
+type ExportWalletAssetsParams struct {
+	User   string // path, required
+	Chain  string // query
+	Format string // query
+}
+
+// ExportWalletAssets assets of a wallet as csv or ndjson
+func (c *Client) ExportWalletAssets(ctx context.Context, params ExportWalletAssetsParams) (io.ReadCloser, error) {
+	path := "/api/public/export/wallet/{user}/assets"
+	query := url.Values{}
+	path = strings.Replace(path, "{user}", url.PathEscape(params.User), 1)
+	if params.Chain != "" {
+		query.Set("chain", params.Chain)
+	}
+	if params.Format != "" {
+		query.Set("format", params.Format)
+	}
+	return c.stream(ctx, "GET", path, query, nil)
+}
+
+// ForceSyncParams the parameters of ForceSync
+type ForceSyncParams struct {
+	User  string // path, required
+	Chain string // query
+}
+
+// ForceSync start a sync of a wallet in the background
+func (c *Client) ForceSync(ctx context.Context, params ForceSyncParams) (SyncRun, error) {
+	path := "/api/admin/syncs/{user}"
+	query := url.Values{}
+	path = strings.Replace(path, "{user}", url.PathEscape(params.User), 1)
+	if params.Chain != "" {
+		query.Set("chain", params.Chain)
+	}
+	var data SyncRun
+	err := c.do(ctx, "POST", path, query, nil, &data, nil)
+	return data, err
+}
+
+// GetAssetParams the parameters of GetAsset
+type GetAssetParams struct {
+	CollectibleId int64  // path, required
+	Chain         string // query
+}
+
+// GetAsset an asset in full
+func (c *Client) GetAsset(ctx context.Context, params GetAssetParams) (ResponseAsset, error) {
+	path := "/api/public/collectibles/generalInfo/{collectibleId}"
+	query := url.Values{}
+	path = strings.Replace(path, "{collectibleId}", url.PathEscape(strconv.FormatInt(params.CollectibleId, 10)), 1)
+	if params.Chain != "" {
+		query.Set("chain", params.Chain)
+	}
+	var data ResponseAsset
+	err := c.do(ctx, "GET", path, query, nil, &data, nil)
+	return data, err
+}
+
+// GetAssetChangesParams the parameters of GetAssetChanges
+type GetAssetChangesParams struct {
+	CollectibleId int64  // path, required
+	Chain         string // query
+	Page          int64  // query, required
+	PageSize      int64  // query, required
+}
+
+// GetAssetChanges fields changed by each sync of an asset
+func (c *Client) GetAssetChanges(ctx context.Context, params GetAssetChangesParams) ([]AssetChange, *PageMetadata, error) {
+	path := "/api/public/collectibles/changes/{collectibleId}"
+	query := url.Values{}
+	path = strings.Replace(path, "{collectibleId}", url.PathEscape(strconv.FormatInt(params.CollectibleId, 10)), 1)
+	if params.Chain != "" {
+		query.Set("chain", params.Chain)
+	}
+	if params.Page != 0 {
+		query.Set("page", strconv.FormatInt(params.Page, 10))
+	}
+	if params.PageSize != 0 {
+		query.Set("pageSize", strconv.FormatInt(params.PageSize, 10))
+	}
+	var data []AssetChange
+	var metadata PageMetadata
+	if err := c.do(ctx, "GET", path, query, nil, &data, &metadata); err != nil {
+		return data, nil, err
+	}
+	return data, &metadata, nil
+}
+
+// GetAssetHighestBidParams the parameters of GetAssetHighestBid
+type GetAssetHighestBidParams struct {
+	CollectibleId int64  // path, required
+	Chain         string // query
+	Include       string // query
+}
+
+// GetAssetHighestBid highest live auction bid of an asset, of every bid with include=history, none without bids
+func (c *Client) GetAssetHighestBid(ctx context.Context, params GetAssetHighestBidParams) (*ResponseOrder, error) {
+	path := "/api/public/collectibles/highestPrice/{collectibleId}"
+	query := url.Values{}
+	path = strings.Replace(path, "{collectibleId}", url.PathEscape(strconv.FormatInt(params.CollectibleId, 10)), 1)
+	if params.Chain != "" {
+		query.Set("chain", params.Chain)
+	}
+	if params.Include != "" {
+		query.Set("include", params.Include)
+	}
+	var data *ResponseOrder
+	err := c.do(ctx, "GET", path, query, nil, &data, nil)
+	return data, err
+}
+
+// GetAssetOffersParams the parameters of GetAssetOffers
+type GetAssetOffersParams struct {
+	CollectibleId int64  // path, required
+	Chain         string // query
+	Include       string // query
+}
+
+// GetAssetOffers live bids and listings of an asset with valid or unverified signatures, closed and invalid ones too with include=history
+func (c *Client) GetAssetOffers(ctx context.Context, params GetAssetOffersParams) ([]ResponseOrder, error) {
+	path := "/api/public/collectibles/offerRecords/{collectibleId}"
+	query := url.Values{}
+	path = strings.Replace(path, "{collectibleId}", url.PathEscape(strconv.FormatInt(params.CollectibleId, 10)), 1)
+	if params.Chain != "" {
+		query.Set("chain", params.Chain)
+	}
+	if params.Include != "" {
+		query.Set("include", params.Include)
+	}
+	var data []ResponseOrder
+	err := c.do(ctx, "GET", path, query, nil, &data, nil)
+	return data, err
+}
+
+// GetAssetSiblingsParams the parameters of GetAssetSiblings
+type GetAssetSiblingsParams struct {
+	CollectibleId int64  // path, required
+	Chain         string // query
+}
+
+// GetAssetSiblings other assets of the collection of an asset
+func (c *Client) GetAssetSiblings(ctx context.Context, params GetAssetSiblingsParams) ([]ResponseAssetItem, error) {
+	path := "/api/public/collectibles/otherCollectibles/{collectibleId}"
+	query := url.Values{}
+	path = strings.Replace(path, "{collectibleId}", url.PathEscape(strconv.FormatInt(params.CollectibleId, 10)), 1)
+	if params.Chain != "" {
+		query.Set("chain", params.Chain)
+	}
+	var data []ResponseAssetItem
+	err := c.do(ctx, "GET", path, query, nil, &data, nil)
+	return data, err
+}
+
+// GetAssetTradeHistoryParams the parameters of GetAssetTradeHistory
+type GetAssetTradeHistoryParams struct {
+	CollectibleId int64  // path, required
+	Chain         string // query
+	Page          int64  // query, required
+	PageSize      int64  // query, required
+}
+
+// GetAssetTradeHistory sales, bids and transfers of an asset
+func (c *Client) GetAssetTradeHistory(ctx context.Context, params GetAssetTradeHistoryParams) ([]ResponseItemActivity, *PageMetadata, error) {
+	path := "/api/public/collectibles/trade/{collectibleId}/history"
+	query := url.Values{}
+	path = strings.Replace(path, "{collectibleId}", url.PathEscape(strconv.FormatInt(params.CollectibleId, 10)), 1)
+	if params.Chain != "" {
+		query.Set("chain", params.Chain)
+	}
+	if params.Page != 0 {
+		query.Set("page", strconv.FormatInt(params.Page, 10))
+	}
+	if params.PageSize != 0 {
+		query.Set("pageSize", strconv.FormatInt(params.PageSize, 10))
+	}
+	var data []ResponseItemActivity
+	var metadata PageMetadata
+	if err := c.do(ctx, "GET", path, query, nil, &data, &metadata); err != nil {
+		return data, nil, err
+	}
+	return data, &metadata, nil
+}
+
+// GetCollectionParams the parameters of GetCollection
+type GetCollectionParams struct {
+	CollectionId string // path, required
+	Chain        string // query
+}
+
+// GetCollection a collection with its creator
+func (c *Client) GetCollection(ctx context.Context, params GetCollectionParams) (ResponseCollection, error) {
+	path := "/api/public/collection/getInfo/{collectionId}"
+	query := url.Values{}
+	path = strings.Replace(path, "{collectionId}", url.PathEscape(params.CollectionId), 1)
+	if params.Chain != "" {
+		query.Set("chain", params.Chain)
+	}
+	var data ResponseCollection
+	err := c.do(ctx, "GET", path, query, nil, &data, nil)
+	return data, err
+}
+
+// GetCollectionActivitiesParams the parameters of GetCollectionActivities
+type GetCollectionActivitiesParams struct {
+	CollectionId string // path, required
+	Chain        string // query
+	Page         int64  // query, required
+	PageSize     int64  // query, required
+}
+
+// GetCollectionActivities sales, bids and transfers of a collection
+func (c *Client) GetCollectionActivities(ctx context.Context, params GetCollectionActivitiesParams) ([]ResponseItemActivity, *PageMetadata, error) {
+	path := "/api/public/collection/getItemActivities/{collectionId}"
+	query := url.Values{}
+	path = strings.Replace(path, "{collectionId}", url.PathEscape(params.CollectionId), 1)
+	if params.Chain != "" {
+		query.Set("chain", params.Chain)
+	}
+	if params.Page != 0 {
+		query.Set("page", strconv.FormatInt(params.Page, 10))
+	}
+	if params.PageSize != 0 {
+		query.Set("pageSize", strconv.FormatInt(params.PageSize, 10))
+	}
+	var data []ResponseItemActivity
+	var metadata PageMetadata
+	if err := c.do(ctx, "GET", path, query, nil, &data, &metadata); err != nil {
+		return data, nil, err
+	}
+	return data, &metadata, nil
+}
+
+// GetCollectionHoldersParams the parameters of GetCollectionHolders
+type GetCollectionHoldersParams struct {
+	CollectionId string // path, required
+	Chain        string // query
+	Top          int64  // query
+}
+
+// GetCollectionHolders top holders, holding distribution and holder churn of a collection
+func (c *Client) GetCollectionHolders(ctx context.Context, params GetCollectionHoldersParams) (HolderAnalytics, error) {
+	path := "/api/public/collection/holders/{collectionId}"
+	query := url.Values{}
+	path = strings.Replace(path, "{collectionId}", url.PathEscape(params.CollectionId), 1)
+	if params.Chain != "" {
+		query.Set("chain", params.Chain)
+	}
+	if params.Top != 0 {
+		query.Set("top", strconv.FormatInt(params.Top, 10))
+	}
+	var data HolderAnalytics
+	err := c.do(ctx, "GET", path, query, nil, &data, nil)
+	return data, err
+}
+
+// GetCollectionRankingsParams the parameters of GetCollectionRankings
+type GetCollectionRankingsParams struct {
+	Metric   string // query
+	Window   string // query
+	Page     int64  // query
+	PageSize int64  // query
+	Chain    string // query
+}
+
+// GetCollectionRankings collections ranked by volume, sales or floor change
+func (c *Client) GetCollectionRankings(ctx context.Context, params GetCollectionRankingsParams) ([]CollectionRanking, *PageMetadata, error) {
+	path := "/api/public/collections/rankings"
+	query := url.Values{}
+	if params.Metric != "" {
+		query.Set("metric", params.Metric)
+	}
+	if params.Window != "" {
+		query.Set("window", params.Window)
+	}
+	if params.Page != 0 {
+		query.Set("page", strconv.FormatInt(params.Page, 10))
+	}
+	if params.PageSize != 0 {
+		query.Set("pageSize", strconv.FormatInt(params.PageSize, 10))
+	}
+	if params.Chain != "" {
+		query.Set("chain", params.Chain)
+	}
+	var data []CollectionRanking
+	var metadata PageMetadata
+	if err := c.do(ctx, "GET", path, query, nil, &data, &metadata); err != nil {
+		return data, nil, err
+	}
+	return data, &metadata, nil
+}
+
+// GetErasuresParams the parameters of GetErasures
+type GetErasuresParams struct {
+	Page     int64 // query
+	PageSize int64 // query
+}
+
+// GetErasures erasure audit records, most recent first
+func (c *Client) GetErasures(ctx context.Context, params GetErasuresParams) ([]Erasure, *PageMetadata, error) {
+	path := "/api/admin/erasures"
+	query := url.Values{}
+	if params.Page != 0 {
+		query.Set("page", strconv.FormatInt(params.Page, 10))
+	}
+	if params.PageSize != 0 {
+		query.Set("pageSize", strconv.FormatInt(params.PageSize, 10))
+	}
+	var data []Erasure
+	var metadata PageMetadata
+	if err := c.do(ctx, "GET", path, query, nil, &data, &metadata); err != nil {
+		return data, nil, err
+	}
+	return data, &metadata, nil
+}
+
+// GetHostInfo version and platform of the service
+func (c *Client) GetHostInfo(ctx context.Context) (HostInfo, error) {
+	path := "/api/public/host/info"
+	query := url.Values{}
+	var data HostInfo
+	err := c.do(ctx, "GET", path, query, nil, &data, nil)
+	return data, err
+}
+
+// GetSyncErrorsParams the parameters of GetSyncErrors
+type GetSyncErrorsParams struct {
+	Page     int64  // query
+	PageSize int64  // query
+	Chain    string // query
+}
+
+// GetSyncErrors failed syncs, most recent first
+func (c *Client) GetSyncErrors(ctx context.Context, params GetSyncErrorsParams) ([]SyncRun, *PageMetadata, error) {
+	path := "/api/admin/syncErrors"
+	query := url.Values{}
+	if params.Page != 0 {
+		query.Set("page", strconv.FormatInt(params.Page, 10))
+	}
+	if params.PageSize != 0 {
+		query.Set("pageSize", strconv.FormatInt(params.PageSize, 10))
+	}
+	if params.Chain != "" {
+		query.Set("chain", params.Chain)
+	}
+	var data []SyncRun
+	var metadata PageMetadata
+	if err := c.do(ctx, "GET", path, query, nil, &data, &metadata); err != nil {
+		return data, nil, err
+	}
+	return data, &metadata, nil
+}
+
+// GetUserParams the parameters of GetUser
+type GetUserParams struct {
+	UserMetamaskId string // path, required
+}
+
+// GetUser profile and social links of a user
+func (c *Client) GetUser(ctx context.Context, params GetUserParams) (User, error) {
+	path := "/api/public/user/socialMedia/{userMetamaskId}"
+	query := url.Values{}
+	path = strings.Replace(path, "{userMetamaskId}", url.PathEscape(params.UserMetamaskId), 1)
+	var data User
+	err := c.do(ctx, "GET", path, query, nil, &data, nil)
+	return data, err
+}
+
+// GetWalletCollectionsParams the parameters of GetWalletCollections
+type GetWalletCollectionsParams struct {
+	Usermetamaskid string // path, required
+	Page           int64  // query, required
+	PageSize       int64  // query, required
+	Chain          string // query
+}
+
+// GetWalletCollections collections of a wallet
+func (c *Client) GetWalletCollections(ctx context.Context, params GetWalletCollectionsParams) ([]ResponseColl, *PageMetadata, error) {
+	path := "/api/public/collectionsByUserId/{usermetamaskid}"
+	query := url.Values{}
+	path = strings.Replace(path, "{usermetamaskid}", url.PathEscape(params.Usermetamaskid), 1)
+	if params.Page != 0 {
+		query.Set("page", strconv.FormatInt(params.Page, 10))
+	}
+	if params.PageSize != 0 {
+		query.Set("pageSize", strconv.FormatInt(params.PageSize, 10))
+	}
+	if params.Chain != "" {
+		query.Set("chain", params.Chain)
+	}
+	var data []ResponseColl
+	var metadata PageMetadata
+	if err := c.do(ctx, "GET", path, query, nil, &data, &metadata); err != nil {
+		return data, nil, err
+	}
+	return data, &metadata, nil
+}
+
+// GetWalletPnlParams the parameters of GetWalletPnl
+type GetWalletPnlParams struct {
+	User   string // path, required
+	Chain  string // query
+	Method string // query
+	Match  string // query
+}
+
+// GetWalletPnl cost basis and profit and loss of a wallet, sales are valued at the rates recorded closest to them, rates being recorded from the first sync on
+func (c *Client) GetWalletPnl(ctx context.Context, params GetWalletPnlParams) (Report, error) {
+	path := "/api/public/wallet/{user}/pnl"
+	query := url.Values{}
+	path = strings.Replace(path, "{user}", url.PathEscape(params.User), 1)
+	if params.Chain != "" {
+		query.Set("chain", params.Chain)
+	}
+	if params.Method != "" {
+		query.Set("method", params.Method)
+	}
+	if params.Match != "" {
+		query.Set("match", params.Match)
+	}
+	var data Report
+	err := c.do(ctx, "GET", path, query, nil, &data, nil)
+	return data, err
+}
+
+// GetWalletPortfolioParams the parameters of GetWalletPortfolio
+type GetWalletPortfolioParams struct {
+	User      string // path, required
+	Chain     string // query
+	Valuation string // query
+}
+
+// GetWalletPortfolio value of the assets of a wallet by collection
+func (c *Client) GetWalletPortfolio(ctx context.Context, params GetWalletPortfolioParams) (Portfolio, error) {
+	path := "/api/public/wallet/{user}/portfolio"
+	query := url.Values{}
+	path = strings.Replace(path, "{user}", url.PathEscape(params.User), 1)
+	if params.Chain != "" {
+		query.Set("chain", params.Chain)
+	}
+	if params.Valuation != "" {
+		query.Set("valuation", params.Valuation)
+	}
+	var data Portfolio
+	err := c.do(ctx, "GET", path, query, nil, &data, nil)
+	return data, err
+}
+
+// GetWalletSyncsParams the parameters of GetWalletSyncs
+type GetWalletSyncsParams struct {
+	Page     int64  // query
+	PageSize int64  // query
+	Chain    string // query
+}
+
+// GetWalletSyncs synced wallets, least recently refreshed first, with their latest sync
+func (c *Client) GetWalletSyncs(ctx context.Context, params GetWalletSyncsParams) ([]WalletSync, *PageMetadata, error) {
+	path := "/api/admin/syncs"
+	query := url.Values{}
+	if params.Page != 0 {
+		query.Set("page", strconv.FormatInt(params.Page, 10))
+	}
+	if params.PageSize != 0 {
+		query.Set("pageSize", strconv.FormatInt(params.PageSize, 10))
+	}
+	if params.Chain != "" {
+		query.Set("chain", params.Chain)
+	}
+	var data []WalletSync
+	var metadata PageMetadata
+	if err := c.do(ctx, "GET", path, query, nil, &data, &metadata); err != nil {
+		return data, nil, err
+	}
+	return data, &metadata, nil
+}
+
+// GetWebhookDeadLettersParams the parameters of GetWebhookDeadLetters
+type GetWebhookDeadLettersParams struct {
+	Id       string // path, required
+	Page     int64  // query
+	PageSize int64  // query
+}
+
+// GetWebhookDeadLetters deliveries of a webhook that ran out of attempts
+func (c *Client) GetWebhookDeadLetters(ctx context.Context, params GetWebhookDeadLettersParams) ([]WebhookDelivery, *PageMetadata, error) {
+	path := "/api/public/webhooks/{id}/deadLetters"
+	query := url.Values{}
+	path = strings.Replace(path, "{id}", url.PathEscape(params.Id), 1)
+	if params.Page != 0 {
+		query.Set("page", strconv.FormatInt(params.Page, 10))
+	}
+	if params.PageSize != 0 {
+		query.Set("pageSize", strconv.FormatInt(params.PageSize, 10))
+	}
+	var data []WebhookDelivery
+	var metadata PageMetadata
+	if err := c.do(ctx, "GET", path, query, nil, &data, &metadata); err != nil {
+		return data, nil, err
+	}
+	return data, &metadata, nil
+}
+
+// GetWebhookDeliveriesParams the parameters of GetWebhookDeliveries
+type GetWebhookDeliveriesParams struct {
+	Id       string // path, required
+	Page     int64  // query
+	PageSize int64  // query
+}
+
+// GetWebhookDeliveries delivery log of a webhook
+func (c *Client) GetWebhookDeliveries(ctx context.Context, params GetWebhookDeliveriesParams) ([]WebhookDelivery, *PageMetadata, error) {
+	path := "/api/public/webhooks/{id}/deliveries"
+	query := url.Values{}
+	path = strings.Replace(path, "{id}", url.PathEscape(params.Id), 1)
+	if params.Page != 0 {
+		query.Set("page", strconv.FormatInt(params.Page, 10))
+	}
+	if params.PageSize != 0 {
+		query.Set("pageSize", strconv.FormatInt(params.PageSize, 10))
+	}
+	var data []WebhookDelivery
+	var metadata PageMetadata
+	if err := c.do(ctx, "GET", path, query, nil, &data, &metadata); err != nil {
+		return data, nil, err
+	}
+	return data, &metadata, nil
+}
+
+// GetWebhooks every subscription
+func (c *Client) GetWebhooks(ctx context.Context) ([]Webhook, error) {
+	path := "/api/public/webhooks"
+	query := url.Values{}
+	var data []Webhook
+	err := c.do(ctx, "GET", path, query, nil, &data, nil)
+	return data, err
+}
+
+// Graphql assets, collections, users, orders and activity with their relations, a graphql response
+func (c *Client) Graphql(ctx context.Context, body GraphQLRequest) (io.ReadCloser, error) {
+	path := "/api/public/graphql"
+	query := url.Values{}
+	return c.stream(ctx, "POST", path, query, body)
+}
+
+// Healthz the process is up
+func (c *Client) Healthz(ctx context.Context) (HealthStatus, error) {
+	path := "/healthz"
+	query := url.Values{}
+	var data HealthStatus
+	err := c.do(ctx, "GET", path, query, nil, &data, nil)
+	return data, err
+}
+
+// Metrics prometheus metrics
+func (c *Client) Metrics(ctx context.Context) (io.ReadCloser, error) {
+	path := "/metrics"
+	query := url.Values{}
+	return c.stream(ctx, "GET", path, query, nil)
+}
+
+// Openapi this specification
+func (c *Client) Openapi(ctx context.Context) (io.ReadCloser, error) {
+	path := "/api/openapi.json"
+	query := url.Values{}
+	return c.stream(ctx, "GET", path, query, nil)
+}
+
+// OptInWalletParams the parameters of OptInWallet
+type OptInWalletParams struct {
+	User string // path, required
+}
+
+// OptInWallet let an erased wallet be synced again
+func (c *Client) OptInWallet(ctx context.Context, params OptInWalletParams) error {
+	path := "/api/admin/wallets/{user}/optIn"
+	query := url.Values{}
+	path = strings.Replace(path, "{user}", url.PathEscape(params.User), 1)
+	return c.do(ctx, "POST", path, query, nil, nil, nil)
+}
+
+// PurgeDeletedParams the parameters of PurgeDeleted
+type PurgeDeletedParams struct {
+	Days  int64  // query, required
+	Chain string // query
+}
+
+// PurgeDeleted remove documents soft deleted more than days ago
+func (c *Client) PurgeDeleted(ctx context.Context, params PurgeDeletedParams) (PurgeResult, error) {
+	path := "/api/admin/purge"
+	query := url.Values{}
+	if params.Days != 0 {
+		query.Set("days", strconv.FormatInt(params.Days, 10))
+	}
+	if params.Chain != "" {
+		query.Set("chain", params.Chain)
+	}
+	var data PurgeResult
+	err := c.do(ctx, "POST", path, query, nil, &data, nil)
+	return data, err
+}
+
+// Readyz mongo, and opensea if configured, are reachable
+func (c *Client) Readyz(ctx context.Context) error {
+	path := "/readyz"
+	query := url.Values{}
+	return c.do(ctx, "GET", path, query, nil, nil, nil)
+}
+
+// RecomputeCollectionParams the parameters of RecomputeCollection
+type RecomputeCollectionParams struct {
+	CollectionId string // path, required
+	Chain        string // query
+}
+
+// RecomputeCollection derive the floor price of a collection from its assets again
+func (c *Client) RecomputeCollection(ctx context.Context, params RecomputeCollectionParams) (Collection, error) {
+	path := "/api/admin/recompute/collections/{collectionId}"
+	query := url.Values{}
+	path = strings.Replace(path, "{collectionId}", url.PathEscape(params.CollectionId), 1)
+	if params.Chain != "" {
+		query.Set("chain", params.Chain)
+	}
+	var data Collection
+	err := c.do(ctx, "POST", path, query, nil, &data, nil)
+	return data, err
+}
+
+// RecomputeRankings refresh the collection rankings of every window now
+func (c *Client) RecomputeRankings(ctx context.Context) error {
+	path := "/api/admin/recompute/rankings"
+	query := url.Values{}
+	return c.do(ctx, "POST", path, query, nil, nil, nil)
+}
+
+// RecomputeSuspicious flag the suspicious sales now, and refresh the rankings when a flag changed
+func (c *Client) RecomputeSuspicious(ctx context.Context) (SuspiciousResult, error) {
+	path := "/api/admin/recompute/suspicious"
+	query := url.Values{}
+	var data SuspiciousResult
+	err := c.do(ctx, "POST", path, query, nil, &data, nil)
+	return data, err
+}
+
+// RedeliverWebhookDeadLetterParams the parameters of RedeliverWebhookDeadLetter
+type RedeliverWebhookDeadLetterParams struct {
+	Id         string // path, required
+	DeliveryId string // path, required
+}
+
+// RedeliverWebhookDeadLetter queue a dead letter again
+func (c *Client) RedeliverWebhookDeadLetter(ctx context.Context, params RedeliverWebhookDeadLetterParams) error {
+	path := "/api/public/webhooks/{id}/deadLetters/{deliveryId}/redeliver"
+	query := url.Values{}
+	path = strings.Replace(path, "{id}", url.PathEscape(params.Id), 1)
+	path = strings.Replace(path, "{deliveryId}", url.PathEscape(params.DeliveryId), 1)
+	return c.do(ctx, "POST", path, query, nil, nil, nil)
+}
+
+// RestoreAssetParams the parameters of RestoreAsset
+type RestoreAssetParams struct {
+	User            string // path, required
+	ContractAddress string // path, required
+	TokenId         string // path, required
+	Chain           string // query
+}
+
+// RestoreAsset restore a soft deleted asset with its activity and orders
+func (c *Client) RestoreAsset(ctx context.Context, params RestoreAssetParams) error {
+	path := "/api/admin/restore/assets/{user}/{contract_address}/{token_id}"
+	query := url.Values{}
+	path = strings.Replace(path, "{user}", url.PathEscape(params.User), 1)
+	path = strings.Replace(path, "{contract_address}", url.PathEscape(params.ContractAddress), 1)
+	path = strings.Replace(path, "{token_id}", url.PathEscape(params.TokenId), 1)
+	if params.Chain != "" {
+		query.Set("chain", params.Chain)
+	}
+	return c.do(ctx, "POST", path, query, nil, nil, nil)
+}
+
+// RestoreCollectionParams the parameters of RestoreCollection
+type RestoreCollectionParams struct {
+	User  string // path, required
+	Slug  string // path, required
+	Chain string // query
+}
+
+// RestoreCollection restore a soft deleted collection
+func (c *Client) RestoreCollection(ctx context.Context, params RestoreCollectionParams) error {
+	path := "/api/admin/restore/collections/{user}/{slug}"
+	query := url.Values{}
+	path = strings.Replace(path, "{user}", url.PathEscape(params.User), 1)
+	path = strings.Replace(path, "{slug}", url.PathEscape(params.Slug), 1)
+	if params.Chain != "" {
+		query.Set("chain", params.Chain)
+	}
+	return c.do(ctx, "POST", path, query, nil, nil, nil)
+}
+
+// SearchParams the parameters of Search
+type SearchParams struct {
+	Q     string // query, required
+	Type  string // query
+	Limit int64  // query
+	Chain string // query
+}
+
+// Search full text search of assets, collections and users
+func (c *Client) Search(ctx context.Context, params SearchParams) (SearchResult, error) {
+	path := "/api/public/search"
+	query := url.Values{}
+	if params.Q != "" {
+		query.Set("q", params.Q)
+	}
+	if params.Type != "" {
+		query.Set("type", params.Type)
+	}
+	if params.Limit != 0 {
+		query.Set("limit", strconv.FormatInt(params.Limit, 10))
+	}
+	if params.Chain != "" {
+		query.Set("chain", params.Chain)
+	}
+	var data SearchResult
+	err := c.do(ctx, "GET", path, query, nil, &data, nil)
+	return data, err
+}
+
+// SearchCollectionAssetsParams the parameters of SearchCollectionAssets
+type SearchCollectionAssetsParams struct {
+	CollectionId string  // path, required
+	Page         int64   // query
+	PageSize     int64   // query
+	Status       int64   // query
+	SortBy       int64   // query
+	MinPrice     float64 // query
+	MaxPrice     float64 // query
+	Field        string  // query
+	Chain        string  // query
+}
+
+// SearchCollectionAssets assets of a collection by status, price and name
+func (c *Client) SearchCollectionAssets(ctx context.Context, params SearchCollectionAssetsParams) ([]ResponseAssetItem, *PageMetadata, error) {
+	path := "/api/public/search/singleCollectibles/{collectionId}"
+	query := url.Values{}
+	path = strings.Replace(path, "{collectionId}", url.PathEscape(params.CollectionId), 1)
+	if params.Page != 0 {
+		query.Set("page", strconv.FormatInt(params.Page, 10))
+	}
+	if params.PageSize != 0 {
+		query.Set("pageSize", strconv.FormatInt(params.PageSize, 10))
+	}
+	if params.Status != 0 {
+		query.Set("status", strconv.FormatInt(params.Status, 10))
+	}
+	if params.SortBy != 0 {
+		query.Set("sortBy", strconv.FormatInt(params.SortBy, 10))
+	}
+	if params.MinPrice != 0 {
+		query.Set("minPrice", strconv.FormatFloat(params.MinPrice, 'f', -1, 64))
+	}
+	if params.MaxPrice != 0 {
+		query.Set("maxPrice", strconv.FormatFloat(params.MaxPrice, 'f', -1, 64))
+	}
+	if params.Field != "" {
+		query.Set("field", params.Field)
+	}
+	if params.Chain != "" {
+		query.Set("chain", params.Chain)
+	}
+	var data []ResponseAssetItem
+	var metadata PageMetadata
+	if err := c.do(ctx, "GET", path, query, nil, &data, &metadata); err != nil {
+		return data, nil, err
+	}
+	return data, &metadata, nil
+}
+
+// StreamActivitiesParams the parameters of StreamActivities
+type StreamActivitiesParams struct {
+	CollectionId string // query
+	Wallet       string // query
+	Chain        string // query
+}
+
+// StreamActivities new activities and orders as server-sent events of stream.Message
+func (c *Client) StreamActivities(ctx context.Context, params StreamActivitiesParams) (io.ReadCloser, error) {
+	path := "/api/public/stream/activities"
+	query := url.Values{}
+	if params.CollectionId != "" {
+		query.Set("collectionId", params.CollectionId)
+	}
+	if params.Wallet != "" {
+		query.Set("wallet", params.Wallet)
+	}
+	if params.Chain != "" {
+		query.Set("chain", params.Chain)
+	}
+	return c.stream(ctx, "GET", path, query, nil)
+}
+
+// SyncWalletParams the parameters of SyncWallet
+type SyncWalletParams struct {
+	User  string // path, required
+	Chain string // query
+}
+
+// SyncWallet sync the collections and assets of a wallet from opensea
+func (c *Client) SyncWallet(ctx context.Context, params SyncWalletParams) error {
+	path := "/api/public/opensea/sync/{user}"
+	query := url.Values{}
+	path = strings.Replace(path, "{user}", url.PathEscape(params.User), 1)
+	if params.Chain != "" {
+		query.Set("chain", params.Chain)
+	}
+	return c.do(ctx, "GET", path, query, nil, nil, nil)
+}
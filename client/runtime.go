@@ -0,0 +1,139 @@
+// Package client calls the openseasync api. The types and methods in client.go are
+// generated from the OpenAPI specification of the service, regenerate them after a
+// route or a response type changes.
+package client
+
+//go:generate go run ../openapi/clientgen -out .
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Client a client of one server
+type Client struct {
+	BaseURL    string // scheme and host, http://localhost:8888
+	HTTPClient *http.Client
+	Token      string // bearer token sent to the routes requiring one, the admin api
+}
+
+// New a client of the server at baseURL using http.DefaultClient
+func New(baseURL string) *Client {
+	return &Client{BaseURL: strings.TrimRight(baseURL, "/"), HTTPClient: http.DefaultClient}
+}
+
+// PageMetadata paging of a list response
+type PageMetadata struct {
+	Page      int64 `json:"page"`
+	PageSize  int64 `json:"pageSize"`
+	Total     int64 `json:"total"`
+	TotalPage int64 `json:"totalPage"`
+}
+
+// FieldError why a request parameter was rejected
+type FieldError struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// Error a response other than 2xx
+type Error struct {
+	StatusCode int
+	Code       string       `json:"code"`
+	Message    string       `json:"message"`
+	Errors     []FieldError `json:"errors,omitempty"`
+}
+
+func (e *Error) Error() string {
+	if len(e.Errors) > 0 {
+		return fmt.Sprintf("%d %s: %s (%s: %s)", e.StatusCode, e.Code, e.Message, e.Errors[0].Field, e.Errors[0].Message)
+	}
+	return fmt.Sprintf("%d %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+type envelope struct {
+	Status   string          `json:"status"`
+	Code     string          `json:"code"`
+	Data     json.RawMessage `json:"data"`
+	Metadata json.RawMessage `json:"metadata"`
+}
+
+// do send a request and decode the data and metadata of the response envelope
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body interface{}, data interface{}, metadata interface{}) error {
+	response, err := c.send(ctx, method, path, query, body)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	var result envelope
+	if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
+		return fmt.Errorf("decode %s %s: %w", method, path, err)
+	}
+	if data != nil && len(result.Data) > 0 {
+		if err := json.Unmarshal(result.Data, data); err != nil {
+			return fmt.Errorf("decode data of %s %s: %w", method, path, err)
+		}
+	}
+	if metadata != nil && len(result.Metadata) > 0 {
+		if err := json.Unmarshal(result.Metadata, metadata); err != nil {
+			return fmt.Errorf("decode metadata of %s %s: %w", method, path, err)
+		}
+	}
+	return nil
+}
+
+// stream send a request and return the body as is, the caller closes it
+func (c *Client) stream(ctx context.Context, method, path string, query url.Values, body interface{}) (io.ReadCloser, error) {
+	response, err := c.send(ctx, method, path, query, body)
+	if err != nil {
+		return nil, err
+	}
+	return response.Body, nil
+}
+
+func (c *Client) send(ctx context.Context, method, path string, query url.Values, body interface{}) (*http.Response, error) {
+	target := c.BaseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(encoded)
+	}
+	request, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		request.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		defer response.Body.Close()
+		apiErr := &Error{StatusCode: response.StatusCode}
+		_ = json.NewDecoder(response.Body).Decode(apiErr)
+		return nil, apiErr
+	}
+	return response, nil
+}
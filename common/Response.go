@@ -5,12 +5,19 @@ import (
 )
 
 type BasicResponse struct {
-	Status   string      `json:"status"`
-	Code     string      `json:"code"`
-	Data     interface{} `json:"data,omitempty"`
-	MetaData interface{} `json:"metadata,omitempty"`
-	Message  string      `json:"message,omitempty"`
-	PageInfo *PageInfo   `json:"page_info,omitempty"`
+	Status   string       `json:"status"`
+	Code     string       `json:"code"`
+	Data     interface{}  `json:"data,omitempty"`
+	MetaData interface{}  `json:"metadata,omitempty"`
+	Message  string       `json:"message,omitempty"`
+	PageInfo *PageInfo    `json:"page_info,omitempty"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError why a request parameter was rejected
+type FieldError struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
 }
 
 type MetaData struct {
@@ -51,6 +58,15 @@ func CreateErrorResponse(_errCode, _errMsg string) BasicResponse {
 	}
 }
 
+func CreateValidationErrorResponse(_errCode, _errMsg string, _errors []FieldError) BasicResponse {
+	return BasicResponse{
+		Status:  constants.HTTP_STATUS_ERROR,
+		Code:    _errCode,
+		Message: _errMsg,
+		Errors:  _errors,
+	}
+}
+
 func NewSuccessResponseWithPageInfo(_data interface{}, _page *PageInfo) BasicResponse {
 	return BasicResponse{
 		Status:   constants.HTTP_STATUS_SUCCESS,
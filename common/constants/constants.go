@@ -35,6 +35,9 @@ const (
 	TRANSACTION_STATUS_SUCCESS = "success"
 	TRANSACTION_STATUS_FAIL    = "fail"
 
+	URL_API_PREFIX                                 = "/api"
+	URL_OPENAPI                                    = "/openapi.json"
+	URL_DOCS                                       = "/docs"
 	URL_HOST_GET_COMMON                            = "/public"
 	URL_HOST_GET_HOST_INFO                         = "/host/info"
 	URL_OPENSEA_OWNER_ASSETS_SYNC                  = "/opensea/sync/:user"
@@ -43,14 +46,46 @@ const (
 	URL_FIND_COLLECTION_USERMETAMASKID             = "/collectionsByUserId/:usermetamaskid"
 	URL_FIND_COLLECTION_COLLECTIONID               = "/collection/getInfo/:collectionId"
 	URL_FIND_COLLECTION_ITEM_ACTIVITY_COLLECTIONID = "/collection/getItemActivities/:collectionId"
+	URL_FIND_COLLECTION_HOLDERS                    = "/collection/holders/:collectionId"
 	URL_FIND_TRADE_HISTORY                         = "/collectibles/trade/:collectibleId/history"
 	URL_FIND_USER_SOCIALMEDIA                      = "/user/socialMedia/:userMetamaskId"
 	URL_FIND_ASSETS_OFFERRECORDS                   = "/collectibles/offerRecords/:collectibleId"
 	URL_FIND_ASSETS_HIGHESTPRICE                   = "/collectibles/highestPrice/:collectibleId"
 	URL_FIND_ASSETS_OTTHER                         = "/collectibles/otherCollectibles/:collectibleId"
+	URL_FIND_ASSETS_CHANGES                        = "/collectibles/changes/:collectibleId"
 	URL_DELETE_ASSET                               = "/assets/:user/:contract_address/:token_id"
 	URL_DELETE_COLLECTION                          = "/collections/:user/:slug"
 	URL_SYSTEM_CONFIG_PARAMS                       = "/system/params"
+	URL_HEALTHZ                                    = "/healthz"
+	URL_READYZ                                     = "/readyz"
+	URL_METRICS                                    = "/metrics"
+	URL_STREAM_ACTIVITIES                          = "/stream/activities"
+	URL_EXPORT_WALLET_ASSETS                       = "/export/wallet/:user/assets"
+	URL_EXPORT_WALLET_ACTIVITY                     = "/export/wallet/:user/activity"
+	URL_WALLET_PNL                                 = "/wallet/:user/pnl"
+	URL_WALLET_PORTFOLIO                           = "/wallet/:user/portfolio"
+	URL_COLLECTION_RANKINGS                        = "/collections/rankings"
+	URL_SEARCH                                     = "/search"
+	URL_GRAPHQL                                    = "/graphql"
+	URL_WEBHOOKS                                   = "/webhooks"
+	URL_WEBHOOK                                    = "/webhooks/:id"
+	URL_WEBHOOK_DELIVERIES                         = "/webhooks/:id/deliveries"
+	URL_WEBHOOK_DEAD_LETTERS                       = "/webhooks/:id/deadLetters"
+	URL_WEBHOOK_REDELIVER                          = "/webhooks/:id/deadLetters/:deliveryId/redeliver"
+
+	URL_ADMIN_PREFIX               = "/admin"
+	URL_ADMIN_SYNCS                = "/syncs"
+	URL_ADMIN_SYNC                 = "/syncs/:user"
+	URL_ADMIN_SYNC_ERRORS          = "/syncErrors"
+	URL_ADMIN_PURGE                = "/purge"
+	URL_ADMIN_RESTORE_ASSET        = "/restore/assets/:user/:contract_address/:token_id"
+	URL_ADMIN_RESTORE_COLLECTION   = "/restore/collections/:user/:slug"
+	URL_ADMIN_RECOMPUTE_COLLECTION = "/recompute/collections/:collectionId"
+	URL_ADMIN_RECOMPUTE_RANKINGS   = "/recompute/rankings"
+	URL_ADMIN_RECOMPUTE_SUSPICIOUS = "/recompute/suspicious"
+	URL_ADMIN_WALLET               = "/wallets/:user"
+	URL_ADMIN_WALLET_OPT_IN        = "/wallets/:user/optIn"
+	URL_ADMIN_ERASURES             = "/erasures"
 
 	TABLE_NAME_EVENT_BSC     = "event_bsc"
 	TABLE_NAME_EVENT_GOERLI  = "event_goerli"
@@ -63,4 +63,30 @@ const (
 	// ethereum
 	CONTRACT_ADDRESS_ERROR_CODE = "500008001"
 	CONTRACT_ADDRESS_ERROR_MSG  = "Invalid contract address"
+
+	// service status 009
+	SERVICE_NOT_READY_ERROR_CODE = "500009001"
+	SERVICE_NOT_READY_ERROR_MSG  = "Service is not ready"
+
+	// webhook 010
+	WEBHOOK_NOT_FOUND_ERROR_CODE     = "500010001"
+	WEBHOOK_NOT_FOUND_ERROR_MSG      = "Webhook not found"
+	DEAD_LETTER_NOT_FOUND_ERROR_CODE = "500010002"
+	DEAD_LETTER_NOT_FOUND_ERROR_MSG  = "Dead letter not found"
+
+	// admin 011
+	ADMIN_UNAUTHORIZED_ERROR_CODE       = "500011001"
+	ADMIN_UNAUTHORIZED_ERROR_MSG        = "Admin token is missing or wrong"
+	SYNC_RUNNING_ERROR_CODE             = "500011002"
+	SYNC_RUNNING_ERROR_MSG              = "A sync of the wallet is already running"
+	SYNC_NOT_RUNNING_ERROR_CODE         = "500011003"
+	SYNC_NOT_RUNNING_ERROR_MSG          = "No sync of the wallet is running"
+	DELETED_RECORD_NOT_FOUND_ERROR_CODE = "500011004"
+	DELETED_RECORD_NOT_FOUND_ERROR_MSG  = "No such deleted record"
+	COLLECTION_NOT_FOUND_ERROR_CODE     = "500011005"
+	COLLECTION_NOT_FOUND_ERROR_MSG      = "Collection not found"
+	WALLET_ERASED_ERROR_CODE            = "500011006"
+	WALLET_ERASED_ERROR_MSG             = "Wallet was erased, syncing it is blocked until it opts in again"
+	WALLET_NOT_BLOCKED_ERROR_CODE       = "500011007"
+	WALLET_NOT_BLOCKED_ERROR_MSG        = "Wallet is not blocked from syncing"
 )
@@ -11,16 +11,17 @@ import (
 	"math"
 	"math/big"
 	"net/http"
+	"openseasync/chain"
 	constants2 "openseasync/common/constants"
 	"openseasync/config"
 	"openseasync/logs"
+	"openseasync/metrics"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
@@ -57,18 +58,14 @@ func GetRewardPerBlock() *big.Int {
 	return rewardBig
 }
 
+// CheckTx wait for the receipt of tx with the configured number of confirmations
 func CheckTx(client *ethclient.Client, tx *types.Transaction) (*types.Receipt, error) {
-retry:
-	rp, err := client.TransactionReceipt(context.Background(), tx.Hash())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+	rp, err := chain.WaitForConfirmations(ctx, client, tx.Hash(), uint64(config.GetConfig().Chain.Confirmations), time.Second)
 	if err != nil {
-		if err == ethereum.NotFound {
-			logs.GetLogger().Error("tx %v not found, check it later", tx.Hash().String())
-			time.Sleep(1 * time.Second)
-			goto retry
-		} else {
-			logs.GetLogger().Error("TransactionReceipt fail: %s", err)
-			return nil, err
-		}
+		logs.GetLogger().Errorf("TransactionReceipt of tx %v fail: %s", tx.Hash().String(), err)
+		return nil, err
 	}
 	return rp, nil
 }
@@ -194,22 +191,65 @@ func MD5(v string) string {
 	return hex.EncodeToString(h.Sum(nil))
 }
 
-func RequestOpenSeaAssets(owner string, offset, limit int64) ([]byte, error) {
-	openSeaAssetsURL := constants2.OPENSEA_DEV_ASSETS_URL
-	if !config.GetConfig().Dev {
-		openSeaAssetsURL = constants2.OPENSEA_PROD_ASSETS_URL
+// OpenSeaEndpoints opensea urls serving one network
+type OpenSeaEndpoints struct {
+	Assets      string
+	SingleAsset string
+	Collections string
+	Events      string
+	ApiKey      bool // send the api key, testnets don't need one
+}
+
+// GetOpenSeaEndpoints opensea urls of network, from [networks] or the built-in ethereum and goerli ones
+func GetOpenSeaEndpoints(network string) (*OpenSeaEndpoints, error) {
+	if n, ok := config.GetConfig().Networks[network]; ok && n.OpenSeaApiUrl != "" {
+		return &OpenSeaEndpoints{
+			Assets:      UrlJoin(n.OpenSeaApiUrl, "assets"),
+			SingleAsset: UrlJoin(n.OpenSeaApiUrl, "asset"),
+			Collections: UrlJoin(n.OpenSeaApiUrl, "collections"),
+			Events:      UrlJoin(n.OpenSeaApiUrl, "events"),
+			ApiKey:      true,
+		}, nil
+	}
+	switch network {
+	case constants2.NETWORK_TYPE_ETH:
+		return &OpenSeaEndpoints{
+			Assets:      constants2.OPENSEA_PROD_ASSETS_URL,
+			SingleAsset: constants2.OPENSEA_PROD_SINGLE_ASSET_URL,
+			Collections: constants2.OPENSEA_PROD_COLLECTIONS_URL,
+			Events:      constants2.OPENSEA_PROD_EVENT_URL,
+			ApiKey:      true,
+		}, nil
+	case constants2.NETWORK_TYPE_GOERLI:
+		return &OpenSeaEndpoints{
+			Assets:      constants2.OPENSEA_DEV_ASSETS_URL,
+			SingleAsset: constants2.OPENSEA_DEV_SINGLE_ASSET_URL,
+			Collections: constants2.OPENSEA_DEV_COLLECTION_URL,
+			Events:      constants2.OPENSEA_DEV_EVENT_URL,
+		}, nil
+	}
+	return nil, errors.New("no opensea api is known for network " + network)
+}
+
+func RequestOpenSeaAssets(ctx context.Context, network, owner string, offset, limit int64) ([]byte, error) {
+	endpoints, err := GetOpenSeaEndpoints(network)
+	if err != nil {
+		logs.FromContext(ctx).Error(err)
+		return nil, err
 	}
 
-	url := fmt.Sprintf("%s?owner=%s&offset=%d&limit=%d", openSeaAssetsURL, owner, offset, limit)
-	req, _ := http.NewRequest(http.MethodGet, url, nil)
-	if !config.GetConfig().Dev {
+	url := fmt.Sprintf("%s?owner=%s&offset=%d&limit=%d", endpoints.Assets, owner, offset, limit)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if endpoints.ApiKey {
 		req.Header.Add("X-API-KEY", constants2.OPENSEA_API_KEY)
 	}
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		logs.GetLogger().Error(err)
+		metrics.ObserveOpenSeaRequest("assets", 0)
+		logs.FromContext(ctx).Error(err)
 		return nil, err
 	}
+	metrics.ObserveOpenSeaRequest("assets", resp.StatusCode)
 	defer resp.Body.Close()
 	content, _ := ioutil.ReadAll(resp.Body)
 
@@ -219,33 +259,32 @@ func RequestOpenSeaAssets(owner string, offset, limit int64) ([]byte, error) {
 	return content, nil
 }
 
-func RequestOpenSeaCollections(owner string, offset, limit int64) ([]byte, error) {
-	openSeaCollectionsURL := constants2.OPENSEA_DEV_COLLECTION_URL
-	if !config.GetConfig().Dev {
-		openSeaCollectionsURL = constants2.OPENSEA_PROD_COLLECTIONS_URL
+func RequestOpenSeaCollections(ctx context.Context, network, owner string, offset, limit int64) ([]byte, error) {
+	endpoints, err := GetOpenSeaEndpoints(network)
+	if err != nil {
+		logs.FromContext(ctx).Error(err)
+		return nil, err
 	}
-	url := fmt.Sprintf("%s?asset_owner=%s&offset=%d&limit=%d", openSeaCollectionsURL, owner, offset, limit)
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	url := fmt.Sprintf("%s?asset_owner=%s&offset=%d&limit=%d", endpoints.Collections, owner, offset, limit)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		logs.GetLogger().Error(err)
+		logs.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	if !config.GetConfig().Dev {
+	if endpoints.ApiKey {
 		req.Header.Add("X-API-KEY", constants2.OPENSEA_API_KEY)
 	}
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		logs.GetLogger().Error(err)
+		metrics.ObserveOpenSeaRequest("collections", 0)
+		logs.FromContext(ctx).Error(err)
 		return nil, err
 	}
+	metrics.ObserveOpenSeaRequest("collections", resp.StatusCode)
 	defer resp.Body.Close()
 	content, err := ioutil.ReadAll(resp.Body)
-		fmt.Println("+++++++++++++++++++++ Collections Begin ++++++++++++++++++++++++++")
-
-	fmt.Println(string(content))
-	fmt.Println("+++++++++++++++++++++ Collections End ++++++++++++++++++++++++++")
 	if err != nil {
-		logs.GetLogger().Error(err)
+		logs.FromContext(ctx).Error(err)
 		return nil, err
 	}
 	if resp.StatusCode != http.StatusOK {
@@ -255,29 +294,32 @@ func RequestOpenSeaCollections(owner string, offset, limit int64) ([]byte, error
 	return content, nil
 }
 
-func RequestOpenSeaSingleAsset(contractAddress, tokenId string) ([]byte, error) {
-	openSeaSingleAssetURL := constants2.OPENSEA_DEV_SINGLE_ASSET_URL
-	if !config.GetConfig().Dev {
-		openSeaSingleAssetURL = constants2.OPENSEA_PROD_SINGLE_ASSET_URL
+func RequestOpenSeaSingleAsset(ctx context.Context, network, contractAddress, tokenId string) ([]byte, error) {
+	endpoints, err := GetOpenSeaEndpoints(network)
+	if err != nil {
+		logs.FromContext(ctx).Error(err)
+		return nil, err
 	}
-	url := fmt.Sprintf("%s/%s/%s", openSeaSingleAssetURL, contractAddress, tokenId)
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	url := fmt.Sprintf("%s/%s/%s", endpoints.SingleAsset, contractAddress, tokenId)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		logs.GetLogger().Error(err)
+		logs.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	if !config.GetConfig().Dev {
+	if endpoints.ApiKey {
 		req.Header.Add("X-API-KEY", constants2.OPENSEA_API_KEY)
 	}
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		logs.GetLogger().Error(err)
+		metrics.ObserveOpenSeaRequest("asset", 0)
+		logs.FromContext(ctx).Error(err)
 		return nil, err
 	}
+	metrics.ObserveOpenSeaRequest("asset", resp.StatusCode)
 	defer resp.Body.Close()
 	content, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		logs.GetLogger().Error(err)
+		logs.FromContext(ctx).Error(err)
 		return nil, err
 	}
 	if resp.StatusCode != http.StatusOK {
@@ -287,29 +329,32 @@ func RequestOpenSeaSingleAsset(contractAddress, tokenId string) ([]byte, error)
 	return content, nil
 }
 
-func RequestOpenSeaEvent(contractAddress, tokenId string) ([]byte, error) {
-	openSeaEventURL := constants2.OPENSEA_DEV_EVENT_URL
-	if !config.GetConfig().Dev {
-		openSeaEventURL = constants2.OPENSEA_PROD_EVENT_URL
+func RequestOpenSeaEvent(ctx context.Context, network, contractAddress, tokenId string) ([]byte, error) {
+	endpoints, err := GetOpenSeaEndpoints(network)
+	if err != nil {
+		logs.FromContext(ctx).Error(err)
+		return nil, err
 	}
-	url := fmt.Sprintf("%s?asset_contract_address=%s&token_id=%s", openSeaEventURL, contractAddress, tokenId)
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	url := fmt.Sprintf("%s?asset_contract_address=%s&token_id=%s", endpoints.Events, contractAddress, tokenId)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		logs.GetLogger().Error(err)
+		logs.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	if !config.GetConfig().Dev {
+	if endpoints.ApiKey {
 		req.Header.Add("X-API-KEY", constants2.OPENSEA_API_KEY)
 	}
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		logs.GetLogger().Error(err)
+		metrics.ObserveOpenSeaRequest("events", 0)
+		logs.FromContext(ctx).Error(err)
 		return nil, err
 	}
+	metrics.ObserveOpenSeaRequest("events", resp.StatusCode)
 	defer resp.Body.Close()
 	content, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		logs.GetLogger().Error(err)
+		logs.FromContext(ctx).Error(err)
 		return nil, err
 	}
 	
@@ -320,6 +365,35 @@ func RequestOpenSeaEvent(contractAddress, tokenId string) ([]byte, error) {
 	return content, nil
 }
 
+// PingOpenSea check that the opensea api of the default network answers
+func PingOpenSea() error {
+	endpoints, err := GetOpenSeaEndpoints(chain.DefaultNetwork())
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return err
+	}
+	req, err := http.NewRequest(http.MethodGet, endpoints.Assets+"?limit=1", nil)
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return err
+	}
+	if endpoints.ApiKey {
+		req.Header.Add("X-API-KEY", constants2.OPENSEA_API_KEY)
+	}
+	client := http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		metrics.ObserveOpenSeaRequest("ping", 0)
+		return err
+	}
+	defer resp.Body.Close()
+	metrics.ObserveOpenSeaRequest("ping", resp.StatusCode)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("opensea http status:%s", resp.Status)
+	}
+	return nil
+}
+
 func ParseTime(timeStr string) int64 {
 	str := strings.Split(timeStr, ".")[0]
 	str = strings.ReplaceAll(str, "T", " ")
@@ -341,3 +415,15 @@ func EthToWei(value float64) *big.Int {
 
 	return result
 }
+
+// Sleep wait for d, returning ctx.Err() as soon as ctx is done
+func Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
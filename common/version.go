@@ -18,7 +18,7 @@ func GetVersion() string {
 }
 
 type HostInfo struct {
-	SwanMinerVersion string `json:"swan_miner_version""`
+	SwanMinerVersion string `json:"swan_miner_version"`
 	OperatingSystem  string `json:"operating_system"`
 	Architecture     string `json:"architecture"`
 	CPUnNumber       int    `json:"cpu_number"`
@@ -7,9 +7,19 @@ import (
 )
 
 type Configuration struct {
-	Port     string   `toml:"port"`
-	Database database `toml:"database"`
-	Dev      bool     `toml:"dev"`
+	Port      string    `toml:"port"`
+	Database  database  `toml:"database"`
+	Dev       bool      `toml:"dev"`
+	OpenSea   openSea   `toml:"opensea"`
+	Log       Log       `toml:"log"`
+	Chain     chain     `toml:"chain"`
+	Webhook   webhook   `toml:"webhook"`
+	Rankings  rankings  `toml:"rankings"`
+	WashTrade washTrade `toml:"wash_trade"`
+	Cache     cache     `toml:"cache"`
+	Admin     admin     `toml:"admin"`
+
+	Networks map[string]network `toml:"networks"`
 }
 
 type database struct {
@@ -21,6 +31,73 @@ type database struct {
 	DbArgs       string `toml:"db_args"`
 }
 
+type Log struct {
+	Level      string   `toml:"level"`       // overrides the level derived from dev
+	Format     string   `toml:"format"`      // json or text, json by default
+	Output     []string `toml:"output"`      // any of stdout, stderr, file, none
+	File       string   `toml:"file"`        // log file path when output contains file
+	MaxSize    int      `toml:"max_size"`    // megabytes before the log file is rotated
+	MaxBackups int      `toml:"max_backups"` // rotated files to keep
+	MaxAge     int      `toml:"max_age"`     // days to keep rotated files
+	Compress   bool     `toml:"compress"`    // gzip rotated files
+}
+
+type chain struct {
+	Network          string `toml:"network"`           // chain synced and queried when a request names none, empty for goerli in dev and ethereum otherwise
+	RpcUrl           string `toml:"rpc_url"`           // json-rpc endpoint of the default network
+	VerifyOwnership  bool   `toml:"verify_ownership"`  // check opensea ownership against the chain during sync
+	CorrectOwnership bool   `toml:"correct_ownership"` // overwrite the owner and copies with the on-chain values
+
+	Confirmations       int64 `toml:"confirmations"`         // blocks on top of a transfer before it is no longer pending
+	IndexerEnabled      bool  `toml:"indexer_enabled"`       // index Transfer logs of known contracts into item_activitys
+	IndexerStartBlock   int64 `toml:"indexer_start_block"`   // first block indexed for a new contract
+	IndexerBlockBatch   int64 `toml:"indexer_block_batch"`   // blocks per eth_getLogs request
+	IndexerPollInterval int64 `toml:"indexer_poll_interval"` // seconds between two indexing rounds
+}
+
+// network a chain besides the built-in ethereum and goerli ones
+type network struct {
+	RpcUrl        string `toml:"rpc_url"`         // json-rpc endpoint
+	OpenSeaApiUrl string `toml:"opensea_api_url"` // opensea api root serving assets, asset, collections and events
+	ChainId       int64  `toml:"chain_id"`        // eip-155 chain id, order signatures are not verified without it
+}
+
+type webhook struct {
+	Workers       int   `toml:"workers"`        // concurrent deliveries
+	QueueSize     int   `toml:"queue_size"`     // deliveries waiting for a worker before new ones are dead-lettered
+	MaxAttempts   int   `toml:"max_attempts"`   // attempts before a delivery is dead-lettered
+	RetryInterval int64 `toml:"retry_interval"` // seconds before the first retry, doubled on every attempt
+	Timeout       int64 `toml:"timeout"`        // seconds to wait for the receiver to answer
+}
+
+type rankings struct {
+	Enabled         bool  `toml:"enabled"`          // refresh the cached collection rankings in the background
+	RefreshInterval int64 `toml:"refresh_interval"` // seconds between two refreshes
+}
+
+type washTrade struct {
+	Enabled          bool    `toml:"enabled"`            // flag suspicious sales in the background
+	Interval         int64   `toml:"interval"`           // seconds between two analyses
+	Lookback         int64   `toml:"lookback"`           // days of sales analyzed each time
+	RoundTripWindow  int64   `toml:"round_trip_window"`  // hours within which a token sold back to an earlier seller is a round trip
+	PriceDeviation   float64 `toml:"price_deviation"`    // times above or below the collection median a unit price is an outlier
+	ExcludeFromStats bool    `toml:"exclude_from_stats"` // leave flagged sales out of the rankings and portfolio valuations
+}
+
+type cache struct {
+	Enabled    bool             `toml:"enabled"`     // cache the responses of the read routes
+	MaxEntries int              `toml:"max_entries"` // responses kept by the in-process lru
+	Ttl        map[string]int64 `toml:"ttl"`         // seconds by route name, overriding the built-in ones, 0 disables a route
+}
+
+type admin struct {
+	Token string `toml:"token"` // bearer token of the /api/admin routes, every admin request is refused when empty
+}
+
+type openSea struct {
+	ReadyCheck bool `toml:"ready_check"` // readiness also requires opensea to answer
+}
+
 var config *Configuration
 
 func InitConfig(configFile string) {
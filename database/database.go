@@ -2,6 +2,7 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	_ "github.com/jinzhu/gorm/dialects/mysql"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 type Database struct {
@@ -81,3 +83,16 @@ func InitMongo() *mongo.Client {
 func GetMongoClient() *mongo.Database {
 	return mongoClient
 }
+
+// PingMongo check that the mongo primary is reachable
+func PingMongo(ctx context.Context) error {
+	if mongoClient == nil {
+		return errors.New("mongo client is not initialized")
+	}
+	return mongoClient.Client().Ping(ctx, readpref.Primary())
+}
+
+// SetMongoClient use db from now on, for callers connecting on their own such as tests
+func SetMongoClient(db *mongo.Database) {
+	mongoClient = db
+}
@@ -0,0 +1,59 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	EVENT_TYPE_SALE                 = "sale"                 // a new successful sale in item_activitys
+	EVENT_TYPE_BID                  = "bid"                  // a new offer in orders
+	EVENT_TYPE_ASSET_REMOVED        = "asset_removed"        // an asset left the synced wallet
+	EVENT_TYPE_FLOOR_PRICE_CHANGED  = "floor_price_changed"  // a collection floor price moved
+	EVENT_TYPE_ACTIVITY             = "activity"             // any new document in item_activitys
+	EVENT_TYPE_ORDER                = "order"                // any new document in orders
+	EVENT_TYPE_ASSET_UPSERTED       = "asset_upserted"       // a sync inserted or updated an asset of the synced wallet
+	EVENT_TYPE_COLLECTION_UPSERTED  = "collection_upserted"  // a sync inserted or updated a collection of the synced wallet
+	EVENT_TYPE_ORDER_STATUS_CHANGED = "order_status_changed" // a bid or listing was filled, cancelled or expired
+)
+
+// Event something the sync pipeline noticed
+type Event struct {
+	Type            string      `json:"type"`
+	Chain           string      `json:"chain"`
+	Wallets         []string    `json:"wallets"` // wallets involved, the synced one, buyer, seller or maker
+	CollectionId    string      `json:"collectionId"`
+	CollectibleId   int         `json:"collectibleId,omitempty"`
+	ContractAddress string      `json:"contractAddress,omitempty"`
+	TokenId         string      `json:"tokenId,omitempty"`
+	Data            interface{} `json:"data"`
+	CreateDate      int64       `json:"createDate"`
+}
+
+// Handler receive published events, it must not block the publisher
+type Handler func(ctx context.Context, event Event)
+
+var (
+	handlers     []Handler
+	handlersLock sync.RWMutex
+)
+
+// Subscribe call handler for every event published from now on
+func Subscribe(handler Handler) {
+	handlersLock.Lock()
+	defer handlersLock.Unlock()
+	handlers = append(handlers, handler)
+}
+
+// Publish hand event to every subscriber
+func Publish(ctx context.Context, event Event) {
+	if event.CreateDate == 0 {
+		event.CreateDate = time.Now().UnixMilli()
+	}
+	handlersLock.RLock()
+	defer handlersLock.RUnlock()
+	for _, handler := range handlers {
+		handler(ctx, event)
+	}
+}
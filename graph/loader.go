@@ -0,0 +1,91 @@
+package graph
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	LOADER_WAIT      = time.Millisecond // how long a batch stays open for sibling resolvers
+	LOADER_MAX_BATCH = 100
+)
+
+// batchFunc the values of keys in one query, a key without a value is left out
+type batchFunc func(ctx context.Context, keys []string) (map[string]interface{}, error)
+
+// loader collects the keys resolvers ask for during LOADER_WAIT and fetches them in one batch,
+// every value is cached for the rest of the request
+type loader struct {
+	ctx   context.Context
+	fetch batchFunc
+
+	mu    sync.Mutex
+	cache map[string]*loaded
+	batch []string
+	timer *time.Timer
+}
+
+type loaded struct {
+	done  chan struct{}
+	value interface{}
+	err   error
+}
+
+func newLoader(ctx context.Context, fetch batchFunc) *loader {
+	return &loader{ctx: ctx, fetch: fetch, cache: make(map[string]*loaded)}
+}
+
+// Load the value of key, nil when there is none
+func (l *loader) Load(key string) (interface{}, error) {
+	l.mu.Lock()
+	entry, ok := l.cache[key]
+	if !ok {
+		entry = &loaded{done: make(chan struct{})}
+		l.cache[key] = entry
+		l.batch = append(l.batch, key)
+		if len(l.batch) >= LOADER_MAX_BATCH {
+			l.dispatchLocked()
+		} else if l.timer == nil {
+			l.timer = time.AfterFunc(LOADER_WAIT, l.dispatch)
+		}
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-entry.done:
+		return entry.value, entry.err
+	case <-l.ctx.Done():
+		return nil, l.ctx.Err()
+	}
+}
+
+func (l *loader) dispatch() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.dispatchLocked()
+}
+
+// dispatchLocked fetch the open batch, called with mu held
+func (l *loader) dispatchLocked() {
+	if l.timer != nil {
+		l.timer.Stop()
+		l.timer = nil
+	}
+	keys := l.batch
+	l.batch = nil
+	if len(keys) == 0 {
+		return
+	}
+	entries := make([]*loaded, len(keys))
+	for i, key := range keys {
+		entries[i] = l.cache[key]
+	}
+	go func() {
+		values, err := l.fetch(l.ctx, keys)
+		for i, key := range keys {
+			entries[i].value, entries[i].err = values[key], err
+			close(entries[i].done)
+		}
+	}()
+}
@@ -0,0 +1,214 @@
+package graph
+
+import (
+	"context"
+	"openseasync/models"
+	"strconv"
+	"strings"
+)
+
+// Loaders the loaders of one request, a key is chain|id, or chain|limit|id for lists
+type Loaders struct {
+	assets                 *loader
+	assetsByCollection     *loader
+	assetsByUser           *loader
+	collections            *loader
+	collectionsByUser      *loader
+	users                  *loader
+	contracts              *loader
+	orders                 *loader
+	activitiesByAsset      *loader
+	activitiesByCollection *loader
+}
+
+type loadersKey struct{}
+
+// WithLoaders ctx carrying fresh loaders, one set per request so nothing is cached across requests
+func WithLoaders(ctx context.Context) context.Context {
+	l := &Loaders{
+		assets:                 newLoader(ctx, loadAssets),
+		assetsByCollection:     newLoader(ctx, loadAssetLists("collectionId")),
+		assetsByUser:           newLoader(ctx, loadAssetLists("userMetamaskId")),
+		collections:            newLoader(ctx, loadCollections),
+		collectionsByUser:      newLoader(ctx, loadCollectionsByUser),
+		users:                  newLoader(ctx, loadUsers),
+		contracts:              newLoader(ctx, loadContracts),
+		orders:                 newLoader(ctx, loadOrders),
+		activitiesByAsset:      newLoader(ctx, loadActivityLists("collectibleId")),
+		activitiesByCollection: newLoader(ctx, loadActivityLists("collectionId")),
+	}
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *Loaders {
+	return ctx.Value(loadersKey{}).(*Loaders)
+}
+
+func joinKey(parts ...string) string {
+	return strings.Join(parts, "|")
+}
+
+// groupKeys the ids of keys by everything before the last part of the key
+func groupKeys(keys []string) map[string][]string {
+	groups := make(map[string][]string)
+	for _, key := range keys {
+		i := strings.LastIndex(key, "|")
+		groups[key[:i]] = append(groups[key[:i]], key[i+1:])
+	}
+	return groups
+}
+
+// listGroup the chain and limit of a chain|limit group
+func listGroup(group string) (string, int64) {
+	i := strings.LastIndex(group, "|")
+	limit, _ := strconv.ParseInt(group[i+1:], 10, 64)
+	return group[:i], limit
+}
+
+func parseIds(ids []string) []int64 {
+	result := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if n, err := strconv.ParseInt(id, 10, 64); err == nil {
+			result = append(result, n)
+		}
+	}
+	return result
+}
+
+func loadAssets(ctx context.Context, keys []string) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	for network, ids := range groupKeys(keys) {
+		assets, err := models.FindAssetsByIds(network, parseIds(ids))
+		if err != nil {
+			return nil, err
+		}
+		for i := range assets {
+			values[joinKey(network, strconv.Itoa(assets[i].Id))] = &assets[i]
+		}
+	}
+	return values, nil
+}
+
+func loadAssetLists(field string) batchFunc {
+	return func(ctx context.Context, keys []string) (map[string]interface{}, error) {
+		values := make(map[string]interface{})
+		for group, ids := range groupKeys(keys) {
+			network, limit := listGroup(group)
+			assets, err := models.FindAssetsByKeys(network, field, ids, limit)
+			if err != nil {
+				return nil, err
+			}
+			for _, asset := range assets {
+				owner := asset.CollectionID
+				if field == "userMetamaskId" {
+					owner = asset.UserMetamaskID
+				}
+				key := joinKey(group, owner)
+				list, _ := values[key].([]models.Asset)
+				values[key] = append(list, asset)
+			}
+		}
+		return values, nil
+	}
+}
+
+func loadCollections(ctx context.Context, keys []string) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	for network, ids := range groupKeys(keys) {
+		collections, err := models.FindCollectionsByIds(network, ids)
+		if err != nil {
+			return nil, err
+		}
+		for i := range collections {
+			values[joinKey(network, collections[i].ID)] = &collections[i]
+		}
+	}
+	return values, nil
+}
+
+func loadCollectionsByUser(ctx context.Context, keys []string) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	for group, users := range groupKeys(keys) {
+		network, limit := listGroup(group)
+		collections, err := models.FindCollectionsByUsers(network, users, limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, collection := range collections {
+			key := joinKey(group, collection.UserMetamaskID)
+			list, _ := values[key].([]models.Collection)
+			values[key] = append(list, collection)
+		}
+	}
+	return values, nil
+}
+
+// loadUsers users are not kept per chain, their keys are the bare wallet
+func loadUsers(ctx context.Context, keys []string) (map[string]interface{}, error) {
+	users, err := models.FindUsersByMetamaskIds(keys)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]interface{})
+	for i := range users {
+		values[users[i].UserMetamaskID] = &users[i]
+	}
+	return values, nil
+}
+
+func loadContracts(ctx context.Context, keys []string) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	for network, addresses := range groupKeys(keys) {
+		contracts, err := models.FindContractsByAddresses(network, addresses)
+		if err != nil {
+			return nil, err
+		}
+		for i := range contracts {
+			values[joinKey(network, contracts[i].Address)] = &contracts[i]
+		}
+	}
+	return values, nil
+}
+
+func loadOrders(ctx context.Context, keys []string) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	for network, ids := range groupKeys(keys) {
+		orders, err := models.FindOrdersByCollectibleIds(network, parseIds(ids))
+		if err != nil {
+			return nil, err
+		}
+		for _, order := range orders {
+			key := joinKey(network, strconv.Itoa(order.CollectibleId))
+			list, _ := values[key].([]models.Orders)
+			values[key] = append(list, order)
+		}
+	}
+	return values, nil
+}
+
+func loadActivityLists(field string) batchFunc {
+	return func(ctx context.Context, keys []string) (map[string]interface{}, error) {
+		values := make(map[string]interface{})
+		for group, ids := range groupKeys(keys) {
+			network, limit := listGroup(group)
+			var in interface{} = ids
+			if field == "collectibleId" {
+				in = parseIds(ids)
+			}
+			activities, err := models.FindItemActivitiesByKeys(network, field, in, limit)
+			if err != nil {
+				return nil, err
+			}
+			for _, activity := range activities {
+				owner := activity.CollectionId
+				if field == "collectibleId" {
+					owner = strconv.Itoa(activity.CollectibleId)
+				}
+				key := joinKey(group, owner)
+				list, _ := values[key].([]models.ItemActivity)
+				values[key] = append(list, activity)
+			}
+		}
+		return values, nil
+	}
+}
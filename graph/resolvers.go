@@ -0,0 +1,411 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"openseasync/chain"
+	"openseasync/models"
+	"strconv"
+	"strings"
+)
+
+const MAX_FIRST = 100
+
+// Resolver the root of the schema
+type Resolver struct{}
+
+// chainArg the chain argument, normalized, every chain when empty
+func chainArg(value *string) (string, error) {
+	if value == nil || *value == "" {
+		return "", nil
+	}
+	network := strings.ToLower(strings.TrimSpace(*value))
+	if !chain.IsSupportedNetwork(network) {
+		return "", fmt.Errorf("unsupported chain %s", *value)
+	}
+	return network, nil
+}
+
+// first a list size argument within 1 and MAX_FIRST
+func first(value int32) int64 {
+	if value < 1 {
+		return 1
+	}
+	if value > MAX_FIRST {
+		return MAX_FIRST
+	}
+	return int64(value)
+}
+
+func loadAsset(ctx context.Context, network string, collectibleId int) (*assetResolver, error) {
+	value, err := loadersFrom(ctx).assets.Load(joinKey(network, strconv.Itoa(collectibleId)))
+	if err != nil || value == nil {
+		return nil, err
+	}
+	return &assetResolver{value.(*models.Asset)}, nil
+}
+
+func loadCollection(ctx context.Context, network, collectionId string) (*collectionResolver, error) {
+	if collectionId == "" {
+		return nil, nil
+	}
+	value, err := loadersFrom(ctx).collections.Load(joinKey(network, collectionId))
+	if err != nil || value == nil {
+		return nil, err
+	}
+	return &collectionResolver{value.(*models.Collection)}, nil
+}
+
+func loadUser(ctx context.Context, userMetamaskId string) (*userResolver, error) {
+	if userMetamaskId == "" {
+		return nil, nil
+	}
+	value, err := loadersFrom(ctx).users.Load(strings.ToLower(userMetamaskId))
+	if err != nil || value == nil {
+		return nil, err
+	}
+	return &userResolver{value.(*models.User)}, nil
+}
+
+func loadAssetList(ctx context.Context, l *loader, network string, limit int64, owner string) ([]*assetResolver, error) {
+	value, err := l.Load(joinKey(network, strconv.FormatInt(limit, 10), owner))
+	if err != nil {
+		return nil, err
+	}
+	assets, _ := value.([]models.Asset)
+	result := make([]*assetResolver, len(assets))
+	for i := range assets {
+		result[i] = &assetResolver{&assets[i]}
+	}
+	return result, nil
+}
+
+func loadActivityList(ctx context.Context, l *loader, network string, limit int64, owner string) ([]*activityResolver, error) {
+	value, err := l.Load(joinKey(network, strconv.FormatInt(limit, 10), owner))
+	if err != nil {
+		return nil, err
+	}
+	activities, _ := value.([]models.ItemActivity)
+	result := make([]*activityResolver, len(activities))
+	for i := range activities {
+		result[i] = &activityResolver{&activities[i]}
+	}
+	return result, nil
+}
+
+func (r *Resolver) Asset(ctx context.Context, args struct {
+	CollectibleId int32
+	Chain         *string
+}) (*assetResolver, error) {
+	network, err := chainArg(args.Chain)
+	if err != nil {
+		return nil, err
+	}
+	return loadAsset(ctx, network, int(args.CollectibleId))
+}
+
+func (r *Resolver) Assets(ctx context.Context, args struct {
+	CollectionId string
+	Chain        *string
+	First        int32
+}) ([]*assetResolver, error) {
+	network, err := chainArg(args.Chain)
+	if err != nil {
+		return nil, err
+	}
+	return loadAssetList(ctx, loadersFrom(ctx).assetsByCollection, network, first(args.First), args.CollectionId)
+}
+
+func (r *Resolver) Collection(ctx context.Context, args struct {
+	CollectionId string
+	Chain        *string
+}) (*collectionResolver, error) {
+	network, err := chainArg(args.Chain)
+	if err != nil {
+		return nil, err
+	}
+	return loadCollection(ctx, network, args.CollectionId)
+}
+
+func (r *Resolver) User(ctx context.Context, args struct{ UserMetamaskId string }) (*userResolver, error) {
+	return loadUser(ctx, args.UserMetamaskId)
+}
+
+func (r *Resolver) Contract(ctx context.Context, args struct {
+	Address string
+	Chain   *string
+}) (*contractResolver, error) {
+	network, err := chainArg(args.Chain)
+	if err != nil {
+		return nil, err
+	}
+	value, err := loadersFrom(ctx).contracts.Load(joinKey(network, strings.ToLower(args.Address)))
+	if err != nil || value == nil {
+		return nil, err
+	}
+	return &contractResolver{value.(*models.Contract)}, nil
+}
+
+type assetResolver struct{ a *models.Asset }
+
+func (r *assetResolver) CollectibleId() int32    { return int32(r.a.Id) }
+func (r *assetResolver) Chain() string           { return r.a.Chain }
+func (r *assetResolver) Name() string            { return r.a.CollectibleName }
+func (r *assetResolver) Description() string     { return r.a.Description }
+func (r *assetResolver) TokenId() string         { return r.a.CollectibleTokenId }
+func (r *assetResolver) ContractAddress() string { return r.a.ContractAddress }
+func (r *assetResolver) CoverImageUrl() string   { return r.a.CoverImageUrl }
+func (r *assetResolver) ThumbnailUrl() string    { return r.a.ThumbnailUrl }
+func (r *assetResolver) AnimationUrl() string    { return r.a.AnimationUrl }
+func (r *assetResolver) Price() string           { return r.a.Price }
+func (r *assetResolver) Status() string          { return r.a.Status }
+func (r *assetResolver) NumSales() int32         { return int32(r.a.NumSales) }
+func (r *assetResolver) OwnerMetamaskId() string { return r.a.OwnerMetamaskId }
+func (r *assetResolver) OnChainOwner() string    { return r.a.OnChainOwner }
+func (r *assetResolver) OwnershipMismatch() bool { return r.a.OwnershipMismatch }
+func (r *assetResolver) CreateDate() float64     { return float64(r.a.CreateDate) }
+
+func (r *assetResolver) Collection(ctx context.Context) (*collectionResolver, error) {
+	return loadCollection(ctx, r.a.Chain, r.a.CollectionID)
+}
+
+func (r *assetResolver) Contract(ctx context.Context) (*contractResolver, error) {
+	value, err := loadersFrom(ctx).contracts.Load(joinKey(r.a.Chain, r.a.ContractAddress))
+	if err != nil || value == nil {
+		return nil, err
+	}
+	return &contractResolver{value.(*models.Contract)}, nil
+}
+
+func (r *assetResolver) Creator(ctx context.Context) (*userResolver, error) {
+	return loadUser(ctx, r.a.CreatorMetamaskId)
+}
+
+func (r *assetResolver) Owner(ctx context.Context) (*userResolver, error) {
+	return loadUser(ctx, r.a.OwnerMetamaskId)
+}
+
+func (r *assetResolver) orders(ctx context.Context) ([]models.Orders, error) {
+	value, err := loadersFrom(ctx).orders.Load(joinKey(r.a.Chain, strconv.Itoa(r.a.Id)))
+	if err != nil {
+		return nil, err
+	}
+	orders, _ := value.([]models.Orders)
+	return orders, nil
+}
+
+func (r *assetResolver) Orders(ctx context.Context, args struct{ TradeType *string }) ([]*orderResolver, error) {
+	orders, err := r.orders(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*orderResolver, 0, len(orders))
+	for i := range orders {
+		if args.TradeType == nil || orders[i].TradeType == *args.TradeType {
+			result = append(result, &orderResolver{&orders[i]})
+		}
+	}
+	return result, nil
+}
+
+func (r *assetResolver) HighestBid(ctx context.Context) (*orderResolver, error) {
+	orders, err := r.orders(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		highest *orderResolver
+		max     *big.Float
+	)
+	for i := range orders {
+		if orders[i].TradeType != "onAuction" {
+			continue
+		}
+		price, ok := new(big.Float).SetString(orders[i].Price)
+		if !ok {
+			continue
+		}
+		if max == nil || price.Cmp(max) > 0 {
+			highest, max = &orderResolver{&orders[i]}, price
+		}
+	}
+	return highest, nil
+}
+
+func (r *assetResolver) Activities(ctx context.Context, args struct{ First int32 }) ([]*activityResolver, error) {
+	return loadActivityList(ctx, loadersFrom(ctx).activitiesByAsset, r.a.Chain, first(args.First), strconv.Itoa(r.a.Id))
+}
+
+func (r *assetResolver) OtherAssets(ctx context.Context, args struct{ First int32 }) ([]*assetResolver, error) {
+	limit := first(args.First)
+	// one more than asked for, the asset itself is among them
+	assets, err := loadAssetList(ctx, loadersFrom(ctx).assetsByCollection, r.a.Chain, limit+1, r.a.CollectionID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*assetResolver, 0, limit)
+	for _, asset := range assets {
+		if asset.a.Id != r.a.Id && int64(len(result)) < limit {
+			result = append(result, asset)
+		}
+	}
+	return result, nil
+}
+
+type collectionResolver struct{ c *models.Collection }
+
+func (r *collectionResolver) CollectionId() string   { return r.c.ID }
+func (r *collectionResolver) Chain() string          { return r.c.Chain }
+func (r *collectionResolver) Name() string           { return r.c.CollectionName }
+func (r *collectionResolver) Description() string    { return r.c.Description }
+func (r *collectionResolver) CoverImageUrl() string  { return r.c.CoverImageUrl }
+func (r *collectionResolver) BannerImageUrl() string { return r.c.UserCoverUrl }
+func (r *collectionResolver) FloorPrice() string     { return r.c.FloorPrice }
+func (r *collectionResolver) HighestPrice() string   { return r.c.HighestPrice }
+func (r *collectionResolver) TotalVolume() float64   { return r.c.TotalVolume }
+func (r *collectionResolver) ItemsCount() int32      { return int32(r.c.ItemsCount) }
+func (r *collectionResolver) OwnersCount() int32     { return int32(r.c.OwnersCount) }
+func (r *collectionResolver) CreateDate() float64    { return float64(r.c.CreateDate) }
+
+func (r *collectionResolver) Creator(ctx context.Context) (*userResolver, error) {
+	return loadUser(ctx, r.c.CreatorMetamaskId)
+}
+
+func (r *collectionResolver) Assets(ctx context.Context, args struct{ First int32 }) ([]*assetResolver, error) {
+	return loadAssetList(ctx, loadersFrom(ctx).assetsByCollection, r.c.Chain, first(args.First), r.c.ID)
+}
+
+func (r *collectionResolver) Activities(ctx context.Context, args struct{ First int32 }) ([]*activityResolver, error) {
+	return loadActivityList(ctx, loadersFrom(ctx).activitiesByCollection, r.c.Chain, first(args.First), r.c.ID)
+}
+
+type userResolver struct{ u *models.User }
+
+func (r *userResolver) UserMetamaskId() string   { return r.u.UserMetamaskID }
+func (r *userResolver) UserName() string         { return r.u.Username }
+func (r *userResolver) AvatarUrl() string        { return r.u.AvatarUrl }
+func (r *userResolver) DiscordLink() string      { return r.u.DiscordLink }
+func (r *userResolver) TelegramLink() string     { return r.u.TelegramLink }
+func (r *userResolver) InstagramLink() string    { return r.u.InstagramLink }
+func (r *userResolver) TwitterLink() string      { return r.u.TwitterLink }
+func (r *userResolver) PersonalPageLink() string { return r.u.PersonalPageLink }
+
+func (r *userResolver) Collections(ctx context.Context, args struct {
+	Chain *string
+	First int32
+}) ([]*collectionResolver, error) {
+	network, err := chainArg(args.Chain)
+	if err != nil {
+		return nil, err
+	}
+	value, err := loadersFrom(ctx).collectionsByUser.Load(joinKey(network, strconv.FormatInt(first(args.First), 10), r.u.UserMetamaskID))
+	if err != nil {
+		return nil, err
+	}
+	collections, _ := value.([]models.Collection)
+	result := make([]*collectionResolver, len(collections))
+	for i := range collections {
+		result[i] = &collectionResolver{&collections[i]}
+	}
+	return result, nil
+}
+
+func (r *userResolver) Assets(ctx context.Context, args struct {
+	Chain *string
+	First int32
+}) ([]*assetResolver, error) {
+	network, err := chainArg(args.Chain)
+	if err != nil {
+		return nil, err
+	}
+	return loadAssetList(ctx, loadersFrom(ctx).assetsByUser, network, first(args.First), r.u.UserMetamaskID)
+}
+
+type orderResolver struct{ o *models.Orders }
+
+func (r *orderResolver) Id() string           { return r.o.Id }
+func (r *orderResolver) Chain() string        { return r.o.Chain }
+func (r *orderResolver) CollectibleId() int32 { return int32(r.o.CollectibleId) }
+func (r *orderResolver) Price() string        { return r.o.Price }
+func (r *orderResolver) BasePrice() string    { return r.o.BasePrice }
+func (r *orderResolver) TradeType() string    { return r.o.TradeType }
+func (r *orderResolver) PaymentToken() string { return r.o.PayTokenContract.Symbol }
+func (r *orderResolver) StartTime() float64   { return float64(r.o.StartTime) }
+func (r *orderResolver) BidTime() float64     { return float64(r.o.BidTime) }
+func (r *orderResolver) EndTime() float64     { return float64(r.o.EndTime) }
+func (r *orderResolver) StatusTime() float64  { return float64(r.o.StatusTime) }
+func (r *orderResolver) Protocol() string     { return r.o.Protocol }
+func (r *orderResolver) Signer() string       { return r.o.Signer }
+
+// Status orders synced before statuses were tracked are active
+func (r *orderResolver) Status() string {
+	if r.o.Status == "" {
+		return models.ORDER_STATUS_ACTIVE
+	}
+	return r.o.Status
+}
+
+// SignatureStatus orders synced before signatures were verified are unverified
+func (r *orderResolver) SignatureStatus() string {
+	if r.o.SignatureStatus == "" {
+		return chain.SIGNATURE_UNVERIFIED
+	}
+	return r.o.SignatureStatus
+}
+
+func (r *orderResolver) Asset(ctx context.Context) (*assetResolver, error) {
+	return loadAsset(ctx, r.o.Chain, r.o.CollectibleId)
+}
+
+func (r *orderResolver) Bidder(ctx context.Context) (*userResolver, error) {
+	return loadUser(ctx, r.o.AuctionMetamaskId)
+}
+
+type activityResolver struct{ a *models.ItemActivity }
+
+func (r *activityResolver) Id() int32               { return int32(r.a.Id) }
+func (r *activityResolver) Chain() string           { return r.a.Chain }
+func (r *activityResolver) TradeType() string       { return r.a.TradeType }
+func (r *activityResolver) Price() string           { return r.a.Price }
+func (r *activityResolver) PriceInUsd() string      { return r.a.PriceInUsd }
+func (r *activityResolver) Quantity() string        { return r.a.Quantity }
+func (r *activityResolver) PaymentToken() string    { return r.a.PayTokenContract.Symbol }
+func (r *activityResolver) TransactionHash() string { return r.a.Transaction.TransactionHash }
+func (r *activityResolver) Source() string          { return r.a.Source }
+func (r *activityResolver) Suspicious() bool        { return r.a.Suspicious }
+func (r *activityResolver) CreateDate() float64     { return float64(r.a.CreateDate) }
+
+func (r *activityResolver) SuspiciousReasons() []string {
+	if r.a.SuspiciousReasons == nil {
+		return []string{}
+	}
+	return r.a.SuspiciousReasons
+}
+
+func (r *activityResolver) Asset(ctx context.Context) (*assetResolver, error) {
+	return loadAsset(ctx, r.a.Chain, r.a.CollectibleId)
+}
+
+func (r *activityResolver) Collection(ctx context.Context) (*collectionResolver, error) {
+	return loadCollection(ctx, r.a.Chain, r.a.CollectionId)
+}
+
+func (r *activityResolver) Seller(ctx context.Context) (*userResolver, error) {
+	return loadUser(ctx, r.a.SellerMetamaskId)
+}
+
+func (r *activityResolver) Buyer(ctx context.Context) (*userResolver, error) {
+	return loadUser(ctx, r.a.BuyerMetamaskId)
+}
+
+type contractResolver struct{ c *models.Contract }
+
+func (r *contractResolver) Address() string      { return r.c.Address }
+func (r *contractResolver) Chain() string        { return r.c.Chain }
+func (r *contractResolver) Name() string         { return r.c.ContractName }
+func (r *contractResolver) Symbol() string       { return r.c.Symbol }
+func (r *contractResolver) ContractType() string { return r.c.ContractType }
+func (r *contractResolver) SchemaName() string   { return r.c.SchemaName }
+func (r *contractResolver) TotalSupply() string  { return r.c.TotalSupply }
+func (r *contractResolver) Description() string  { return r.c.Description }
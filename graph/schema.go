@@ -0,0 +1,142 @@
+package graph
+
+import (
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+// SCHEMA what /graphql answers, every relation is resolved through the loaders of the request
+const SCHEMA = `
+schema {
+	query: Query
+}
+
+type Query {
+	# an asset by collectible id
+	asset(collectibleId: Int!, chain: String): Asset
+	# the newest assets of a collection
+	assets(collectionId: String!, chain: String, first: Int = 20): [Asset!]!
+	collection(collectionId: String!, chain: String): Collection
+	user(userMetamaskId: String!): User
+	contract(address: String!, chain: String): Contract
+}
+
+type Asset {
+	collectibleId: Int!
+	chain: String!
+	name: String!
+	description: String!
+	tokenId: String!
+	contractAddress: String!
+	coverImageUrl: String!
+	thumbnailUrl: String!
+	animationUrl: String!
+	price: String!
+	status: String!
+	numSales: Int!
+	ownerMetamaskId: String!
+	onChainOwner: String!
+	ownershipMismatch: Boolean!
+	createDate: Float!
+	collection: Collection
+	contract: Contract
+	creator: User
+	owner: User
+	# live orders, of tradeType only when given
+	orders(tradeType: String): [Order!]!
+	# the auction bid with the highest price
+	highestBid: Order
+	# the newest sales, bids and transfers
+	activities(first: Int = 20): [ItemActivity!]!
+	# the newest other assets of the collection
+	otherAssets(first: Int = 4): [Asset!]!
+}
+
+type Collection {
+	collectionId: String!
+	chain: String!
+	name: String!
+	description: String!
+	coverImageUrl: String!
+	bannerImageUrl: String!
+	floorPrice: String!
+	highestPrice: String!
+	totalVolume: Float!
+	itemsCount: Int!
+	ownersCount: Int!
+	createDate: Float!
+	creator: User
+	assets(first: Int = 20): [Asset!]!
+	activities(first: Int = 20): [ItemActivity!]!
+}
+
+type User {
+	userMetamaskId: String!
+	userName: String!
+	avatarUrl: String!
+	discordLink: String!
+	telegramLink: String!
+	instagramLink: String!
+	twitterLink: String!
+	personalPageLink: String!
+	collections(chain: String, first: Int = 20): [Collection!]!
+	assets(chain: String, first: Int = 20): [Asset!]!
+}
+
+type Order {
+	id: String!
+	chain: String!
+	collectibleId: Int!
+	price: String!
+	basePrice: String!
+	tradeType: String!
+	paymentToken: String!
+	startTime: Float!
+	bidTime: Float!
+	endTime: Float!
+	status: String!
+	statusTime: Float!
+	protocol: String!
+	signatureStatus: String!
+	signer: String!
+	asset: Asset
+	bidder: User
+}
+
+type ItemActivity {
+	id: Int!
+	chain: String!
+	tradeType: String!
+	price: String!
+	priceInUsd: String!
+	quantity: String!
+	paymentToken: String!
+	transactionHash: String!
+	source: String!
+	suspicious: Boolean!
+	suspiciousReasons: [String!]!
+	createDate: Float!
+	asset: Asset
+	collection: Collection
+	seller: User
+	buyer: User
+}
+
+type Contract {
+	address: String!
+	chain: String!
+	name: String!
+	symbol: String!
+	contractType: String!
+	schemaName: String!
+	totalSupply: String!
+	description: String!
+}
+`
+
+// MAX_PARALLELISM resolvers of a request run at once, the more run the fuller each loader batch
+const MAX_PARALLELISM = 50
+
+// NewSchema the parsed schema bound to its resolvers
+func NewSchema() (*graphql.Schema, error) {
+	return graphql.ParseSchema(SCHEMA, &Resolver{}, graphql.MaxParallelism(MAX_PARALLELISM))
+}
@@ -0,0 +1,178 @@
+package indexer
+
+import (
+	"context"
+	"openseasync/chain"
+	"openseasync/config"
+	"openseasync/logs"
+	"openseasync/models"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultBlockBatch   = 2000
+	defaultPollInterval = 15 * time.Second
+)
+
+// Run index transfers of the known contracts of network until ctx is done
+func Run(ctx context.Context, backend chain.Backend, network string) {
+	conf := config.GetConfig().Chain
+	interval := defaultPollInterval
+	if conf.IndexerPollInterval > 0 {
+		interval = time.Duration(conf.IndexerPollInterval) * time.Second
+	}
+	ctx = logs.WithFields(ctx, logrus.Fields{"component": "indexer", "chain": network})
+
+	for {
+		if err := IndexOnce(ctx, backend, network); err != nil {
+			logs.FromContext(ctx).Error(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// IndexOnce bring every known contract of network up to the current head
+func IndexOnce(ctx context.Context, backend chain.Backend, network string) error {
+	header, err := backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		logs.FromContext(ctx).Error(err)
+		return err
+	}
+	contracts, err := models.FindIndexedContracts(network)
+	if err != nil {
+		logs.FromContext(ctx).Error(err)
+		return err
+	}
+	for _, contract := range contracts {
+		if err := IndexContract(ctx, backend, network, contract, header.Number.Int64()); err != nil {
+			logs.FromContext(ctx).Error(err)
+			return err
+		}
+	}
+	return nil
+}
+
+// IndexContract index the transfers of contract from its last indexed block up to head,
+// after rolling back what a reorg replaced, and confirm the ones buried deep enough
+func IndexContract(ctx context.Context, backend chain.Backend, network, contract string, head int64) error {
+	conf := config.GetConfig().Chain
+	batch := int64(defaultBlockBatch)
+	if conf.IndexerBlockBatch > 0 {
+		batch = conf.IndexerBlockBatch
+	}
+	confirmations := conf.Confirmations
+	if confirmations < 0 {
+		confirmations = 0
+	}
+	ctx = logs.WithFields(ctx, logrus.Fields{"contract": contract})
+
+	state, err := models.FindIndexerState(network, contract)
+	if err != nil {
+		logs.FromContext(ctx).Error(err)
+		return err
+	}
+	from := conf.IndexerStartBlock
+	if state != nil {
+		from = state.LastBlock + 1
+		reorgBlock, err := findReorg(ctx, backend, network, contract, state, confirmations)
+		if err != nil {
+			logs.FromContext(ctx).Error(err)
+			return err
+		}
+		if reorgBlock >= 0 {
+			if err = models.RollbackChainActivities(ctx, network, contract, reorgBlock); err != nil {
+				logs.FromContext(ctx).Error(err)
+				return err
+			}
+			from = reorgBlock
+		}
+	}
+
+	for from <= head {
+		to := from + batch - 1
+		if to > head {
+			to = head
+		}
+		transfers, err := chain.FetchTransfers(ctx, backend, []common.Address{common.HexToAddress(contract)}, uint64(from), uint64(to))
+		if err != nil {
+			logs.FromContext(ctx).Error(err)
+			return err
+		}
+		if err = models.InsertChainTransfers(ctx, network, transfers, uint64(head), uint64(confirmations)); err != nil {
+			logs.FromContext(ctx).Error(err)
+			return err
+		}
+		toHash, err := chain.CanonicalHash(ctx, backend, uint64(to))
+		if err != nil {
+			logs.FromContext(ctx).Error(err)
+			return err
+		}
+		if err = models.UpdateIndexerState(network, contract, to, toHash.Hex()); err != nil {
+			logs.FromContext(ctx).Error(err)
+			return err
+		}
+		logs.FromContext(ctx).Debugf("indexed blocks %d-%d, %d transfers", from, to, len(transfers))
+		from = to + 1
+	}
+
+	if err = models.ConfirmChainActivities(ctx, network, contract, head-confirmations+1); err != nil {
+		logs.FromContext(ctx).Error(err)
+		return err
+	}
+	return nil
+}
+
+// findReorg block of contract to index again from after a reorg, -1 when there was none.
+// Only blocks holding pending activity and the last indexed block have a stored hash, the fork may
+// be at any block before a replaced one that held no activity, so the indexer goes back over the
+// whole unconfirmed window.
+func findReorg(ctx context.Context, backend chain.Backend, network, contract string, state *models.IndexerState, confirmations int64) (int64, error) {
+	blocks, err := models.FindPendingChainBlocks(network, contract)
+	if err != nil {
+		return -1, err
+	}
+	for _, block := range blocks {
+		hash, err := chain.CanonicalHash(ctx, backend, uint64(block.Number))
+		if err != nil && err != ethereum.NotFound {
+			return -1, err
+		}
+		if hash.Hex() != block.Hash {
+			logs.FromContext(ctx).Warnf("reorg: block %d was %s, now %s", block.Number, block.Hash, hash.Hex())
+			return unconfirmedWindow(state.LastBlock, block.Number, confirmations), nil
+		}
+	}
+
+	if state.LastBlockHash == "" {
+		return -1, nil
+	}
+	// a block the node no longer has was replaced by a shorter chain
+	hash, err := chain.CanonicalHash(ctx, backend, uint64(state.LastBlock))
+	if err != nil && err != ethereum.NotFound {
+		return -1, err
+	}
+	if hash.Hex() == state.LastBlockHash {
+		return -1, nil
+	}
+	logs.FromContext(ctx).Warnf("reorg: last indexed block %d was %s, now %s", state.LastBlock, state.LastBlockHash, hash.Hex())
+	return unconfirmedWindow(state.LastBlock, state.LastBlock, confirmations), nil
+}
+
+// unconfirmedWindow first block not confirmed when lastBlock was indexed, no later than replaced
+func unconfirmedWindow(lastBlock, replaced, confirmations int64) int64 {
+	from := lastBlock - confirmations + 1
+	if from > replaced {
+		from = replaced
+	}
+	if from < 0 {
+		from = 0
+	}
+	return from
+}
@@ -1,9 +1,15 @@
 package logs
 
 import (
-	"github.com/rifflock/lfshook"
-	"github.com/sirupsen/logrus"
+	"context"
+	"io"
+	"io/ioutil"
 	"openseasync/config"
+	"os"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 var logger *logrus.Logger
@@ -17,29 +23,75 @@ func initLogger() {
 	} else {
 		logger.SetLevel(logrus.InfoLevel)
 	}
+	if level, err := logrus.ParseLevel(conf.Log.Level); err == nil && conf.Log.Level != "" {
+		logger.SetLevel(level)
+	}
 
-	formatter := &logrus.TextFormatter{
+	var formatter logrus.Formatter = &logrus.JSONFormatter{
 		TimestampFormat: "2006-01-02 15:04:05.000",
-		FullTimestamp:   true,
+	}
+	if strings.ToLower(conf.Log.Format) == "text" {
+		formatter = &logrus.TextFormatter{
+			TimestampFormat: "2006-01-02 15:04:05.000",
+			FullTimestamp:   true,
+		}
 	}
 	logger.SetReportCaller(true)
 	logger.SetFormatter(formatter)
-	pathMap := lfshook.PathMap{
-		logrus.InfoLevel:  "./logs/info.log",
-		logrus.WarnLevel:  "./logs/warn.log",
-		logrus.ErrorLevel: "./logs/error.log",
-		logrus.FatalLevel: "./logs/error.log",
-		logrus.PanicLevel: "./logs/error.log",
-	}
-	logger.Hooks.Add(lfshook.NewHook(
-		pathMap,
-		formatter,
-	))
-	logger.WriterLevel(logrus.InfoLevel)
+	logger.SetOutput(logOutput(conf.Log))
+}
+
+// logOutput build the writer from the configured sinks, stdout when none is given
+func logOutput(conf config.Log) io.Writer {
+	var writers []io.Writer
+	for _, output := range conf.Output {
+		switch strings.ToLower(output) {
+		case "stdout":
+			writers = append(writers, os.Stdout)
+		case "stderr":
+			writers = append(writers, os.Stderr)
+		case "file":
+			filename := conf.File
+			if filename == "" {
+				filename = "./logs/openseasync.log"
+			}
+			writers = append(writers, &lumberjack.Logger{
+				Filename:   filename,
+				MaxSize:    conf.MaxSize,
+				MaxBackups: conf.MaxBackups,
+				MaxAge:     conf.MaxAge,
+				Compress:   conf.Compress,
+			})
+		case "none":
+			writers = append(writers, ioutil.Discard)
+		}
+	}
+	if len(writers) == 0 {
+		return os.Stdout
+	}
+	return io.MultiWriter(writers...)
 }
+
 func GetLogger() *logrus.Logger {
 	if logger == nil {
 		initLogger()
 	}
 	return logger
 }
+
+type entryKey struct{}
+
+// WithFields return a copy of ctx whose logger carries fields on top of the ones already in ctx
+func WithFields(ctx context.Context, fields logrus.Fields) context.Context {
+	return context.WithValue(ctx, entryKey{}, FromContext(ctx).WithFields(fields))
+}
+
+// FromContext get the logger carried by ctx, such as the one holding the request id
+func FromContext(ctx context.Context) *logrus.Entry {
+	if ctx != nil {
+		if entry, ok := ctx.Value(entryKey{}).(*logrus.Entry); ok {
+			return entry
+		}
+	}
+	return logrus.NewEntry(GetLogger())
+}
@@ -0,0 +1,34 @@
+package logs
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const RequestIDHeader = "X-Request-Id"
+
+// RequestID give every request an id, reusing the one sent by the caller, and put a logger carrying it in the request context
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" {
+			requestID = uuid.New().String()
+		}
+		c.Header(RequestIDHeader, requestID)
+		ctx := WithFields(c.Request.Context(), logrus.Fields{"request_id": requestID})
+		c.Request = c.Request.WithContext(ctx)
+
+		start := time.Now()
+		c.Next()
+
+		FromContext(ctx).WithFields(logrus.Fields{
+			"method":  c.Request.Method,
+			"path":    c.Request.URL.Path,
+			"status":  c.Writer.Status(),
+			"latency": time.Since(start).String(),
+		}).Info("http request")
+	}
+}
@@ -2,21 +2,20 @@ package main
 
 import (
 	"context"
-	"openseasync/common/constants"
+	"flag"
 	"openseasync/config"
 	"openseasync/database"
 	"openseasync/logs"
-	"openseasync/routers/common"
-	"time"
-
-	"github.com/gin-gonic/gin"
-	cors "github.com/itsjamie/gin-cors"
+	"openseasync/server"
 )
 
 func main() {
+	configFile := flag.String("config", "", "configuration file, ./config/config.toml by default")
+	flag.Parse()
+
 	// init database
+	config.InitConfig(*configFile)
 	db := database.InitMongo()
-	config.InitConfig("")
 	defer func() {
 		err := db.Disconnect(context.TODO())
 		if err != nil {
@@ -24,21 +23,7 @@ func main() {
 		}
 	}()
 
-	r := gin.Default()
-	r.Use(cors.Middleware(cors.Config{
-		Origins:         "*",
-		Methods:         "GET, PUT, POST, DELETE",
-		RequestHeaders:  "Origin, Authorization, Content-Type",
-		ExposedHeaders:  "",
-		MaxAge:          50 * time.Second,
-		Credentials:     true,
-		ValidateHeaders: false,
-	}))
-
-	v1 := r.Group("/api")
-	common.HostManager(v1.Group(constants.URL_HOST_GET_COMMON))
-	err := r.Run(":" + config.GetConfig().Port)
-	if err != nil {
+	if err := server.Run(); err != nil {
 		logs.GetLogger().Fatal(err)
 	}
 }
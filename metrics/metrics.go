@@ -0,0 +1,96 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	// HttpRequestDuration http latency per route
+	HttpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "openseasync",
+		Name:      "http_request_duration_seconds",
+		Help:      "Latency of http requests by route, method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	// OpenSeaRequests opensea api requests by endpoint and status code, 429 included
+	OpenSeaRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "openseasync",
+		Name:      "opensea_requests_total",
+		Help:      "Number of requests sent to the OpenSea API by endpoint and status code.",
+	}, []string{"endpoint", "status"})
+
+	// SyncDuration duration of a wallet sync
+	SyncDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "openseasync",
+		Name:      "sync_duration_seconds",
+		Help:      "Duration of OpenSea syncs by kind.",
+		Buckets:   []float64{1, 5, 10, 30, 60, 120, 300, 600, 1200},
+	}, []string{"kind"})
+
+	// SyncUpserted documents upserted by a sync
+	SyncUpserted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "openseasync",
+		Name:      "sync_upserted_total",
+		Help:      "Number of documents upserted by syncs by kind (assets, events, collections).",
+	}, []string{"kind"})
+
+	// CacheRequests cached routes requests by route and result, hit or miss
+	CacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "openseasync",
+		Name:      "cache_requests_total",
+		Help:      "Number of requests to cached routes by route and result (hit, miss).",
+	}, []string{"route", "result"})
+)
+
+func init() {
+	prometheus.MustRegister(HttpRequestDuration, OpenSeaRequests, SyncDuration, SyncUpserted, CacheRequests)
+}
+
+// Middleware record http latency per route
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		HttpRequestDuration.WithLabelValues(route, c.Request.Method, strconv.Itoa(c.Writer.Status())).
+			Observe(time.Since(start).Seconds())
+	}
+}
+
+// Handler expose metrics in prometheus format
+func Handler() gin.HandlerFunc {
+	h := promhttp.Handler()
+	return func(c *gin.Context) {
+		h.ServeHTTP(c.Writer, c.Request)
+	}
+}
+
+// ObserveOpenSeaRequest count an opensea request by endpoint and status code
+func ObserveOpenSeaRequest(endpoint string, statusCode int) {
+	OpenSeaRequests.WithLabelValues(endpoint, strconv.Itoa(statusCode)).Inc()
+}
+
+// ObserveSync record the duration of a sync started at start
+func ObserveSync(kind string, start time.Time) {
+	SyncDuration.WithLabelValues(kind).Observe(time.Since(start).Seconds())
+}
+
+// AddUpserted count upserted documents of kind
+func AddUpserted(kind string, n int) {
+	SyncUpserted.WithLabelValues(kind).Add(float64(n))
+}
+
+// ObserveCache count a request to a cached route by result
+func ObserveCache(route, result string) {
+	CacheRequests.WithLabelValues(route, result).Inc()
+}
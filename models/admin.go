@@ -0,0 +1,143 @@
+package models
+
+import (
+	"context"
+	"math/big"
+	"openseasync/database"
+	"openseasync/logs"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// purgeCollections collections whose soft deleted documents can be purged
+var purgeCollections = []string{"assets", "collections", "item_activitys", "orders"}
+
+// softDeleted the update marking documents deleted now, deleteTime is how purging ages them
+func softDeleted() bson.M {
+	return bson.M{"$set": bson.M{"isDelete": 1, "deleteTime": time.Now().UnixMilli()}}
+}
+
+// restored the update undoing softDeleted
+func restored() bson.M {
+	return bson.M{"$set": bson.M{"isDelete": 0}, "$unset": bson.M{"deleteTime": ""}}
+}
+
+// PurgeDeleted remove the documents soft deleted before the unix milli before, the number removed by collection.
+// Documents deleted before deleteTime was recorded are aged by their refreshTime, those without either are kept.
+func PurgeDeleted(network string, before int64) (map[string]int64, error) {
+	result := make(map[string]int64)
+	db := database.GetMongoClient()
+	for _, name := range purgeCollections {
+		filter := withChain(bson.M{"isDelete": 1, "$or": bson.A{
+			bson.M{"deleteTime": bson.M{"$gt": 0, "$lt": before}},
+			bson.M{"deleteTime": bson.M{"$exists": false}, "refreshTime": bson.M{"$gt": 0, "$lt": before}},
+		}}, network)
+		deleted, err := db.Collection(name).DeleteMany(context.TODO(), filter)
+		if err != nil {
+			logs.GetLogger().Error(err)
+			return nil, err
+		}
+		result[name] = deleted.DeletedCount
+	}
+	return result, nil
+}
+
+// RestoreAsset undo DeleteAssetByTokenID, false when the wallet has no such deleted asset
+func RestoreAsset(network, user, contractAddress, tokenID string) (bool, error) {
+	db := database.GetMongoClient()
+	restoredAssets, err := db.Collection("assets").UpdateMany(
+		context.TODO(),
+		withChain(bson.M{"userMetamaskId": user, "contractAddress": contractAddress, "collectibleTokenId": tokenID, "isDelete": 1}, network),
+		restored())
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return false, err
+	}
+	if restoredAssets.ModifiedCount == 0 {
+		return false, nil
+	}
+
+	// restore item_activitys, those rolled back by a reorg stay deleted
+	if _, err := db.Collection("item_activitys").UpdateMany(
+		context.TODO(),
+		withChain(bson.M{"userMetamaskId": user, "contractAddress": contractAddress, "tokenId": tokenID, "isDelete": 1,
+			"chainStatus": bson.M{"$ne": CHAIN_STATUS_REORGED}}, network),
+		restored()); err != nil {
+		logs.GetLogger().Error(err)
+		return false, err
+	}
+
+	// restore orders
+	if _, err := db.Collection("orders").UpdateMany(
+		context.TODO(),
+		withChain(bson.M{"contractAddress": contractAddress, "tokenId": tokenID, "isDelete": 1}, network),
+		restored()); err != nil {
+		logs.GetLogger().Error(err)
+		return false, err
+	}
+	return true, nil
+}
+
+// RestoreCollection undo DeleteCollectionByCollectionId, false when the wallet has no such deleted collection
+func RestoreCollection(network, user, slug string) (bool, error) {
+	db := database.GetMongoClient()
+	result, err := db.Collection("collections").UpdateMany(
+		context.TODO(),
+		withChain(bson.M{"userMetamaskId": user, "id": slug, "isDelete": 1}, network),
+		restored())
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// RecomputeCollectionFloorPrice set the floor price of a collection to the lowest price of its assets,
+// empty when none is priced. Unlike the sync, which only lowers it, the floor can rise. Nil when there is no such collection.
+func RecomputeCollectionFloorPrice(ctx context.Context, network, collectionId string) (*Collection, error) {
+	var collection Collection
+	db := database.GetMongoClient()
+	if err := db.Collection("collections").
+		FindOne(context.TODO(), bson.M{"id": collectionId, "chain": network}).Decode(&collection); err == mongo.ErrNoDocuments {
+		return nil, nil
+	} else if err != nil {
+		logs.FromContext(ctx).Error(err)
+		return nil, err
+	}
+
+	cursor, err := db.Collection("assets").Find(context.TODO(),
+		bson.M{"chain": network, "collectionId": collectionId, "isDelete": 0, "price": bson.M{"$nin": bson.A{"", nil}}},
+		options.Find().SetProjection(bson.M{"_id": 0, "price": 1}))
+	if err != nil {
+		logs.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	var assets []Asset
+	if err = cursor.All(context.TODO(), &assets); err != nil {
+		logs.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	var floor *big.Int
+	for _, v := range assets {
+		price, ok := new(big.Int).SetString(v.Price, 10)
+		if !ok {
+			continue
+		}
+		if floor == nil || price.Cmp(floor) < 0 {
+			floor = price
+		}
+	}
+	floorPrice := ""
+	if floor != nil {
+		floorPrice = floor.String()
+	}
+
+	if err := setCollectionFloorPrice(ctx, db, network, &collection, floorPrice); err != nil {
+		return nil, err
+	}
+	collection.FloorPrice = floorPrice
+	return &collection, nil
+}
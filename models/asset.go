@@ -6,9 +6,12 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"math"
 	"math/big"
+	"openseasync/chain"
 	"openseasync/common/utils"
 	"openseasync/database"
+	"openseasync/events"
 	"openseasync/logs"
+	"openseasync/metrics"
 	"strconv"
 	"strings"
 	"time"
@@ -22,14 +25,19 @@ import (
 const ZeroAddress = "0x0000000000000000000000000000000000000000"
 
 // InsertOpenSeaAsset query Aseets through opensea API and insert
-func InsertOpenSeaAsset(assets *OwnerAsset, user string, refreshTime int64) error {
+func InsertOpenSeaAsset(ctx context.Context, network string, assets *OwnerAsset, user string, refreshTime int64) error {
 	db := database.GetMongoClient()
 
 	for _, v := range assets.Assets {
+		// a cancelled sync stops before the next asset, leaving the ones it did not reach as they were
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		uuidOrder, _ := uuid2.NewUUID()
 		var (
 			asset = Asset{
 				Id:                   v.ID,
+				Chain:                network,
 				UserMetamaskID:       user,
 				CollectibleName:      v.Name,
 				CoverImageUrl:        v.ImageURL,
@@ -57,6 +65,7 @@ func InsertOpenSeaAsset(assets *OwnerAsset, user string, refreshTime int64) erro
 			}
 			contract = Contract{
 				Address:      v.AssetContract.Address,
+				Chain:        network,
 				ContractName: v.AssetContract.Name,
 				ContractType: v.AssetContract.AssetContractType,
 				Symbol:       v.AssetContract.Symbol,
@@ -109,28 +118,33 @@ func InsertOpenSeaAsset(assets *OwnerAsset, user string, refreshTime int64) erro
 			asset.SellOrders.PayTokenContract.ImageURL = sellOrders.PaymentTokenContract.ImageURL
 			asset.SellOrders.PayTokenContract.EthPrice = sellOrders.PaymentTokenContract.EthPrice
 			asset.SellOrders.PayTokenContract.UsdPrice = sellOrders.PaymentTokenContract.UsdPrice
+			asset.SellOrders.PayTokenContract.Decimals = tokenDecimals(sellOrders.PaymentTokenContract.Decimals)
 		}
 		// insert transaction
-		time.Sleep(time.Second * 2)
-		createDate, err := insertTransaction(db, v.AssetContract.Address, v.TokenID)
+		if err := utils.Sleep(ctx, time.Second*2); err != nil {
+			return err
+		}
+		createDate, err := insertTransaction(ctx, db, network, v.AssetContract.Address, v.TokenID)
 		if err != nil {
-			logs.GetLogger().Error(err)
+			logs.FromContext(ctx).Error(err)
 			return err
 		}
 		asset.CreateDate = createDate
 
-		time.Sleep(time.Second * 2)
 		// If the number of requests is too many, a 429 error code will be thrown
-		resp, err := utils.RequestOpenSeaSingleAsset(v.AssetContract.Address, v.TokenID)
+		if err := utils.Sleep(ctx, time.Second*2); err != nil {
+			return err
+		}
+		resp, err := utils.RequestOpenSeaSingleAsset(ctx, network, v.AssetContract.Address, v.TokenID)
 		if err != nil {
-			logs.GetLogger().Error(err)
+			logs.FromContext(ctx).Error(err)
 			return err
 		}
 
 		// insert top_owner_ships
 		var autoAsset AutoAsset
 		if err = json.Unmarshal(resp, &autoAsset); err != nil {
-			logs.GetLogger().Error(err)
+			logs.FromContext(ctx).Error(err)
 			return err
 		}
 
@@ -148,40 +162,52 @@ func InsertOpenSeaAsset(assets *OwnerAsset, user string, refreshTime int64) erro
 		}
 		asset.AssetsTopOwnerships = assetTopOwnerships
 		asset.OwnerMetamaskId = assetTopOwnerships[len(assetTopOwnerships)-1].Owner
+		verifyAssetOwnership(ctx, &asset, v.AssetContract.SchemaName)
 		// insert assets
 		count, err := db.Collection("assets").CountDocuments(
 			context.TODO(),
-			bson.M{"userMetamaskId": user, "contractAddress": v.AssetContract.Address, "collectibleTokenId": v.TokenID,
+			bson.M{"chain": network, "userMetamaskId": user, "contractAddress": v.AssetContract.Address, "collectibleTokenId": v.TokenID,
 				"isDelete": 0})
 		if err != nil {
-			logs.GetLogger().Error(err)
+			logs.FromContext(ctx).Error(err)
 			return err
 		}
 		if count == 0 {
 			if _, err = db.Collection("assets").InsertOne(context.TODO(), &asset); err != nil {
-				logs.GetLogger().Error(err)
+				logs.FromContext(ctx).Error(err)
 				return err
 			}
 		} else {
 			// update
 			assetByte, err := bson.Marshal(asset)
 			if err != nil {
-				logs.GetLogger().Error(err)
+				logs.FromContext(ctx).Error(err)
 				return err
 			}
 			var tmpAssetByte bson.M
 			if err := bson.Unmarshal(assetByte, &tmpAssetByte); err != nil {
-				logs.GetLogger().Error(err)
+				logs.FromContext(ctx).Error(err)
 				return err
 			}
-			if _, err = db.Collection("assets").UpdateOne(
-				context.TODO(),
-				bson.M{"userMetamaskId": user, "contractAddress": v.AssetContract.Address, "collectibleTokenId": v.TokenID, "isDelete": 0},
-				bson.M{"$set": tmpAssetByte}); err != nil {
-				logs.GetLogger().Error(err)
+			filter := bson.M{"chain": network, "userMetamaskId": user, "contractAddress": v.AssetContract.Address, "collectibleTokenId": v.TokenID, "isDelete": 0}
+			stored, err := findStored(db, "assets", filter)
+			if err != nil {
+				logs.FromContext(ctx).Error(err)
+				return err
+			}
+			if _, err = db.Collection("assets").UpdateOne(context.TODO(), filter, bson.M{"$set": tmpAssetByte}); err != nil {
+				logs.FromContext(ctx).Error(err)
 				return err
 			}
+			if stored != nil {
+				changes := diffDocument(stored, tmpAssetByte, assetChangeFields,
+					newAssetChange(network, CHANGE_KIND_ASSET, v.ID, v.Collection.Slug, user, refreshTime))
+				if err := insertAssetChanges(ctx, db, changes); err != nil {
+					return err
+				}
+			}
 		}
+		metrics.AddUpserted("assets", 1)
 
 		// insert user
 		uuid, _ := uuid2.NewUUID()
@@ -201,41 +227,50 @@ func InsertOpenSeaAsset(assets *OwnerAsset, user string, refreshTime int64) erro
 			userModel.Username = v.Creator.User.Username
 			userModel.AvatarUrl = v.Creator.ProfileImgURL
 		}
-		if err := insertUsers(db, user, userModel); err != nil {
-			logs.GetLogger().Error(err)
+		if err := insertUsers(ctx, db, user, userModel); err != nil {
+			logs.FromContext(ctx).Error(err)
 			return err
 		}
 
 		// insert order
-		if err := insertOrders(db, v.ID, autoAsset, uuidOrder.String()); err != nil {
-			logs.GetLogger().Error(err)
+		if err := insertOrders(ctx, db, network, v.ID, autoAsset, uuidOrder.String()); err != nil {
+			logs.FromContext(ctx).Error(err)
 			return err
 		}
 
 		// insert contract
-		if err := insertContract(db, v.AssetContract.Address, &contract); err != nil {
-			logs.GetLogger().Error(err)
+		if err := insertContract(ctx, db, network, v.AssetContract.Address, &contract); err != nil {
+			logs.FromContext(ctx).Error(err)
 			return err
 		}
 
 		// update collection creator
-		if err := updateCollectionCreator(db, v.Collection.Slug, v.Creator.Address); err != nil {
-			logs.GetLogger().Error(err)
+		if err := updateCollectionCreator(ctx, db, network, v.Collection.Slug, v.Creator.Address); err != nil {
+			logs.FromContext(ctx).Error(err)
 			return err
 		}
 		if asset.Price != "" {
 			// update collection floor price
-			if err := updateCollectionFloorPrice(db, v.Collection.Slug, asset.Price); err != nil {
-				logs.GetLogger().Error(err)
+			if err := updateCollectionFloorPrice(ctx, db, network, v.Collection.Slug, asset.Price); err != nil {
+				logs.FromContext(ctx).Error(err)
 				return err
 			}
 		}
 
+		events.Publish(ctx, events.Event{
+			Type:            events.EVENT_TYPE_ASSET_UPSERTED,
+			Chain:           network,
+			Wallets:         []string{user, asset.OwnerMetamaskId, asset.CreatorMetamaskId},
+			CollectionId:    asset.CollectionID,
+			CollectibleId:   asset.Id,
+			ContractAddress: asset.ContractAddress,
+			TokenId:         asset.CollectibleTokenId,
+		})
 	}
 
 	// Delete opensea deleted asset
-	if err := deleteAsset(db, user, refreshTime); err != nil {
-		logs.GetLogger().Error(err)
+	if err := deleteAsset(ctx, db, network, user, refreshTime); err != nil {
+		logs.FromContext(ctx).Error(err)
 		return err
 	}
 	return nil
@@ -244,7 +279,7 @@ func InsertOpenSeaAsset(assets *OwnerAsset, user string, refreshTime int64) erro
 // FindAssetSearchByOwner find assets by owner
 func FindAssetSearchByOwner(collectionId string, param Params) (map[string]interface{}, error) {
 	var (
-		assets = make([]bson.M, 0)
+		assets = make([]ResponseAssetItem, 0)
 		result = make(map[string]interface{})
 	)
 	db := database.GetMongoClient()
@@ -258,9 +293,9 @@ func FindAssetSearchByOwner(collectionId string, param Params) (map[string]inter
 	case 1:
 		sort = bson.M{"createDate": 1}
 	case 2:
-		sort = bson.M{"price": 1}
+		sort = bson.M{"priceValue": 1}
 	case 3:
-		sort = bson.M{"price": -1}
+		sort = bson.M{"priceValue": -1}
 	case 4:
 		sort = bson.M{"viewCounts": -1}
 	case 5:
@@ -277,16 +312,16 @@ func FindAssetSearchByOwner(collectionId string, param Params) (map[string]inter
 	}
 
 	cond := mongo.Pipeline{
-		{{"$match", bson.M{"collectionId": collectionId, "status": status, "collectibleName": primitive.Regex{Pattern: param.Field, Options: "i"}, "isDelete": 0}}},
+		{{"$match", withChain(bson.M{"collectionId": collectionId, "status": status, "collectibleName": primitive.Regex{Pattern: EscapeRegex(param.Field), Options: "i"}, "isDelete": 0}, param.Chain)}},
 		{{
-			"$addFields", bson.M{"price": bson.M{"$cond": bson.M{
+			"$addFields", bson.M{"priceValue": bson.M{"$cond": bson.M{
 				"if":   bson.M{"$ne": bson.A{"$price", ""}},
 				"then": bson.M{"$convert": bson.M{"input": "$price", "to": "double"}},
 				"else": 0,
 			},
 			}},
 		}},
-		{{"$match", bson.M{"price": bson.M{"$gte": param.MinPrice * math.Pow10(18), "$lte": param.MaxPrice * math.Pow10(18)}}}},
+		{{"$match", bson.M{"priceValue": bson.M{"$gte": param.MinPrice * math.Pow10(18), "$lte": param.MaxPrice * math.Pow10(18)}}}},
 	}
 	cursor, err := db.Collection("assets").Aggregate(context.TODO(), cond)
 	if err != nil {
@@ -304,13 +339,9 @@ func FindAssetSearchByOwner(collectionId string, param Params) (map[string]inter
 		{{"$limit", param.PageSize}},
 		{{"$project",
 			bson.M{
-				"_id": 0, "id": 1, "coverImageUrl": 1, "collectibleName": 1, "creatorId ": 1, "creatorMetamaskId": 1,
-				"creatorName": 1, "likesCount": 1, "viewsCount": 1, "numOfCopies": 1, "totalCopies": 1, "status": 1,
-				"ownerUserId": 1, "ownerMetamaskId": 1, "createDate": 1, "endTime": 1, "animationUrl": 1, "animationOriginalUrl": 1,
-				"price": bson.M{"$cond": bson.M{
-					"if":   bson.M{"$ne": bson.A{"$price", 0}},
-					"then": "$price",
-					"else": nil}},
+				"_id": 0, "id": 1, "coverImageUrl": 1, "collectibleName": 1, "creatorMetamaskId": 1,
+				"creatorName": 1, "likesCount": 1, "viewsCount": 1, "numOfCopies": 1, "totalCopies ": 1, "status": 1,
+				"ownerMetamaskId": 1, "createDate": 1, "endTime": 1, "animationUrl": 1, "animationOriginalUrl": 1, "price": 1,
 			},
 		}},
 	}
@@ -332,9 +363,9 @@ func FindAssetSearchByOwner(collectionId string, param Params) (map[string]inter
 }
 
 // FindAssetByGeneralInfoCollectibleId find assets by collectibleId
-func FindAssetByGeneralInfoCollectibleId(collectibleId int64) (map[string]interface{}, error) {
+func FindAssetByGeneralInfoCollectibleId(network string, collectibleId int64) (map[string]interface{}, error) {
 	var (
-		asset  bson.M
+		asset  ResponseAsset
 		result = make(map[string]interface{})
 	)
 	db := database.GetMongoClient()
@@ -345,13 +376,11 @@ func FindAssetByGeneralInfoCollectibleId(collectibleId int64) (map[string]interf
 			{"collectibleName", 1},
 			{"collectionId", 1},
 			{"collectionName", 1},
-			{"creatorId ", 1},
 			{"creatorMetamaskId", 1},
 			{"creatorName", 1},
 			{"creatorPersonalSite", 1},
 			{"description", 1},
 			{"fileUrl", 1},
-			{"ownerId", 1},
 			{"ownerMetamaskId", 1},
 			{"ownerName", 1},
 			{"price", 1},
@@ -363,29 +392,29 @@ func FindAssetByGeneralInfoCollectibleId(collectibleId int64) (map[string]interf
 			{"recordId", 1},
 			{"startTime", 1},
 			{"endTime", 1},
+			{"onChainOwner", 1},
+			{"ownershipMismatch", 1},
+			{"chain", 1},
 		})
 
-	err := db.Collection("assets").FindOne(context.TODO(), bson.M{"id": collectibleId}, opts).Decode(&asset)
+	err := db.Collection("assets").FindOne(context.TODO(), withChain(bson.M{"id": collectibleId}, network), opts).Decode(&asset)
 	if err != nil && err != mongo.ErrNoDocuments {
 		logs.GetLogger().Error(err)
 		return nil, err
 	}
 
-	if asset == nil {
-		result["data"] = ResponseAsset{}
-	} else {
-		result["data"] = asset
-	}
+	result["data"] = asset
 	return result, nil
 }
 
-func FindAssetOfferRecordsByCollectibleId(collectibleId int64) ([]bson.M, error) {
+// FindAssetOfferRecordsByCollectibleId the orders of an asset of the kind it is offered with, only the live ones without history
+func FindAssetOfferRecordsByCollectibleId(network string, collectibleId int64, history bool) ([]ResponseOrder, error) {
 	var (
 		asset  bson.M
-		orders = make([]bson.M, 0)
+		orders = make([]ResponseOrder, 0)
 	)
 	db := database.GetMongoClient()
-	err := db.Collection("assets").FindOne(context.TODO(), bson.M{"id": collectibleId}).Decode(&asset)
+	err := db.Collection("assets").FindOne(context.TODO(), withChain(bson.M{"id": collectibleId}, network)).Decode(&asset)
 	if err != nil && err != mongo.ErrNoDocuments {
 		logs.GetLogger().Error(err)
 		return nil, err
@@ -400,8 +429,19 @@ func FindAssetOfferRecordsByCollectibleId(collectibleId int64) ([]bson.M, error)
 			{"price", 1},
 			{"tradeType", 1},
 			{"bidTime", 1},
+			{"startTime", 1},
+			{"endTime", 1},
+			{"status", 1},
+			{"statusTime", 1},
+			{"protocol", 1},
+			{"signatureStatus", 1},
+			{"signer", 1},
 		})
-	cursor, err := db.Collection("orders").Find(context.TODO(), bson.M{"collectibleId": collectibleId, "tradeType": asset["status"]}, opts)
+	filter := withChain(bson.M{"collectibleId": collectibleId, "tradeType": asset["status"]}, network)
+	if !history {
+		filter = liveOrders(filter, time.Now())
+	}
+	cursor, err := db.Collection("orders").Find(context.TODO(), filter, opts)
 	if err != nil {
 		logs.GetLogger().Error(err)
 		return nil, err
@@ -414,10 +454,10 @@ func FindAssetOfferRecordsByCollectibleId(collectibleId int64) ([]bson.M, error)
 }
 
 // FindAssetOtherByCollection find assets other
-func FindAssetOtherByCollection(collectibleId int64) (map[string]interface{}, error) {
+func FindAssetOtherByCollection(network string, collectibleId int64) (map[string]interface{}, error) {
 	var (
 		asset  Asset
-		assets = make([]bson.M, 0)
+		assets = make([]ResponseAssetItem, 0)
 		result = make(map[string]interface{})
 	)
 	db := database.GetMongoClient()
@@ -427,27 +467,25 @@ func FindAssetOtherByCollection(collectibleId int64) (map[string]interface{}, er
 			{"id", 1},
 			{"coverImageUrl", 1},
 			{"collectibleName", 1},
-			{"creatorId", 1},
 			{"creatorName", 1},
 			{"creatorMetamaskId", 1},
 			{"price", 1},
 			{"likesCount", 1},
 			{"viewsCount", 1},
 			{"numOfCopies", 1},
-			{"totalCopies", 1},
+			{"totalCopies ", 1},
 			{"status", 1},
-			{"ownerUserId", 1},
 			{"ownerMetamaskId", 1},
 			{"createDate", 1},
 		})
 	err := db.Collection("assets").
-		FindOne(context.TODO(), bson.M{"id": collectibleId}).Decode(&asset)
+		FindOne(context.TODO(), withChain(bson.M{"id": collectibleId}, network)).Decode(&asset)
 	if err != nil && err != mongo.ErrNoDocuments {
 		logs.GetLogger().Error(err)
 		return nil, err
 	}
 	collectionId := asset.CollectionID
-	cursor, err := db.Collection("assets").Find(context.TODO(), bson.M{"collectionId": collectionId, "id": bson.M{"$ne": collectibleId}}, opts)
+	cursor, err := db.Collection("assets").Find(context.TODO(), withChain(bson.M{"collectionId": collectionId, "id": bson.M{"$ne": collectibleId}}, asset.Chain), opts)
 	if err != nil {
 		logs.GetLogger().Error(err)
 		return nil, err
@@ -461,15 +499,16 @@ func FindAssetOtherByCollection(collectibleId int64) (map[string]interface{}, er
 	return result, nil
 }
 
-// FindOrdersHighestPriceByCollectibleId find highest price by collectibled
-func FindOrdersHighestPriceByCollectibleId(collectibleId int64) (interface{}, error) {
-	var (
-		orders []bson.M
-		order  bson.M
-	)
+// FindOrdersHighestPriceByCollectibleId find highest price by collectibled, among the live bids without history
+func FindOrdersHighestPriceByCollectibleId(network string, collectibleId int64, history bool) (*ResponseOrder, error) {
+	var orders []ResponseOrder
 	db := database.GetMongoClient()
+	filter := withChain(bson.M{"collectibleId": collectibleId, "tradeType": "onAuction", "isDelete": 0}, network)
+	if !history {
+		filter = liveOrders(filter, time.Now())
+	}
 	cond := mongo.Pipeline{
-		{{"$match", bson.M{"collectibleId": collectibleId, "tradeType": "onAuction", "isDelete": 0}}},
+		{{"$match", filter}},
 		{{
 			"$addFields", bson.M{"highestPrice": bson.M{"$cond": bson.M{
 				"if":   bson.M{"$ne": bson.A{"$price", ""}},
@@ -480,7 +519,8 @@ func FindOrdersHighestPriceByCollectibleId(collectibleId int64) (interface{}, er
 		}},
 		{{"$sort", bson.M{"highestPrice": -1}}},
 		{{"$limit", 1}},
-		{{"$project", bson.M{"_id": 0, "price": 1, "startTime": 1, "endTime": 1, "auctionMetamaskId": 1, "auctionUserName": 1}}},
+		{{"$project", bson.M{"_id": 0, "price": 1, "startTime": 1, "endTime": 1, "auctionMetamaskId": 1, "auctionUserName": 1, "status": 1, "statusTime": 1,
+			"protocol": 1, "signatureStatus": 1, "signer": 1}}},
 	}
 	cursor, err := db.Collection("orders").Aggregate(context.TODO(), cond)
 	if err != nil {
@@ -492,19 +532,19 @@ func FindOrdersHighestPriceByCollectibleId(collectibleId int64) (interface{}, er
 		return nil, err
 	}
 	if len(orders) >= 1 {
-		return orders[0], nil
+		return &orders[0], nil
 	}
-	return order, nil
+	return nil, nil
 }
 
 // DeleteAssetByTokenID delete asset by tokenId
-func DeleteAssetByTokenID(user, contractAddress, tokenID string) error {
+func DeleteAssetByTokenID(network, user, contractAddress, tokenID string) error {
 	db := database.GetMongoClient()
 	// delete assets
 	if _, err := db.Collection("assets").UpdateMany(
 		context.TODO(),
-		bson.M{"userMetamaskId": user, "contractAddress": contractAddress, "collectibleTokenId": tokenID},
-		bson.M{"$set": bson.M{"isDelete": 1}}); err != nil {
+		withChain(bson.M{"userMetamaskId": user, "contractAddress": contractAddress, "collectibleTokenId": tokenID}, network),
+		softDeleted()); err != nil {
 		logs.GetLogger().Error(err)
 		return err
 	}
@@ -512,8 +552,8 @@ func DeleteAssetByTokenID(user, contractAddress, tokenID string) error {
 	// delete item_activitys
 	if _, err := db.Collection("item_activitys").UpdateMany(
 		context.TODO(),
-		bson.M{"userMetamaskId": user, "contractAddress": contractAddress, "tokenId": tokenID},
-		bson.M{"$set": bson.M{"isDelete": 1}}); err != nil {
+		withChain(bson.M{"userMetamaskId": user, "contractAddress": contractAddress, "tokenId": tokenID}, network),
+		softDeleted()); err != nil {
 		logs.GetLogger().Error(err)
 		return err
 	}
@@ -521,8 +561,8 @@ func DeleteAssetByTokenID(user, contractAddress, tokenID string) error {
 	// delete orders
 	if _, err := db.Collection("orders").UpdateMany(
 		context.TODO(),
-		bson.M{"contract_address": contractAddress, "tokenId": tokenID},
-		bson.M{"$set": bson.M{"isDelete": 1}}); err != nil {
+		withChain(bson.M{"contractAddress": contractAddress, "tokenId": tokenID}, network),
+		softDeleted()); err != nil {
 		logs.GetLogger().Error(err)
 		return err
 	}
@@ -530,16 +570,16 @@ func DeleteAssetByTokenID(user, contractAddress, tokenID string) error {
 }
 
 // insert contract
-func insertContract(db *mongo.Database, contractAddress string, contract *Contract) error {
+func insertContract(ctx context.Context, db *mongo.Database, network, contractAddress string, contract *Contract) error {
 	count, err := db.Collection("contracts").
-		CountDocuments(context.TODO(), bson.M{"address": contractAddress})
+		CountDocuments(context.TODO(), bson.M{"address": contractAddress, "chain": network})
 	if err != nil {
-		logs.GetLogger().Error(err)
+		logs.FromContext(ctx).Error(err)
 		return err
 	}
 	if count == 0 {
 		if _, err = db.Collection("contracts").InsertOne(context.TODO(), contract); err != nil {
-			logs.GetLogger().Error(err)
+			logs.FromContext(ctx).Error(err)
 			return err
 		}
 	}
@@ -547,28 +587,74 @@ func insertContract(db *mongo.Database, contractAddress string, contract *Contra
 }
 
 // delete asset
-func deleteAsset(db *mongo.Database, user string, refreshTime int64) error {
-	if _, err := db.Collection("assets").UpdateMany(
-		context.TODO(),
-		bson.M{"userMetamaskId": user, "refreshTime": bson.M{"$lt": refreshTime}, "isDelete": 0},
-		bson.M{"$set": bson.M{"isDelete": 1}}); err != nil {
-		logs.GetLogger().Error(err)
+func deleteAsset(ctx context.Context, db *mongo.Database, network, user string, refreshTime int64) error {
+	filter := bson.M{"chain": network, "userMetamaskId": user, "refreshTime": bson.M{"$lt": refreshTime}, "isDelete": 0}
+	// remember what is about to be deleted, subscribers are told which assets left the wallet
+	cursor, err := db.Collection("assets").Find(context.TODO(), filter,
+		options.Find().SetProjection(bson.M{"_id": 0, "id": 1, "collectibleName": 1, "collectionId": 1, "contractAddress": 1, "collectibleTokenId": 1}))
+	if err != nil {
+		logs.FromContext(ctx).Error(err)
+		return err
+	}
+	var removed []bson.M
+	if err = cursor.All(context.TODO(), &removed); err != nil {
+		logs.FromContext(ctx).Error(err)
 		return err
 	}
+	if len(removed) == 0 {
+		return nil
+	}
+
+	if _, err := db.Collection("assets").UpdateMany(context.TODO(), filter, softDeleted()); err != nil {
+		logs.FromContext(ctx).Error(err)
+		return err
+	}
+	var changes []interface{}
+	collectibleIds := make([]int, len(removed))
+	for i, v := range removed {
+		switch id := v["id"].(type) {
+		case int32:
+			collectibleIds[i] = int(id)
+		case int64:
+			collectibleIds[i] = int(id)
+		}
+		collectionId, _ := v["collectionId"].(string)
+		change := newAssetChange(network, CHANGE_KIND_ASSET, collectibleIds[i], collectionId, user, refreshTime)
+		change.Field, change.OldValue, change.NewValue = "isDelete", 0, 1
+		changes = append(changes, change)
+	}
+	if err := insertAssetChanges(ctx, db, changes); err != nil {
+		return err
+	}
+	for i, v := range removed {
+		collectionId, _ := v["collectionId"].(string)
+		contractAddress, _ := v["contractAddress"].(string)
+		tokenId, _ := v["collectibleTokenId"].(string)
+		events.Publish(ctx, events.Event{
+			Type:            events.EVENT_TYPE_ASSET_REMOVED,
+			Chain:           network,
+			Wallets:         []string{user},
+			CollectionId:    collectionId,
+			CollectibleId:   collectibleIds[i],
+			ContractAddress: contractAddress,
+			TokenId:         tokenId,
+			Data:            v,
+		})
+	}
 	return nil
 }
 
 // insert transaction
-func insertTransaction(db *mongo.Database, contractAddress, tokenId string) (int64, error) {
+func insertTransaction(ctx context.Context, db *mongo.Database, network, contractAddress, tokenId string) (int64, error) {
 	// If the number of requests is too many, a 429 error code will be thrown
-	resp, err := utils.RequestOpenSeaEvent(contractAddress, tokenId)
+	resp, err := utils.RequestOpenSeaEvent(ctx, network, contractAddress, tokenId)
 	if err != nil {
-		logs.GetLogger().Error(err)
+		logs.FromContext(ctx).Error(err)
 		return 0, err
 	}
 	var event Event
 	if err = json.Unmarshal(resp, &event); err != nil {
-		logs.GetLogger().Error(err)
+		logs.FromContext(ctx).Error(err)
 		return 0, err
 	}
 
@@ -577,6 +663,7 @@ func insertTransaction(db *mongo.Database, contractAddress, tokenId string) (int
 	for _, v := range event.AssetEvents {
 		var itemActivity = ItemActivity{
 			Id:               v.ID,
+			Chain:            network,
 			CollectibleId:    v.Asset.ID,
 			CollectibleName:  v.Asset.Name,
 			CollectionId:     v.CollectionSlug,
@@ -595,6 +682,7 @@ func insertTransaction(db *mongo.Database, contractAddress, tokenId string) (int
 			TradeType:        v.EventType,
 			Quantity:         v.Quantity,
 			Transaction:      v.Transaction,
+			Source:           ACTIVITY_SOURCE_OPENSEA,
 		}
 		if v.EventType == "created" {
 			if createData == 0 || createData > utils.ParseTime(v.CreatedDate) {
@@ -607,126 +695,263 @@ func insertTransaction(db *mongo.Database, contractAddress, tokenId string) (int
 				ImageURL: v.PaymentToken.ImageURL,
 				EthPrice: v.PaymentToken.EthPrice,
 				UsdPrice: v.PaymentToken.UsdPrice.(string),
+				Decimals: tokenDecimals(v.PaymentToken.Decimals),
+			}
+			// opensea reports the current rates of the token, kept so later trades can be valued at them
+			if err := recordTokenPrice(db, network, itemActivity.PayTokenContract, time.Now()); err != nil {
+				logs.FromContext(ctx).Error(err)
+			}
+			if err := setTradePrices(db, network, &itemActivity); err != nil {
+				logs.FromContext(ctx).Error(err)
+				return 0, err
 			}
-			usdPrice, _ := strconv.ParseFloat(v.PaymentToken.UsdPrice.(string), 64)
-			price, _ := strconv.ParseFloat(v.TotalPrice, 64)
-			n := new(big.Int)
-			n.Mul(big.NewInt(int64(usdPrice)), big.NewInt(int64(price)))
-			n.Div(n, big.NewInt(int64(math.Pow10(18))))
-			itemActivity.PriceInUsd = n.String()
 		}
 
 		count, err := db.Collection("item_activitys").CountDocuments(
 			context.TODO(),
-			bson.M{"id": v.ID, "contractAddress": contractAddress, "tokenId": tokenId, "isDelete": 0})
+			bson.M{"chain": network, "id": v.ID, "contractAddress": contractAddress, "tokenId": tokenId, "isDelete": 0})
 		if err != nil {
-			logs.GetLogger().Error(err)
+			logs.FromContext(ctx).Error(err)
 			return 0, err
 		}
 		if count == 0 {
 			if _, err = db.Collection("item_activitys").InsertOne(context.TODO(), &itemActivity); err != nil {
-				logs.GetLogger().Error(err)
+				logs.FromContext(ctx).Error(err)
 				return 0, err
 			}
+			events.Publish(ctx, events.Event{
+				Type:            events.EVENT_TYPE_ACTIVITY,
+				Chain:           network,
+				Wallets:         []string{itemActivity.SellerMetamaskId, itemActivity.BuyerMetamaskId},
+				CollectionId:    itemActivity.CollectionId,
+				CollectibleId:   itemActivity.CollectibleId,
+				ContractAddress: contractAddress,
+				TokenId:         tokenId,
+				Data:            itemActivity,
+			})
+			if itemActivity.TradeType == "successful" {
+				events.Publish(ctx, events.Event{
+					Type:            events.EVENT_TYPE_SALE,
+					Chain:           network,
+					Wallets:         []string{itemActivity.SellerMetamaskId, itemActivity.BuyerMetamaskId},
+					CollectionId:    itemActivity.CollectionId,
+					CollectibleId:   itemActivity.CollectibleId,
+					ContractAddress: contractAddress,
+					TokenId:         tokenId,
+					Data:            itemActivity,
+				})
+			}
 		} else {
 			// update
 			itemActivityByte, err := bson.Marshal(itemActivity)
 			if err != nil {
-				logs.GetLogger().Error(err)
+				logs.FromContext(ctx).Error(err)
 				return 0, err
 			}
 			var tmpItemActivity bson.M
 			if err := bson.Unmarshal(itemActivityByte, &tmpItemActivity); err != nil {
-				logs.GetLogger().Error(err)
+				logs.FromContext(ctx).Error(err)
 				return 0, err
 			}
+			// the suspicious flag is the analysis' to set
+			delete(tmpItemActivity, "suspicious")
+			delete(tmpItemActivity, "suspiciousReasons")
 			if _, err = db.Collection("item_activitys").UpdateOne(
 				context.TODO(),
-				bson.M{"contractAddress": contractAddress, "tokenId": tokenId, "isDelete": 0},
+				bson.M{"chain": network, "id": v.ID, "contractAddress": contractAddress, "tokenId": tokenId, "isDelete": 0},
 				bson.M{"$set": tmpItemActivity}); err != nil {
-				logs.GetLogger().Error(err)
+				logs.FromContext(ctx).Error(err)
 				return 0, err
 			}
 		}
+		metrics.AddUpserted("events", 1)
 	}
 	return createData, nil
 }
 
 // insert orders
-func insertOrders(db *mongo.Database, collectibleId int, autoAsset AutoAsset, uuid string) error {
+func insertOrders(ctx context.Context, db *mongo.Database, network string, collectibleId int, autoAsset AutoAsset, uuid string) error {
+	now := time.Now()
+	chainId := chain.ChainID(network)
+	seen := make([]string, 0, len(autoAsset.Orders)+len(autoAsset.SeaportSellOrders))
 	for _, v := range autoAsset.Orders {
+		seen = append(seen, v.OrderHash)
 		if v.Taker.Address == ZeroAddress {
 			continue
 		}
 		var orders = Orders{
 			UUID:              uuid,
 			Id:                v.OrderHash,
+			Chain:             network,
 			CollectibleId:     collectibleId,
+			CollectionId:      autoAsset.Collection.Slug,
+			ContractAddress:   autoAsset.AssetContract.Address,
+			TokenId:           autoAsset.TokenID,
 			StartTime:         utils.ParseTime(v.CreatedDate),
-			EndTime:           utils.ParseTime(v.ClosingDate),
+			EndTime:           orderEndTime(v.ClosingDate),
 			BidTime:           utils.ParseTime(v.CreatedDate),
 			AuctionMetamaskId: v.Maker.Address,
 			AuctionUserName:   v.Maker.User.Username,
 			CurrentBounty:     v.CurrentBounty,
 			Price:             v.CurrentPrice,
 			BasePrice:         v.BasePrice,
+			Protocol:          chain.PROTOCOL_WYVERN,
+			Side:              wyvernOrderSide(v.Side),
 		}
+		orders.Status = orderStatus(v.Cancelled, v.Finalized, v.MarkedInvalid, orders.EndTime, now)
 		orders.PayTokenContract.Symbol = v.PaymentTokenContract.Symbol
 		orders.PayTokenContract.ImageURL = v.PaymentTokenContract.ImageURL
 		orders.PayTokenContract.EthPrice = v.PaymentTokenContract.EthPrice
 		orders.PayTokenContract.UsdPrice = v.PaymentTokenContract.UsdPrice
+		orders.PayTokenContract.Decimals = tokenDecimals(v.PaymentTokenContract.Decimals)
+		if err := recordTokenPrice(db, network, orders.PayTokenContract, now); err != nil {
+			logs.FromContext(ctx).Error(err)
+		}
 
 		orders.TradeType = "onAuction"
 		if v.ClosingExtendable == true {
 			orders.TradeType = "onSale"
 		}
 
-		count, err := db.Collection("orders").
-			CountDocuments(context.TODO(), bson.M{"id": v.OrderHash})
-		if err != nil {
-			logs.GetLogger().Error(err)
+		setOrderSignature(&orders, chain.VerifyWyvernOrder(chainId, chain.WyvernOrder{
+			Exchange:           v.Exchange,
+			Maker:              v.Maker.Address,
+			Taker:              v.Taker.Address,
+			MakerRelayerFee:    v.MakerRelayerFee,
+			TakerRelayerFee:    v.TakerRelayerFee,
+			MakerProtocolFee:   v.MakerProtocolFee,
+			TakerProtocolFee:   v.TakerProtocolFee,
+			FeeRecipient:       v.FeeRecipient.Address,
+			FeeMethod:          uint8(v.FeeMethod),
+			Side:               uint8(v.Side),
+			SaleKind:           uint8(v.SaleKind),
+			Target:             v.Target,
+			HowToCall:          uint8(v.HowToCall),
+			Calldata:           v.Calldata,
+			ReplacementPattern: v.ReplacementPattern,
+			StaticTarget:       v.StaticTarget,
+			StaticExtradata:    v.StaticExtradata,
+			PaymentToken:       v.PaymentToken,
+			BasePrice:          v.BasePrice,
+			Extra:              v.Extra,
+			ListingTime:        strconv.Itoa(v.ListingTime),
+			ExpirationTime:     strconv.Itoa(v.ExpirationTime),
+			Salt:               v.Salt,
+			Nonce:              v.Nonce,
+		}, v.OrderHash, v.V, v.R, v.S))
+
+		if err := saveOrder(ctx, db, &orders, now); err != nil {
 			return err
 		}
-		if count == 0 {
-			if _, err = db.Collection("orders").InsertOne(context.TODO(), &orders); err != nil {
-				logs.GetLogger().Error(err)
-				return err
-			}
-		} else {
-			ordersByte, err := bson.Marshal(orders)
-			if err != nil {
-				logs.GetLogger().Error(err)
-				return err
-			}
-			var tmpOrders bson.M
-			if err := bson.Unmarshal(ordersByte, &tmpOrders); err != nil {
-				logs.GetLogger().Error(err)
-				return err
-			}
+	}
+	for _, v := range autoAsset.SeaportSellOrders {
+		seen = append(seen, v.OrderHash)
+		var orders = Orders{
+			UUID:              uuid,
+			Id:                v.OrderHash,
+			Chain:             network,
+			CollectibleId:     collectibleId,
+			CollectionId:      autoAsset.Collection.Slug,
+			ContractAddress:   autoAsset.AssetContract.Address,
+			TokenId:           autoAsset.TokenID,
+			StartTime:         utils.ParseTime(v.CreatedDate),
+			EndTime:           orderEndTime(v.ClosingDate),
+			BidTime:           utils.ParseTime(v.CreatedDate),
+			AuctionMetamaskId: v.Maker.Address,
+			AuctionUserName:   v.Maker.User.Username,
+			Price:             v.CurrentPrice,
+			BasePrice:         v.CurrentPrice,
+			TradeType:         "onSale",
+			Protocol:          chain.PROTOCOL_SEAPORT,
+			Side:              ORDER_SIDE_SELL,
+		}
+		orders.Status = orderStatus(v.Cancelled, v.Finalized, v.MarkedInvalid, orders.EndTime, now)
+		setOrderSignature(&orders, chain.VerifySeaportOrder(chainId, v.ProtocolAddress, v.ProtocolData.Parameters,
+			v.OrderHash, v.ProtocolData.Signature))
 
-			if _, err = db.Collection("assets").UpdateOne(
-				context.TODO(),
-				bson.M{"orderHash": v.OrderHash}, bson.M{"$set": tmpOrders}); err != nil {
-				logs.GetLogger().Error(err)
-				return err
+		if err := saveOrder(ctx, db, &orders, now); err != nil {
+			return err
+		}
+	}
+	return closeMissingOrders(ctx, db, network, collectibleId, autoAsset.AssetContract.Address, autoAsset.TokenID, seen, now)
+}
+
+// saveOrder insert or update a synced order, announcing new orders and status changes
+func saveOrder(ctx context.Context, db *mongo.Database, orders *Orders, now time.Time) error {
+	var stored Orders
+	err := db.Collection("orders").FindOne(context.TODO(), bson.M{"id": orders.Id, "chain": orders.Chain}).Decode(&stored)
+	if err != nil && err != mongo.ErrNoDocuments {
+		logs.FromContext(ctx).Error(err)
+		return err
+	}
+	statusChanged := setOrderStatusTime(orders, stored.Status, stored.StatusTime, now)
+	if err == mongo.ErrNoDocuments {
+		if _, err = db.Collection("orders").InsertOne(context.TODO(), orders); err != nil {
+			logs.FromContext(ctx).Error(err)
+			return err
+		}
+		// an order whose signature is not the maker's is kept for inspection but not announced
+		if orders.SignatureStatus != chain.SIGNATURE_INVALID {
+			events.Publish(ctx, events.Event{
+				Type:            events.EVENT_TYPE_ORDER,
+				Chain:           orders.Chain,
+				Wallets:         []string{orders.AuctionMetamaskId},
+				CollectionId:    orders.CollectionId,
+				CollectibleId:   orders.CollectibleId,
+				ContractAddress: orders.ContractAddress,
+				TokenId:         orders.TokenId,
+				Data:            *orders,
+			})
+			// listings are announced as orders only, bids are the offers on the token
+			if orders.Side == ORDER_SIDE_BUY {
+				events.Publish(ctx, events.Event{
+					Type:            events.EVENT_TYPE_BID,
+					Chain:           orders.Chain,
+					Wallets:         []string{orders.AuctionMetamaskId},
+					CollectionId:    orders.CollectionId,
+					CollectibleId:   orders.CollectibleId,
+					ContractAddress: orders.ContractAddress,
+					TokenId:         orders.TokenId,
+					Data:            *orders,
+				})
 			}
 		}
+	} else {
+		ordersByte, err := bson.Marshal(orders)
+		if err != nil {
+			logs.FromContext(ctx).Error(err)
+			return err
+		}
+		var tmpOrders bson.M
+		if err := bson.Unmarshal(ordersByte, &tmpOrders); err != nil {
+			logs.FromContext(ctx).Error(err)
+			return err
+		}
 
+		if _, err = db.Collection("orders").UpdateOne(
+			context.TODO(),
+			bson.M{"id": orders.Id, "chain": orders.Chain}, bson.M{"$set": tmpOrders}); err != nil {
+			logs.FromContext(ctx).Error(err)
+			return err
+		}
+	}
+	if statusChanged {
+		publishOrderStatus(ctx, orders, stored.Status)
 	}
 	return nil
 }
 
 // insert users
-func insertUsers(db *mongo.Database, userAddress string, user User) error {
+func insertUsers(ctx context.Context, db *mongo.Database, userAddress string, user User) error {
 	count, err := db.Collection("users").
 		CountDocuments(context.TODO(), bson.M{"userMetamaskId": userAddress})
 	if err != nil {
-		logs.GetLogger().Error(err)
+		logs.FromContext(ctx).Error(err)
 		return err
 	}
 	if count == 0 {
 		if _, err = db.Collection("users").InsertOne(context.TODO(), user); err != nil {
-			logs.GetLogger().Error(err)
+			logs.FromContext(ctx).Error(err)
 			return err
 		}
 	} else {
@@ -736,7 +961,7 @@ func insertUsers(db *mongo.Database, userAddress string, user User) error {
 			bson.M{"userMetamaskId": userAddress},
 			bson.M{"$set": bson.M{"userName": user.Username, "avatarUrl": user.AvatarUrl, "instagramLink": user.InstagramLink, "personalPageLink": user.PersonalPageLink,
 				"discordLink": user.DiscordLink, "telegramLink": user.TelegramLink, "twitterLink": user.TwitterLink}}); err != nil {
-			logs.GetLogger().Error(err)
+			logs.FromContext(ctx).Error(err)
 			return err
 		}
 	}
@@ -744,22 +969,22 @@ func insertUsers(db *mongo.Database, userAddress string, user User) error {
 }
 
 // update collection creator
-func updateCollectionCreator(db *mongo.Database, collectionId string, creator string) error {
+func updateCollectionCreator(ctx context.Context, db *mongo.Database, network, collectionId string, creator string) error {
 	if _, err := db.Collection("collections").
-		UpdateOne(context.TODO(), bson.M{"id": collectionId}, bson.M{"$set": bson.M{"creatorMetamaskId": creator}}); err != nil {
-		logs.GetLogger().Error(err)
+		UpdateOne(context.TODO(), bson.M{"id": collectionId, "chain": network}, bson.M{"$set": bson.M{"creatorMetamaskId": creator}}); err != nil {
+		logs.FromContext(ctx).Error(err)
 		return err
 	}
 	return nil
 }
 
 // update collection floor price
-func updateCollectionFloorPrice(db *mongo.Database, collectionId string, floorPrice string) error {
+func updateCollectionFloorPrice(ctx context.Context, db *mongo.Database, network, collectionId string, floorPrice string) error {
 	var collection Collection
 
 	if err := db.Collection("collections").
-		FindOne(context.TODO(), bson.M{"id": collectionId}).Decode(&collection); err != nil {
-		logs.GetLogger().Error(err)
+		FindOne(context.TODO(), bson.M{"id": collectionId, "chain": network}).Decode(&collection); err != nil {
+		logs.FromContext(ctx).Error(err)
 		return err
 	}
 
@@ -774,11 +999,31 @@ func updateCollectionFloorPrice(db *mongo.Database, collectionId string, floorPr
 		}
 	}
 
+	return setCollectionFloorPrice(ctx, db, network, &collection, floorPrice)
+}
+
+// setCollectionFloorPrice save the floor price of collection, recording the change when there is one
+func setCollectionFloorPrice(ctx context.Context, db *mongo.Database, network string, collection *Collection, floorPrice string) error {
+	collectionId := collection.ID
 	if _, err := db.Collection("collections").
-		UpdateOne(context.TODO(), bson.M{"id": collectionId}, bson.M{"$set": bson.M{"floorPrice": floorPrice}}); err != nil {
-		logs.GetLogger().Error(err)
+		UpdateOne(context.TODO(), bson.M{"id": collectionId, "chain": network}, bson.M{"$set": bson.M{"floorPrice": floorPrice}}); err != nil {
+		logs.FromContext(ctx).Error(err)
 		return err
 	}
+	if collection.FloorPrice != floorPrice {
+		change := newAssetChange(network, CHANGE_KIND_COLLECTION, 0, collectionId, collection.UserMetamaskID, collection.RefreshTime)
+		change.Field, change.OldValue, change.NewValue = "floorPrice", collection.FloorPrice, floorPrice
+		if err := insertAssetChanges(ctx, db, []interface{}{change}); err != nil {
+			return err
+		}
+		events.Publish(ctx, events.Event{
+			Type:         events.EVENT_TYPE_FLOOR_PRICE_CHANGED,
+			Chain:        network,
+			Wallets:      []string{collection.UserMetamaskID},
+			CollectionId: collectionId,
+			Data:         bson.M{"oldFloorPrice": collection.FloorPrice, "newFloorPrice": floorPrice},
+		})
+	}
 
 	return nil
 }
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestCancelledSyncStopsWriting a sync cancelled mid page returns before writing the asset it was on or any
+// after it. No database is connected, so any write would panic.
+func TestCancelledSyncStopsWriting(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	assets := &OwnerAsset{Assets: make([]AutoAsset, 3)}
+
+	start := time.Now()
+	err := InsertOpenSeaAsset(ctx, "ethereum", assets, "0x1111111111111111111111111111111111111111", start.UnixMilli())
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, want %v", err, context.Canceled)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("the sync took %v to stop", elapsed)
+	}
+
+	collections := &OwnerCollection{Collections: make([]AutoCollection, 3)}
+	err = InsertOpenSeaCollection(ctx, "ethereum", collections, "0x1111111111111111111111111111111111111111", start.UnixMilli())
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, want %v", err, context.Canceled)
+	}
+}
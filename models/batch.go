@@ -0,0 +1,97 @@
+package models
+
+import (
+	"context"
+	"openseasync/database"
+	"openseasync/logs"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// finders of many documents in one query, used by the graphql loaders in place of a $lookup per document
+
+// FindAssetsByIds assets by collectible id
+func FindAssetsByIds(network string, ids []int64) ([]Asset, error) {
+	assets := make([]Asset, 0)
+	err := findAll("assets", withChain(bson.M{"id": bson.M{"$in": ids}, "isDelete": 0}, network), &assets)
+	return assets, err
+}
+
+// FindAssetsByKeys the newest assets whose field is one of keys, at most limit of each
+func FindAssetsByKeys(network, field string, keys []string, limit int64) ([]Asset, error) {
+	assets := make([]Asset, 0)
+	err := findLatestByKeys("assets", field, withChain(bson.M{"isDelete": 0}, network), keys, limit, &assets)
+	return assets, err
+}
+
+// FindCollectionsByIds collections by id, the same collection is stored once per wallet holding it
+func FindCollectionsByIds(network string, ids []string) ([]Collection, error) {
+	collections := make([]Collection, 0)
+	err := findLatestByKeys("collections", "id", withChain(bson.M{"isDelete": 0}, network), ids, 1, &collections)
+	return collections, err
+}
+
+// FindCollectionsByUsers the newest collections of each wallet, at most limit of each
+func FindCollectionsByUsers(network string, users []string, limit int64) ([]Collection, error) {
+	collections := make([]Collection, 0)
+	err := findLatestByKeys("collections", "userMetamaskId", withChain(bson.M{"isDelete": 0}, network), users, limit, &collections)
+	return collections, err
+}
+
+// FindUsersByMetamaskIds users by wallet
+func FindUsersByMetamaskIds(userMetamaskIds []string) ([]User, error) {
+	users := make([]User, 0)
+	err := findAll("users", bson.M{"userMetamaskId": bson.M{"$in": userMetamaskIds}}, &users)
+	return users, err
+}
+
+// FindContractsByAddresses contracts by address
+func FindContractsByAddresses(network string, addresses []string) ([]Contract, error) {
+	contracts := make([]Contract, 0)
+	err := findAll("contracts", withChain(bson.M{"address": bson.M{"$in": addresses}}, network), &contracts)
+	return contracts, err
+}
+
+// FindOrdersByCollectibleIds the orders of each asset
+func FindOrdersByCollectibleIds(network string, collectibleIds []int64) ([]Orders, error) {
+	orders := make([]Orders, 0)
+	err := findAll("orders", withChain(bson.M{"collectibleId": bson.M{"$in": collectibleIds}, "isDelete": 0}, network), &orders)
+	return orders, err
+}
+
+// FindItemActivitiesByKeys the newest activities whose field is one of keys, at most limit of each
+func FindItemActivitiesByKeys(network, field string, keys interface{}, limit int64) ([]ItemActivity, error) {
+	activities := make([]ItemActivity, 0)
+	err := findLatestByKeys("item_activitys", field, withChain(bson.M{"isDelete": 0}, network), keys, limit, &activities)
+	return activities, err
+}
+
+func findAll(name string, filter bson.M, results interface{}) error {
+	db := database.GetMongoClient()
+	cursor, err := db.Collection(name).Find(context.TODO(), filter)
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return err
+	}
+	if err = cursor.All(context.TODO(), results); err != nil {
+		logs.GetLogger().Error(err)
+		return err
+	}
+	return nil
+}
+
+// findLatestByKeys the documents of filter whose field is one of keys, newest first, at most limit of each key
+func findLatestByKeys(name, field string, filter bson.M, keys interface{}, limit int64, results interface{}) error {
+	filter[field] = bson.M{"$in": keys}
+	pipe := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$sort", Value: bson.D{{Key: "createDate", Value: -1}, {Key: "id", Value: -1}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$" + field, "docs": bson.M{"$push": "$$ROOT"}}}},
+		{{Key: "$project", Value: bson.M{"docs": bson.M{"$slice": bson.A{"$docs", limit}}}}},
+		{{Key: "$unwind", Value: "$docs"}},
+		{{Key: "$replaceRoot", Value: bson.M{"newRoot": "$docs"}}},
+		{{Key: "$sort", Value: bson.D{{Key: "createDate", Value: -1}, {Key: "id", Value: -1}}}},
+	}
+	return aggregateInto(database.GetMongoClient().Collection(name), pipe, results)
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"context"
+	"openseasync/database"
+	"openseasync/logs"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// chainCollections collections whose documents are keyed by chain
+var chainCollections = []string{"assets", "collections", "contracts", "orders", "item_activitys", "indexer_states"}
+
+// withChain restrict filter to network, every chain is kept when network is empty
+func withChain(filter bson.M, network string) bson.M {
+	if network != "" {
+		filter["chain"] = network
+	}
+	return filter
+}
+
+// BackfillChain set network on the documents synced before they were keyed by chain
+func BackfillChain(network string) error {
+	db := database.GetMongoClient()
+	for _, name := range chainCollections {
+		result, err := db.Collection(name).UpdateMany(
+			context.TODO(),
+			bson.M{"chain": bson.M{"$exists": false}},
+			bson.M{"$set": bson.M{"chain": network}})
+		if err != nil {
+			logs.GetLogger().Error(err)
+			return err
+		}
+		if result.ModifiedCount > 0 {
+			logs.GetLogger().Infof("set chain %s on %d %s", network, result.ModifiedCount, name)
+		}
+	}
+	return nil
+}
+
+// chainIndex a unique key of chain keyed documents, among those matching partial when set
+type chainIndex struct {
+	collection string
+	keys       bson.D
+	partial    bson.M
+}
+
+// chainIndexes the same contract and token on two chains are different documents,
+// soft deleted copies and chain read activity, which has no opensea id, are left out
+var chainIndexes = []chainIndex{
+	{"assets", bson.D{{"chain", 1}, {"userMetamaskId", 1}, {"contractAddress", 1}, {"collectibleTokenId", 1}}, bson.M{"isDelete": 0}},
+	{"collections", bson.D{{"chain", 1}, {"userMetamaskId", 1}, {"id", 1}}, bson.M{"isDelete": 0}},
+	{"contracts", bson.D{{"chain", 1}, {"address", 1}}, nil},
+	{"orders", bson.D{{"chain", 1}, {"id", 1}}, nil},
+	{"item_activitys", bson.D{{"chain", 1}, {"id", 1}, {"contractAddress", 1}, {"tokenId", 1}},
+		bson.M{"isDelete": 0, "id": bson.M{"$gt": 0}}},
+	{"item_activitys", bson.D{{"chain", 1}, {"transaction.transaction_hash", 1}, {"logIndex", 1}, {"contractAddress", 1}, {"tokenId", 1}},
+		bson.M{"source": ACTIVITY_SOURCE_CHAIN}},
+	{"indexer_states", bson.D{{"chain", 1}, {"contractAddress", 1}}, nil},
+	{"collection_holders", bson.D{{"chain", 1}, {"collectionId", 1}, {"syncTime", 1}}, nil},
+	{"token_prices", bson.D{{"chain", 1}, {"symbol", 1}, {"hour", 1}}, nil},
+}
+
+// EnsureChainIndexes create the unique indexes keying documents by chain, an index the existing duplicates
+// prevent is logged and skipped
+func EnsureChainIndexes() error {
+	var result error
+	db := database.GetMongoClient()
+	for _, index := range chainIndexes {
+		opts := options.Index().SetUnique(true)
+		if index.partial != nil {
+			opts.SetPartialFilterExpression(index.partial)
+		}
+		model := mongo.IndexModel{Keys: index.keys, Options: opts}
+		if _, err := db.Collection(index.collection).Indexes().CreateOne(context.TODO(), model); err != nil {
+			logs.GetLogger().Errorf("unique index on %s: %s", index.collection, err)
+			result = err
+		}
+	}
+	return result
+}
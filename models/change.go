@@ -0,0 +1,145 @@
+package models
+
+import (
+	"context"
+	"openseasync/database"
+	"openseasync/logs"
+	"reflect"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	CHANGE_KIND_ASSET      = "asset"
+	CHANGE_KIND_COLLECTION = "collection"
+)
+
+// AssetChange one field of an asset or collection changed by a sync
+type AssetChange struct {
+	Chain          string      `json:"chain" bson:"chain"`                   // 所在链
+	Kind           string      `json:"kind" bson:"kind"`                     // asset 或 collection
+	CollectibleId  int         `json:"collectibleId" bson:"collectibleId"`   // NFT id, 集合变更时为 0
+	CollectionId   string      `json:"collectionId" bson:"collectionId"`     // 集合ID
+	UserMetamaskID string      `json:"userMetamaskId" bson:"userMetamaskId"` // 同步的用户地址
+	Field          string      `json:"field" bson:"field"`                   // 变更字段
+	OldValue       interface{} `json:"oldValue" bson:"oldValue"`             // 旧值
+	NewValue       interface{} `json:"newValue" bson:"newValue"`             // 新值
+	RefreshTime    int64       `json:"refreshTime" bson:"refreshTime"`       // 刷新时间
+	CreateDate     int64       `json:"createDate" bson:"createDate"`
+}
+
+// assetChangeFields fields of an asset taken from opensea, the others are rewritten by every sync,
+// the ownership check, the order sync and the caches whatever happened on opensea
+var assetChangeFields = map[string]bool{
+	"collectibleName": true, "coverImageUrl": true, "coverPreviewUrl": true, "thumbnailUrl": true, "animationUrl": true,
+	"animationOriginalUrl": true, "fileUrl": true, "description": true, "numSales": true, "ownerMetamaskId": true,
+	"ownerName": true, "ownerImgUrl": true, "creatorMetamaskId": true, "creatorPersonalSite": true, "creatorName": true,
+	"creatorImgUrl": true, "collectionId": true, "collectionName": true, "traits": true, "sellOrders": true, "price": true,
+	"status": true, "startTime": true, "endTime": true,
+}
+
+// collectionChangeFields fields of a collection taken from opensea, floor price changes are recorded by
+// updateCollectionFloorPrice and the creator is recomputed by the asset sync.
+// The cover image is stored under a key ending with a space.
+var collectionChangeFields = map[string]bool{
+	"collectionName": true, "userCoverUrl": true, "description": true, "coverImageUrl ": true, "coverLargeImageURL": true,
+	"itemsCount": true, "totalVolume": true, "ownersCount": true,
+}
+
+// diffDocument the top level fields among tracked that differ between stored and incoming, sorted by field
+func diffDocument(stored, incoming bson.M, tracked map[string]bool, change AssetChange) []interface{} {
+	var fields []string
+	for field := range incoming {
+		if tracked[field] {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	var changes []interface{}
+	for _, field := range fields {
+		if reflect.DeepEqual(stored[field], incoming[field]) {
+			continue
+		}
+		change.Field = field
+		change.OldValue = stored[field]
+		change.NewValue = incoming[field]
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// findStored the stored document matching filter, nil when there is none
+func findStored(db *mongo.Database, name string, filter bson.M) (bson.M, error) {
+	var stored bson.M
+	err := db.Collection(name).FindOne(context.TODO(), filter).Decode(&stored)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	return stored, err
+}
+
+// insertAssetChanges write change records to asset_changes
+func insertAssetChanges(ctx context.Context, db *mongo.Database, changes []interface{}) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	if _, err := db.Collection("asset_changes").InsertMany(context.TODO(), changes); err != nil {
+		logs.FromContext(ctx).Error(err)
+		return err
+	}
+	return nil
+}
+
+// newAssetChange a change record of kind with everything but the field and values filled in
+func newAssetChange(network, kind string, collectibleId int, collectionId, user string, refreshTime int64) AssetChange {
+	return AssetChange{
+		Chain:          network,
+		Kind:           kind,
+		CollectibleId:  collectibleId,
+		CollectionId:   collectionId,
+		UserMetamaskID: user,
+		RefreshTime:    refreshTime,
+		CreateDate:     time.Now().UnixMilli(),
+	}
+}
+
+// FindAssetChangesByCollectibleId audit history of an asset, newest first
+func FindAssetChangesByCollectibleId(network string, collectibleId int64, page, pageSize int64) (map[string]interface{}, error) {
+	var (
+		// decoded as bson.M so nested old and new values render as objects
+		changes = make([]bson.M, 0)
+		result  = make(map[string]interface{})
+	)
+	db := database.GetMongoClient()
+	filter := withChain(bson.M{"kind": CHANGE_KIND_ASSET, "collectibleId": collectibleId}, network)
+	total, err := db.Collection("asset_changes").CountDocuments(context.TODO(), filter)
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	totalPage := total / pageSize
+	if total%pageSize != 0 {
+		totalPage++
+	}
+
+	cursor, err := db.Collection("asset_changes").Find(context.TODO(), filter,
+		options.Find().SetSort(bson.D{{Key: "refreshTime", Value: -1}, {Key: "field", Value: 1}}).SetSkip((page-1)*pageSize).SetLimit(pageSize).
+			SetProjection(bson.M{"_id": 0}))
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	if err = cursor.All(context.TODO(), &changes); err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+
+	result["data"] = changes
+	result["metadata"] = map[string]int64{"page": page, "pageSize": pageSize, "total": total, "totalPage": totalPage}
+	return result, nil
+}
@@ -3,23 +3,31 @@ package models
 import (
 	"context"
 	"errors"
+	"math/big"
 	"openseasync/common/utils"
 	"openseasync/database"
+	"openseasync/events"
 	"openseasync/logs"
+	"openseasync/metrics"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 var CONNOT_DELETE_COLLECTION_ERR = errors.New("Cannot delete a collection that has an asset")
 
 // InsertOpenSeaCollection find collection through opensea API and insert
-func InsertOpenSeaCollection(collections *OwnerCollection, user string, refreshTime int64) error {
+func InsertOpenSeaCollection(ctx context.Context, network string, collections *OwnerCollection, user string, refreshTime int64) error {
 	db := database.GetMongoClient()
 
 	for _, v := range collections.Collections {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		var collection = Collection{
 			ID:                 v.Slug,
+			Chain:              network,
 			UserMetamaskID:     user,
 			CreatorMetamaskId:  v.PayoutAddress,
 			CollectionName:     v.Name,
@@ -37,43 +45,60 @@ func InsertOpenSeaCollection(collections *OwnerCollection, user string, refreshT
 			collection.CreatorMetamaskId = user
 		}
 		count, err := db.Collection("collections").
-			CountDocuments(context.TODO(), bson.M{"userMetamaskId": user, "id": v.Slug, "isDelete": 0})
+			CountDocuments(context.TODO(), bson.M{"chain": network, "userMetamaskId": user, "id": v.Slug, "isDelete": 0})
 		if err != nil {
-			logs.GetLogger().Error(err)
+			logs.FromContext(ctx).Error(err)
 			return err
 		}
 		if count == 0 {
 			if _, err = db.Collection("collections").InsertOne(context.TODO(), &collection); err != nil {
-				logs.GetLogger().Error(err)
+				logs.FromContext(ctx).Error(err)
 				return err
 			}
 		} else {
 			// update
 			collectionByte, err := bson.Marshal(collection)
 			if err != nil {
-				logs.GetLogger().Error(err)
+				logs.FromContext(ctx).Error(err)
 				return err
 			}
 			var tmpCollection bson.M
 			if err := bson.Unmarshal(collectionByte, &tmpCollection); err != nil {
-				logs.GetLogger().Error(err)
+				logs.FromContext(ctx).Error(err)
 				return err
 			}
-			if _, err = db.Collection("collections").UpdateOne(
-				context.TODO(),
-				bson.M{"userMetamaskId": user, "id": v.Slug, "isDelete": 0},
-				bson.M{"$set": tmpCollection}); err != nil {
-				logs.GetLogger().Error(err)
+			filter := bson.M{"chain": network, "userMetamaskId": user, "id": v.Slug, "isDelete": 0}
+			stored, err := findStored(db, "collections", filter)
+			if err != nil {
+				logs.FromContext(ctx).Error(err)
 				return err
 			}
+			if _, err = db.Collection("collections").UpdateOne(context.TODO(), filter, bson.M{"$set": tmpCollection}); err != nil {
+				logs.FromContext(ctx).Error(err)
+				return err
+			}
+			if stored != nil {
+				changes := diffDocument(stored, tmpCollection, collectionChangeFields,
+					newAssetChange(network, CHANGE_KIND_COLLECTION, 0, v.Slug, user, refreshTime))
+				if err := insertAssetChanges(ctx, db, changes); err != nil {
+					return err
+				}
+			}
 		}
+		metrics.AddUpserted("collections", 1)
+		events.Publish(ctx, events.Event{
+			Type:         events.EVENT_TYPE_COLLECTION_UPSERTED,
+			Chain:        network,
+			Wallets:      []string{user, collection.CreatorMetamaskId},
+			CollectionId: collection.ID,
+		})
 	}
 
 	if _, err := db.Collection("collections").UpdateMany(
 		context.TODO(),
-		bson.M{"userMetamaskId": user, "refreshTime": bson.M{"$lt": refreshTime}, "isDelete": 0},
-		bson.M{"$set": bson.M{"isDelete": 1}}); err != nil {
-		logs.GetLogger().Error(err)
+		bson.M{"chain": network, "userMetamaskId": user, "refreshTime": bson.M{"$lt": refreshTime}, "isDelete": 0},
+		softDeleted()); err != nil {
+		logs.FromContext(ctx).Error(err)
 		return err
 	}
 	return nil
@@ -81,13 +106,13 @@ func InsertOpenSeaCollection(collections *OwnerCollection, user string, refreshT
 }
 
 // FindCollectionByUserMetamaskID find collections by usermetamaskid
-func FindCollectionByUserMetamaskID(userMetamaskId string, page, pageSize int64) (map[string]interface{}, error) {
+func FindCollectionByUserMetamaskID(network, userMetamaskId string, page, pageSize int64) (map[string]interface{}, error) {
 	var (
 		collections = make([]ResponseColl, 0)
 		result      = make(map[string]interface{})
 	)
 	db := database.GetMongoClient()
-	total, err := db.Collection("collections").CountDocuments(context.TODO(), bson.M{"userMetamaskId": userMetamaskId, "isDelete": 0})
+	total, err := db.Collection("collections").CountDocuments(context.TODO(), withChain(bson.M{"userMetamaskId": userMetamaskId, "isDelete": 0}, network))
 	if err != nil && err != mongo.ErrNoDocuments {
 		logs.GetLogger().Error(err)
 		return nil, err
@@ -98,7 +123,7 @@ func FindCollectionByUserMetamaskID(userMetamaskId string, page, pageSize int64)
 	}
 
 	pipe := mongo.Pipeline{
-		{{"$match", bson.M{"userMetamaskId": userMetamaskId, "isDelete": 0}}},
+		{{"$match", withChain(bson.M{"userMetamaskId": userMetamaskId, "isDelete": 0}, network)}},
 		{{"$skip", (page - 1) * pageSize}},
 		{{"$limit", pageSize}},
 		{{"$lookup", bson.M{
@@ -138,15 +163,14 @@ func FindCollectionByUserMetamaskID(userMetamaskId string, page, pageSize int64)
 }
 
 // FindCollectionByCollectionID find collections by collectionId
-func FindCollectionByCollectionID(collectionId string) (map[string]interface{}, error) {
+func FindCollectionByCollectionID(network, collectionId string) (map[string]interface{}, error) {
 	var (
-		collection  bson.M
-		collections = make([]bson.M, 0)
+		collections = make([]ResponseCollection, 0)
 		result      = make(map[string]interface{})
 	)
 	db := database.GetMongoClient()
 	pipe := mongo.Pipeline{
-		{{"$match", bson.M{"id": collectionId, "isDelete": 0}}},
+		{{"$match", withChain(bson.M{"id": collectionId, "isDelete": 0}, network)}},
 		{{"$lookup", bson.M{
 			"from":         "users",
 			"localField":   "creatorMetamaskId",
@@ -160,7 +184,7 @@ func FindCollectionByCollectionID(collectionId string) (map[string]interface{},
 			"$addFields", bson.M{"userId": "$user_item.id", "userName": "$user_item.userName", "avatarUrl": "$user_item.avatarUrl"},
 		}},
 		{{"$project",
-			bson.M{"_id": 0, "id": 1, "userId": 1, "userMetamaskId": 1, "userCoverUrl": 1, "avatarUrl": 1,
+			bson.M{"_id": 0, "id": 1, "chain": 1, "userId": 1, "userMetamaskId": 1, "userCoverUrl": 1, "avatarUrl": 1,
 				"userName": 1, "itemsCount": 1, "ownersCount": 1, "floorPrice": 1, "highestPrice": 1,
 				"collectionName": 1, "likesCount": 1, "viewsCount": 1, "description": 1}}},
 	}
@@ -176,8 +200,7 @@ func FindCollectionByCollectionID(collectionId string) (map[string]interface{},
 	}
 
 	if len(collections) >= 1 {
-		collection = collections[0]
-		result["data"] = collection
+		result["data"] = collections[0]
 	} else {
 		result["data"] = ResponseCollection{}
 	}
@@ -197,10 +220,10 @@ func FindUserMediaByUserId(userMetamaskId string) (interface{}, error) {
 }
 
 // DeleteCollectionByCollectionId delete empty collection
-func DeleteCollectionByCollectionId(user, slug string) error {
+func DeleteCollectionByCollectionId(network, user, slug string) error {
 	db := database.GetMongoClient()
 	row, err := db.Collection("assets").CountDocuments(
-		context.TODO(), bson.M{"userMetamaskId": user, "collectionId": slug, "is_delete": 0})
+		context.TODO(), withChain(bson.M{"userMetamaskId": user, "collectionId": slug, "is_delete": 0}, network))
 	if err != nil {
 		logs.GetLogger().Error(err)
 		return err
@@ -213,11 +236,45 @@ func DeleteCollectionByCollectionId(user, slug string) error {
 
 	if _, err := db.Collection("collections").UpdateMany(
 		context.TODO(),
-		bson.M{"userMetamaskId": user, "id": slug, "isDelete": 0},
-		bson.M{"$set": bson.M{"isDelete": 1}}); err != nil {
+		withChain(bson.M{"userMetamaskId": user, "id": slug, "isDelete": 0}, network),
+		softDeleted()); err != nil {
 		logs.GetLogger().Error(err)
 		return err
 	}
 
 	return nil
 }
+
+// FindCollectionFloorPrices lowest known floor price in wei of each collection, collections without one are left out
+func FindCollectionFloorPrices(network string, collectionIds []string) (map[string]*big.Int, error) {
+	var (
+		collections = make([]Collection, 0)
+		result      = make(map[string]*big.Int)
+	)
+	if len(collectionIds) == 0 {
+		return result, nil
+	}
+	db := database.GetMongoClient()
+	cursor, err := db.Collection("collections").Find(context.TODO(),
+		withChain(bson.M{"id": bson.M{"$in": collectionIds}, "floorPrice": bson.M{"$nin": bson.A{"", nil}}}, network),
+		options.Find().SetProjection(bson.M{"_id": 0, "id": 1, "floorPrice": 1}))
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	if err = cursor.All(context.TODO(), &collections); err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	// every synced wallet keeps its own copy of a collection, the floor is the lowest of them
+	for _, v := range collections {
+		floor, ok := new(big.Int).SetString(v.FloorPrice, 10)
+		if !ok || floor.Sign() <= 0 {
+			continue
+		}
+		if current, exists := result[v.ID]; !exists || floor.Cmp(current) < 0 {
+			result[v.ID] = floor
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,168 @@
+package models
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"openseasync/database"
+	"openseasync/logs"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// erasedCollections collections whose documents synced for a wallet are erased with it, traits and top
+// ownerships are embedded in the assets
+var erasedCollections = []string{"users", "assets", "collections", "asset_changes", "sync_runs"}
+
+// Erasure the audit record of the data of a wallet being erased
+type Erasure struct {
+	Id          string           `json:"id" bson:"id"`
+	WalletHash  string           `json:"walletHash" bson:"walletHash"`   // 小写地址的 sha256, 地址本身不保留
+	Reason      string           `json:"reason" bson:"reason"`           // 删除原因
+	Deleted     map[string]int64 `json:"deleted" bson:"deleted"`         // 各表删除的文档数
+	SyncBlocked bool             `json:"syncBlocked" bson:"syncBlocked"` // 为 true 时禁止再次同步该地址
+	CreateDate  int64            `json:"createDate" bson:"createDate"`
+	OptInDate   int64            `json:"optInDate" bson:"optInDate"` // 重新允许同步的时间
+}
+
+// WalletHash how erasures name a wallet
+func WalletHash(wallet string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(wallet)))
+	return hex.EncodeToString(sum[:])
+}
+
+// EraseWallet remove every document synced for wallet on every chain and block syncing it again.
+// The erasure is recorded before anything is removed, so no new sync starts while it runs, stopSyncs is
+// called then and must return once the syncs already running stopped writing.
+func EraseWallet(erasure *Erasure, wallet string, stopSyncs func()) error {
+	db := database.GetMongoClient()
+	erasure.WalletHash = WalletHash(wallet)
+	erasure.SyncBlocked = true
+	erasure.Deleted = make(map[string]int64)
+	if _, err := db.Collection("erasures").InsertOne(context.TODO(), erasure); err != nil {
+		logs.GetLogger().Error(err)
+		return err
+	}
+	stopSyncs()
+
+	// the counts are recorded however far the erasure got, so a failed one shows what is left to remove
+	err := eraseWalletDocuments(erasure, strings.ToLower(wallet))
+	if _, saveErr := db.Collection("erasures").UpdateOne(context.TODO(), bson.M{"id": erasure.Id},
+		bson.M{"$set": bson.M{"deleted": erasure.Deleted}}); saveErr != nil {
+		logs.GetLogger().Error(saveErr)
+		if err == nil {
+			err = saveErr
+		}
+	}
+	return err
+}
+
+// eraseWalletDocuments remove the documents naming wallet, adding the count removed from each collection to
+// erasure as it goes
+func eraseWalletDocuments(erasure *Erasure, wallet string) error {
+	db := database.GetMongoClient()
+	type erasedDocuments struct {
+		collection string
+		filter     bson.M
+	}
+	deletes := make([]erasedDocuments, 0, len(erasedCollections)+4)
+	for _, name := range erasedCollections {
+		deletes = append(deletes, erasedDocuments{name, bson.M{"userMetamaskId": wallet}})
+	}
+	// the trades the wallet took part in, the listings and bids it made, and the webhook payloads naming it,
+	// delivered, queued or dead. Chain activity keeps checksummed addresses, so they are matched in any case.
+	exact := primitive.Regex{Pattern: "^" + regexp.QuoteMeta(wallet) + "$", Options: "i"}
+	named := primitive.Regex{Pattern: regexp.QuoteMeta(wallet), Options: "i"}
+	deletes = append(deletes,
+		erasedDocuments{"item_activitys", bson.M{"$or": bson.A{
+			bson.M{"sellerMetamaskId": exact},
+			bson.M{"buyerMetamaskId": exact},
+			bson.M{"transaction.from_account.address": exact},
+		}}},
+		erasedDocuments{"orders", bson.M{"auctionMetamaskId": exact}},
+		erasedDocuments{"webhook_deliveries", bson.M{"payload": named}},
+		erasedDocuments{"webhook_dead_letters", bson.M{"payload": named}},
+	)
+	for _, v := range deletes {
+		deleted, err := db.Collection(v.collection).DeleteMany(context.TODO(), v.filter)
+		if err != nil {
+			logs.GetLogger().Error(err)
+			return err
+		}
+		erasure.Deleted[v.collection] = deleted.DeletedCount
+	}
+
+	// the holder snapshots of collections and the webhooks following the wallet name it among others,
+	// only it is removed from them
+	if _, err := db.Collection("collection_holders").UpdateMany(context.TODO(), bson.M{"holders": wallet},
+		bson.M{"$pull": bson.M{"holders": wallet}}); err != nil {
+		logs.GetLogger().Error(err)
+		return err
+	}
+	if _, err := db.Collection("webhooks").UpdateMany(context.TODO(), bson.M{"wallets": wallet},
+		bson.M{"$pull": bson.M{"wallets": wallet}}); err != nil {
+		logs.GetLogger().Error(err)
+		return err
+	}
+	return nil
+}
+
+// IsSyncBlocked whether wallet was erased and has not opted in again
+func IsSyncBlocked(wallet string) (bool, error) {
+	db := database.GetMongoClient()
+	count, err := db.Collection("erasures").CountDocuments(context.TODO(), bson.M{"walletHash": WalletHash(wallet), "syncBlocked": true})
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ClearSyncBlock let an erased wallet be synced again, false when it is not blocked
+func ClearSyncBlock(wallet string) (bool, error) {
+	db := database.GetMongoClient()
+	result, err := db.Collection("erasures").UpdateMany(context.TODO(), bson.M{"walletHash": WalletHash(wallet), "syncBlocked": true},
+		bson.M{"$set": bson.M{"syncBlocked": false, "optInDate": time.Now().UnixMilli()}})
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// FindErasures the audit records, most recent first
+func FindErasures(page, pageSize int64) (map[string]interface{}, error) {
+	var (
+		erasures = make([]Erasure, 0)
+		result   = make(map[string]interface{})
+	)
+	db := database.GetMongoClient()
+	total, err := db.Collection("erasures").CountDocuments(context.TODO(), bson.M{})
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	totalPage := total / pageSize
+	if total%pageSize != 0 {
+		totalPage++
+	}
+
+	cursor, err := db.Collection("erasures").Find(context.TODO(), bson.M{},
+		options.Find().SetSort(bson.M{"createDate": -1}).SetSkip((page-1)*pageSize).SetLimit(pageSize))
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	if err = cursor.All(context.TODO(), &erasures); err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	result["data"] = erasures
+	result["metadata"] = map[string]int64{"page": page, "pageSize": pageSize, "total": total, "totalPage": totalPage}
+	return result, nil
+}
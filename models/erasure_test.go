@@ -0,0 +1,87 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"openseasync/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// testMongoURI names the mongo the erasure test runs against, the test is skipped without it
+const testMongoURI = "OPENSEASYNC_TEST_MONGO_URI"
+
+// TestEraseWallet the trades the wallet took part in are removed whatever the case of its address, the
+// other documents are kept, and the running syncs are stopped once the wallet is blocked
+func TestEraseWallet(t *testing.T) {
+	uri := os.Getenv(testMongoURI)
+	if uri == "" || testing.Short() {
+		t.Skipf("set %s to a mongo the test can write to", testMongoURI)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := client.Database(fmt.Sprintf("openseasync_erasure_%d", time.Now().UnixNano()))
+	t.Cleanup(func() {
+		db.Drop(context.Background())
+		client.Disconnect(context.Background())
+	})
+	database.SetMongoClient(db)
+
+	const (
+		wallet    = "0xabcdef0000000000000000000000000000000001"
+		checksum  = "0xABCdef0000000000000000000000000000000001"
+		other     = "0x2222222222222222222222222222222222222222"
+		bystander = "0x3333333333333333333333333333333333333333"
+	)
+	fixtures := map[string][]interface{}{
+		"assets": {Asset{Id: 1, UserMetamaskID: wallet}, Asset{Id: 2, UserMetamaskID: other}},
+		"item_activitys": {
+			ItemActivity{Id: 1, SellerMetamaskId: wallet, BuyerMetamaskId: other},
+			ItemActivity{Id: 2, SellerMetamaskId: other, BuyerMetamaskId: checksum},
+			ItemActivity{Id: 3, SellerMetamaskId: other, BuyerMetamaskId: bystander},
+		},
+	}
+	for collection, documents := range fixtures {
+		if _, err := db.Collection(collection).InsertMany(ctx, documents); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stopped := false
+	erasure := &Erasure{Id: "erasure", CreateDate: time.Now().UnixMilli()}
+	err = EraseWallet(erasure, checksum, func() {
+		blocked, err := IsSyncBlocked(wallet)
+		if err != nil || !blocked {
+			t.Errorf("syncs were stopped before the wallet was blocked: %v %v", blocked, err)
+		}
+		stopped = true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !stopped {
+		t.Error("the running syncs were not stopped")
+	}
+	for collection, want := range map[string]int64{"assets": 1, "item_activitys": 2} {
+		if got := erasure.Deleted[collection]; got != want {
+			t.Errorf("%s: deleted %d, want %d", collection, got, want)
+		}
+	}
+	left, err := db.Collection("item_activitys").CountDocuments(ctx, bson.M{"id": 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if left != 1 {
+		t.Error("a trade the wallet took no part in was removed")
+	}
+}
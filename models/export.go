@@ -0,0 +1,151 @@
+package models
+
+import (
+	"context"
+	"openseasync/database"
+	"openseasync/logs"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ExportColumns columns of both exports, in order, activity exports append event, from and to
+var ExportColumns = []string{"contract", "tokenId", "name", "collection", "price", "currency", "usdValue", "timestamp", "txHash"}
+
+// ActivityExportColumns columns of the activity export, in order
+var ActivityExportColumns = append(append([]string{}, ExportColumns...), "event", "from", "to")
+
+// ExportRow one line of a wallet export, every row carries every key so ndjson lines share one shape
+type ExportRow struct {
+	Contract   string `json:"contract"`
+	TokenId    string `json:"tokenId"`
+	Name       string `json:"name"`
+	Collection string `json:"collection"`
+	Price      string `json:"price"` // in the smallest unit of currency, as stored
+	Currency   string `json:"currency"`
+	UsdValue   string `json:"usdValue"`  // assets at the rate of the last sync, activities at the rate of the trade, empty when unknown
+	Timestamp  string `json:"timestamp"` // RFC 3339, UTC
+	TxHash     string `json:"txHash"`
+	Event      string `json:"event"`
+	From       string `json:"from"`
+	To         string `json:"to"`
+}
+
+// Values the row as strings, in the order of columns, cells a spreadsheet would read as a formula are quoted
+func (r ExportRow) Values(columns []string) []string {
+	values := map[string]string{
+		"contract": r.Contract, "tokenId": r.TokenId, "name": r.Name, "collection": r.Collection, "price": r.Price,
+		"currency": r.Currency, "usdValue": r.UsdValue, "timestamp": r.Timestamp, "txHash": r.TxHash,
+		"event": r.Event, "from": r.From, "to": r.To,
+	}
+	result := make([]string, 0, len(columns))
+	for _, v := range columns {
+		result = append(result, escapeFormula(values[v]))
+	}
+	return result
+}
+
+// escapeFormula prefix value with a quote when it starts like a spreadsheet formula
+func escapeFormula(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// ExportWalletAssets call fn with every asset held by user, reading them one at a time from a cursor
+func ExportWalletAssets(ctx context.Context, network, user string, fn func(ExportRow) error) error {
+	db := database.GetMongoClient()
+	cursor, err := db.Collection("assets").Find(context.TODO(),
+		withChain(bson.M{"userMetamaskId": user, "isDelete": 0}, network),
+		options.Find().SetSort(bson.D{{Key: "contractAddress", Value: 1}, {Key: "collectibleTokenId", Value: 1}}))
+	if err != nil {
+		logs.FromContext(ctx).Error(err)
+		return err
+	}
+	return eachDocument(ctx, cursor, func(cursor *mongo.Cursor) error {
+		var asset Asset
+		if err := cursor.Decode(&asset); err != nil {
+			return err
+		}
+		payToken := asset.SellOrders.PayTokenContract
+		return fn(ExportRow{
+			Contract:   asset.ContractAddress,
+			TokenId:    asset.CollectibleTokenId,
+			Name:       asset.CollectibleName,
+			Collection: asset.CollectionID,
+			Price:      asset.Price,
+			Currency:   payToken.Symbol,
+			UsdValue:   usdValue(asset.Price, payToken.TokenDecimals(), payToken.UsdPrice),
+			Timestamp:  exportTime(asset.CreateDate),
+		})
+	})
+}
+
+// ExportWalletActivity call fn with every activity user bought or sold in, oldest first, reading them one at a time from a cursor
+func ExportWalletActivity(ctx context.Context, network, user string, fn func(ExportRow) error) error {
+	db := database.GetMongoClient()
+	filter := withChain(bson.M{"isDelete": 0, "$or": bson.A{bson.M{"sellerMetamaskId": user}, bson.M{"buyerMetamaskId": user}}}, network)
+	cursor, err := db.Collection("item_activitys").Find(context.TODO(), filter,
+		options.Find().SetSort(bson.D{{Key: "createDate", Value: 1}, {Key: "id", Value: 1}}))
+	if err != nil {
+		logs.FromContext(ctx).Error(err)
+		return err
+	}
+	return eachDocument(ctx, cursor, func(cursor *mongo.Cursor) error {
+		var activity ItemActivity
+		if err := cursor.Decode(&activity); err != nil {
+			return err
+		}
+		return fn(ExportRow{
+			Contract:   activity.ContractAddress,
+			TokenId:    activity.TokenId,
+			Name:       activity.CollectibleName,
+			Collection: activity.CollectionId,
+			Price:      activity.Price,
+			Currency:   activity.PayTokenContract.Symbol,
+			UsdValue:   activity.PriceInUsd,
+			Timestamp:  exportTime(activity.CreateDate),
+			TxHash:     activity.Transaction.TransactionHash,
+			Event:      activity.TradeType,
+			From:       activity.SellerMetamaskId,
+			To:         activity.BuyerMetamaskId,
+		})
+	})
+}
+
+// eachDocument call fn for each document of cursor, then close it
+func eachDocument(ctx context.Context, cursor *mongo.Cursor, fn func(*mongo.Cursor) error) error {
+	defer cursor.Close(context.TODO())
+	for cursor.Next(context.TODO()) {
+		if err := fn(cursor); err != nil {
+			logs.FromContext(ctx).Error(err)
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		logs.FromContext(ctx).Error(err)
+		return err
+	}
+	return nil
+}
+
+// usdValue amount in the smallest unit of a token with decimals at usdPrice per token, empty when either is unknown
+func usdValue(amount string, decimals int, usdPrice string) string {
+	value, ok := TokenValue(amount, decimals, usdPrice)
+	if !ok {
+		return ""
+	}
+	return strconv.FormatFloat(value, 'f', 2, 64)
+}
+
+func exportTime(milli int64) string {
+	if milli == 0 {
+		return ""
+	}
+	return time.UnixMilli(milli).UTC().Format(time.RFC3339)
+}
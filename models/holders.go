@@ -0,0 +1,232 @@
+package models
+
+import (
+	"context"
+	"openseasync/database"
+	"openseasync/logs"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// holderBuckets ranges of copies held the distribution counts wallets in, a max of 0 is unbounded
+var holderBuckets = []struct {
+	label    string
+	min, max int64
+}{
+	{"1", 1, 1},
+	{"2-5", 2, 5},
+	{"6-20", 6, 20},
+	{"20+", 21, 0},
+}
+
+// HolderAnalytics who holds a collection, computed from the top ownerships of its synced assets
+type HolderAnalytics struct {
+	Chain                  string             `json:"chain"`                  // 所在链, 为空时统计所有链
+	CollectionId           string             `json:"collectionId"`           // 集合ID
+	OwnersCount            int                `json:"ownersCount"`            // opensea 给出的持有人数
+	Holders                int64              `json:"holders"`                // 同步数据中的持有人数
+	Items                  int64              `json:"items"`                  // 同步到的 NFT 数
+	Quantity               int64              `json:"quantity"`               // 持有的总份数
+	UniqueHolderPercentage float64            `json:"uniqueHolderPercentage"` // 持有人数占 NFT 数的百分比
+	TopHolders             []CollectionHolder `json:"topHolders"`             // 持有份数最多的钱包
+	Distribution           []HolderBucket     `json:"distribution"`           // 按持有份数分段的钱包数
+	Churn                  *HolderChurn       `json:"churn"`                  // 与上次同步相比的变化, 首次统计时为空
+	SyncTime               int64              `json:"syncTime"`               // 统计所依据的最近刷新时间
+}
+
+// CollectionHolder a wallet holding copies of a collection
+type CollectionHolder struct {
+	Owner         string  `json:"owner"`         // 持有人地址
+	ProfileImgUrl string  `json:"profileImgUrl"` // 持有人头像
+	Quantity      int64   `json:"quantity"`      // 持有份数
+	Tokens        int64   `json:"tokens"`        // 持有的不同 NFT 数
+	Percentage    float64 `json:"percentage"`    // 占总份数的百分比
+}
+
+// HolderBucket how many wallets hold a range of copies
+type HolderBucket struct {
+	Range      string  `json:"range"`      // 1 2-5 6-20 20+
+	Wallets    int64   `json:"wallets"`    // 钱包数
+	Percentage float64 `json:"percentage"` // 占持有人数的百分比
+}
+
+// HolderChurn the holders gained and lost since the previous sync of a collection
+type HolderChurn struct {
+	PreviousSyncTime int64   `json:"previousSyncTime"` // 上次统计所依据的刷新时间
+	PreviousHolders  int64   `json:"previousHolders"`  // 上次的持有人数
+	Joined           int64   `json:"joined"`           // 新增的持有人
+	Left             int64   `json:"left"`             // 不再持有的持有人
+	Retained         int64   `json:"retained"`         // 仍然持有的持有人
+	ChurnRate        float64 `json:"churnRate"`        // 不再持有的持有人占上次持有人数的百分比
+}
+
+// holderSnapshot the holders of a collection as of a sync, kept in collection_holders to compute churn
+type holderSnapshot struct {
+	Chain        string   `bson:"chain"`
+	CollectionId string   `bson:"collectionId"`
+	SyncTime     int64    `bson:"syncTime"`
+	Holders      []string `bson:"holders"`
+	CreateDate   int64    `bson:"createDate"`
+}
+
+// FindHolderAnalytics the holder analytics of a collection with its top holders, nil when nothing of it was synced.
+// Each token is counted once, from the copy refreshed last, as every wallet syncing it keeps its own.
+func FindHolderAnalytics(network, collectionId string, top int64) (*HolderAnalytics, error) {
+	db := database.GetMongoClient()
+	cursor, err := db.Collection("assets").Aggregate(context.TODO(), mongo.Pipeline{
+		{{Key: "$match", Value: withChain(bson.M{"collectionId": collectionId, "isDelete": 0}, network)}},
+		{{Key: "$sort", Value: bson.M{"refreshTime": -1}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":                 bson.M{"chain": "$chain", "contractAddress": "$contractAddress", "collectibleTokenId": "$collectibleTokenId"},
+			"assetsTopOwnerships": bson.M{"$first": "$assetsTopOwnerships"},
+			"refreshTime":         bson.M{"$first": "$refreshTime"},
+		}}},
+	})
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	var tokens []struct {
+		AssetsTopOwnerships []AssetsTopOwnership `bson:"assetsTopOwnerships"`
+		RefreshTime         int64                `bson:"refreshTime"`
+	}
+	if err = cursor.All(context.TODO(), &tokens); err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	result := &HolderAnalytics{Chain: network, CollectionId: collectionId, Items: int64(len(tokens))}
+	holders := make(map[string]*CollectionHolder)
+	for _, token := range tokens {
+		if token.RefreshTime > result.SyncTime {
+			result.SyncTime = token.RefreshTime
+		}
+		for _, v := range token.AssetsTopOwnerships {
+			owner := strings.ToLower(v.Owner)
+			if owner == "" {
+				continue
+			}
+			quantity, err := strconv.ParseInt(v.Quantity, 10, 64)
+			if err != nil || quantity <= 0 {
+				quantity = 1
+			}
+			holder, ok := holders[owner]
+			if !ok {
+				holder = &CollectionHolder{Owner: owner}
+				holders[owner] = holder
+			}
+			if holder.ProfileImgUrl == "" {
+				holder.ProfileImgUrl = v.ProfileImgUrl
+			}
+			holder.Quantity += quantity
+			holder.Tokens++
+			result.Quantity += quantity
+		}
+	}
+
+	result.Holders = int64(len(holders))
+	result.UniqueHolderPercentage = float64(result.Holders) / float64(result.Items) * 100
+	ranked := make([]CollectionHolder, 0, len(holders))
+	for _, v := range holders {
+		v.Percentage = float64(v.Quantity) / float64(result.Quantity) * 100
+		ranked = append(ranked, *v)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Quantity != ranked[j].Quantity {
+			return ranked[i].Quantity > ranked[j].Quantity
+		}
+		return ranked[i].Owner < ranked[j].Owner
+	})
+	if int64(len(ranked)) > top {
+		result.TopHolders = ranked[:top]
+	} else {
+		result.TopHolders = ranked
+	}
+
+	result.Distribution = make([]HolderBucket, len(holderBuckets))
+	for i, b := range holderBuckets {
+		result.Distribution[i].Range = b.label
+	}
+	for _, v := range holders {
+		for i, b := range holderBuckets {
+			if v.Quantity >= b.min && (b.max == 0 || v.Quantity <= b.max) {
+				result.Distribution[i].Wallets++
+				break
+			}
+		}
+	}
+	for i := range result.Distribution {
+		result.Distribution[i].Percentage = float64(result.Distribution[i].Wallets) / float64(result.Holders) * 100
+	}
+
+	var collection Collection
+	err = db.Collection("collections").FindOne(context.TODO(), withChain(bson.M{"id": collectionId, "isDelete": 0}, network),
+		options.FindOne().SetSort(bson.M{"refreshTime": -1})).Decode(&collection)
+	if err != nil && err != mongo.ErrNoDocuments {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	result.OwnersCount = collection.OwnersCount
+
+	if result.Churn, err = holderChurn(network, collectionId, result.SyncTime, holders); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// holderChurn compare holders with the snapshot of the sync before syncTime, and keep them as the snapshot of syncTime.
+// Nil when there is no earlier snapshot.
+func holderChurn(network, collectionId string, syncTime int64, holders map[string]*CollectionHolder) (*HolderChurn, error) {
+	db := database.GetMongoClient()
+	snapshot := holderSnapshot{Chain: network, CollectionId: collectionId, SyncTime: syncTime, CreateDate: time.Now().UnixMilli()}
+	for owner := range holders {
+		snapshot.Holders = append(snapshot.Holders, owner)
+	}
+	sort.Strings(snapshot.Holders)
+	if _, err := db.Collection("collection_holders").UpdateOne(context.TODO(),
+		bson.M{"chain": network, "collectionId": collectionId, "syncTime": syncTime},
+		bson.M{"$setOnInsert": snapshot}, options.Update().SetUpsert(true)); err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+
+	var previous holderSnapshot
+	err := db.Collection("collection_holders").FindOne(context.TODO(),
+		bson.M{"chain": network, "collectionId": collectionId, "syncTime": bson.M{"$lt": syncTime}},
+		options.FindOne().SetSort(bson.M{"syncTime": -1})).Decode(&previous)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	} else if err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+
+	churn := &HolderChurn{PreviousSyncTime: previous.SyncTime, PreviousHolders: int64(len(previous.Holders))}
+	before := make(map[string]bool, len(previous.Holders))
+	for _, owner := range previous.Holders {
+		before[owner] = true
+		if _, ok := holders[owner]; ok {
+			churn.Retained++
+		} else {
+			churn.Left++
+		}
+	}
+	for owner := range holders {
+		if !before[owner] {
+			churn.Joined++
+		}
+	}
+	if churn.PreviousHolders > 0 {
+		churn.ChurnRate = float64(churn.Left) / float64(churn.PreviousHolders) * 100
+	}
+	return churn, nil
+}
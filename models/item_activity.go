@@ -11,14 +11,14 @@ import (
 )
 
 // FindItemActivityByCollectionId find item_activity by collection_id
-func FindItemActivityByCollectionId(collectionId string, page, pageSize int64) (map[string]interface{}, error) {
+func FindItemActivityByCollectionId(network, collectionId string, page, pageSize int64) (map[string]interface{}, error) {
 	var (
-		itemActivitys = make([]bson.M, 0)
+		itemActivitys = make([]ResponseItemActivity, 0)
 		result        = make(map[string]interface{})
 	)
 	db := database.GetMongoClient()
 
-	total, err := db.Collection("item_activitys").CountDocuments(context.TODO(), bson.M{"collectionId": collectionId, "isDelete": 0})
+	total, err := db.Collection("item_activitys").CountDocuments(context.TODO(), withChain(bson.M{"collectionId": collectionId, "isDelete": 0}, network))
 	if err != nil && err != mongo.ErrNoDocuments {
 		logs.GetLogger().Error(err)
 		return nil, err
@@ -36,15 +36,17 @@ func FindItemActivityByCollectionId(collectionId string, page, pageSize int64) (
 			{"priceInUsd ", 1},
 			{"collectibleId", 1},
 			{"collectibleName", 1},
-			{"quantity ", 1},
+			{"quantity", 1},
 			{"buyerId", 1},
 			{"buyerMetamaskId", 1},
 			{"buyerName", 1},
 			{"sellerId", 1},
 			{"sellerMetamaskId", 1},
 			{"sellerName", 1},
+			{"createDate", 1},
+			{"chain", 1},
 		})
-	cursor, err := db.Collection("item_activitys").Find(context.TODO(), bson.M{"collectionId": collectionId, "isDelete": 0}, opts)
+	cursor, err := db.Collection("item_activitys").Find(context.TODO(), withChain(bson.M{"collectionId": collectionId, "isDelete": 0}, network), opts)
 	if err != nil && err != mongo.ErrNoDocuments {
 		logs.GetLogger().Error(err)
 		return nil, err
@@ -61,13 +63,13 @@ func FindItemActivityByCollectionId(collectionId string, page, pageSize int64) (
 }
 
 // FindTradeHistoryByCollectibleId find item_activity by collectibleId
-func FindTradeHistoryByCollectibleId(collectibleId int64, page, pageSize int64) (map[string]interface{}, error) {
+func FindTradeHistoryByCollectibleId(network string, collectibleId int64, page, pageSize int64) (map[string]interface{}, error) {
 	var (
-		itemActivitys = make([]bson.M, 0)
+		itemActivitys = make([]ResponseItemActivity, 0)
 		result        = make(map[string]interface{})
 	)
 	db := database.GetMongoClient()
-	total, err := db.Collection("item_activitys").CountDocuments(context.TODO(), bson.M{"collectibleId": collectibleId, "isDelete": 0})
+	total, err := db.Collection("item_activitys").CountDocuments(context.TODO(), withChain(bson.M{"collectibleId": collectibleId, "isDelete": 0}, network))
 	if err != nil {
 		logs.GetLogger().Error(err)
 		return nil, err
@@ -90,8 +92,9 @@ func FindTradeHistoryByCollectibleId(collectibleId int64, page, pageSize int64)
 			{"sellerMetamaskId", 1},
 			{"sellerName", 1},
 			{"createDate", 1},
+			{"chain", 1},
 		})
-	cursor, err := db.Collection("item_activitys").Find(context.TODO(), bson.M{"collectibleId": collectibleId, "isDelete": 0}, opts)
+	cursor, err := db.Collection("item_activitys").Find(context.TODO(), withChain(bson.M{"collectibleId": collectibleId, "isDelete": 0}, network), opts)
 	if err != nil {
 		logs.GetLogger().Error(err)
 		return nil, err
@@ -105,3 +108,22 @@ func FindTradeHistoryByCollectibleId(collectibleId int64, page, pageSize int64)
 	result["metadata"] = map[string]int64{"page": page, "pageSize": pageSize, "total": total, "totalPage": totalPage}
 	return result, nil
 }
+
+// FindWalletSales successful sales user bought or sold in, oldest first
+func FindWalletSales(network, user string) ([]ItemActivity, error) {
+	var activities = make([]ItemActivity, 0)
+	db := database.GetMongoClient()
+	filter := withChain(bson.M{"isDelete": 0, "tradeType": "successful",
+		"$or": bson.A{bson.M{"sellerMetamaskId": user}, bson.M{"buyerMetamaskId": user}}}, network)
+	cursor, err := db.Collection("item_activitys").Find(context.TODO(), filter,
+		options.Find().SetSort(bson.D{{Key: "createDate", Value: 1}, {Key: "id", Value: 1}}))
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	if err = cursor.All(context.TODO(), &activities); err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	return activities, nil
+}
@@ -0,0 +1,161 @@
+package models
+
+import (
+	"context"
+	"openseasync/chain"
+	"openseasync/common/utils"
+	"openseasync/events"
+	"openseasync/logs"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	ORDER_STATUS_ACTIVE    = "active"
+	ORDER_STATUS_FILLED    = "filled"
+	ORDER_STATUS_CANCELLED = "cancelled"
+	ORDER_STATUS_EXPIRED   = "expired"
+
+	ORDER_SIDE_SELL = "sell" // a listing
+	ORDER_SIDE_BUY  = "buy"  // an offer, a bid on the token
+)
+
+// orderStatus the state of an order opensea still returns
+func orderStatus(cancelled, finalized, markedInvalid bool, endTime int64, now time.Time) string {
+	switch {
+	case finalized:
+		return ORDER_STATUS_FILLED
+	case cancelled || markedInvalid:
+		return ORDER_STATUS_CANCELLED
+	case endTime > 0 && endTime <= now.UnixMilli():
+		return ORDER_STATUS_EXPIRED
+	}
+	return ORDER_STATUS_ACTIVE
+}
+
+// orderEndTime the end time of an order closing at closingDate, 0 for an order without one
+func orderEndTime(closingDate string) int64 {
+	if closingDate == "" {
+		return 0
+	}
+	return utils.ParseTime(closingDate)
+}
+
+// wyvernOrderSide the side of a wyvern order, 0 buying and 1 selling
+func wyvernOrderSide(side int) string {
+	if side == 0 {
+		return ORDER_SIDE_BUY
+	}
+	return ORDER_SIDE_SELL
+}
+
+// activeOrders restrict filter to the orders still active, orders synced before statuses were tracked
+// have none and count as active
+func activeOrders(filter bson.M) bson.M {
+	filter["status"] = bson.M{"$in": bson.A{ORDER_STATUS_ACTIVE, nil}}
+	return filter
+}
+
+// liveOrders restrict filter to the actionable orders, active, not past their end time and not failing
+// signature verification
+func liveOrders(filter bson.M, now time.Time) bson.M {
+	filter["$or"] = bson.A{bson.M{"endTime": 0}, bson.M{"endTime": bson.M{"$gt": now.UnixMilli()}}}
+	filter["signatureStatus"] = bson.M{"$ne": chain.SIGNATURE_INVALID}
+	return activeOrders(filter)
+}
+
+// setOrderStatusTime keep when the status of a synced order last changed, whether it changed.
+// previous is the stored status, empty for a new order or one synced before statuses were tracked.
+func setOrderStatusTime(order *Orders, previous string, previousTime int64, now time.Time) bool {
+	if previous == order.Status || (previous == "" && order.Status == ORDER_STATUS_ACTIVE) {
+		order.StatusTime = previousTime
+		if order.StatusTime == 0 {
+			order.StatusTime = now.UnixMilli()
+		}
+		return false
+	}
+	order.StatusTime = now.UnixMilli()
+	return true
+}
+
+// setOrderSignature keep what verifying the signature of order found, invalid orders are kept but no longer live
+func setOrderSignature(order *Orders, signature *chain.OrderSignature) {
+	order.SignatureStatus = signature.Status
+	order.Signer = signature.Signer
+	order.SignatureError = signature.Reason
+}
+
+// closeMissingOrders settle the live orders of an asset opensea no longer returns, those past their end
+// time expired, those followed by a sale of the token their maker took part in were filled and the others cancelled
+func closeMissingOrders(ctx context.Context, db *mongo.Database, network string, collectibleId int, contractAddress, tokenId string,
+	seen []string, now time.Time) error {
+	cursor, err := db.Collection("orders").Find(context.TODO(), activeOrders(bson.M{
+		"chain": network, "collectibleId": collectibleId, "isDelete": 0, "id": bson.M{"$nin": seen}}))
+	if err != nil {
+		logs.FromContext(ctx).Error(err)
+		return err
+	}
+	var missing []Orders
+	if err = cursor.All(context.TODO(), &missing); err != nil {
+		logs.FromContext(ctx).Error(err)
+		return err
+	}
+
+	for i := range missing {
+		order := &missing[i]
+		previous := order.Status
+		order.Status = ORDER_STATUS_CANCELLED
+		if order.EndTime > 0 && order.EndTime <= now.UnixMilli() {
+			order.Status = ORDER_STATUS_EXPIRED
+		} else {
+			sold, err := db.Collection("item_activitys").CountDocuments(context.TODO(), bson.M{"chain": network,
+				"contractAddress": contractAddress, "tokenId": tokenId, "tradeType": "successful", "isDelete": 0,
+				"createDate": bson.M{"$gte": order.StartTime},
+				"$or":        bson.A{bson.M{"sellerMetamaskId": order.AuctionMetamaskId}, bson.M{"buyerMetamaskId": order.AuctionMetamaskId}}})
+			if err != nil {
+				logs.FromContext(ctx).Error(err)
+				return err
+			}
+			if sold > 0 {
+				order.Status = ORDER_STATUS_FILLED
+			}
+		}
+		order.StatusTime = now.UnixMilli()
+		if _, err := db.Collection("orders").UpdateOne(context.TODO(), bson.M{"chain": network, "id": order.Id},
+			bson.M{"$set": bson.M{"status": order.Status, "statusTime": order.StatusTime}}); err != nil {
+			logs.FromContext(ctx).Error(err)
+			return err
+		}
+		publishOrderStatus(ctx, order, previous)
+	}
+	return nil
+}
+
+// publishOrderStatus announce the status change of order, orders failing signature verification were never announced
+// and their changes are not either
+func publishOrderStatus(ctx context.Context, order *Orders, previous string) {
+	if order.SignatureStatus == chain.SIGNATURE_INVALID {
+		return
+	}
+	if previous == "" {
+		previous = ORDER_STATUS_ACTIVE
+	}
+	events.Publish(ctx, events.Event{
+		Type:            events.EVENT_TYPE_ORDER_STATUS_CHANGED,
+		Chain:           order.Chain,
+		Wallets:         []string{order.AuctionMetamaskId},
+		CollectionId:    order.CollectionId,
+		CollectibleId:   order.CollectibleId,
+		ContractAddress: order.ContractAddress,
+		TokenId:         order.TokenId,
+		Data:            OrderStatusChange{Order: *order, PreviousStatus: previous},
+	})
+}
+
+// OrderStatusChange an order whose state moved, sent with order_status_changed events
+type OrderStatusChange struct {
+	Order          Orders `json:"order"`
+	PreviousStatus string `json:"previousStatus"`
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"context"
+	"math/big"
+	"openseasync/chain"
+	"openseasync/config"
+	"openseasync/logs"
+	"strings"
+	"time"
+)
+
+// verifyAssetOwnership compare the owner and quantity opensea gave for asset with the chain,
+// flag the asset on mismatch and correct it when configured to.
+// Verification is best effort, an unreachable node never fails the sync.
+func verifyAssetOwnership(ctx context.Context, asset *Asset, schemaName string) {
+	conf := config.GetConfig().Chain
+	if !conf.VerifyOwnership {
+		return
+	}
+	client, err := chain.GetClient(asset.Chain)
+	if err != nil {
+		logs.FromContext(ctx).Warn(err)
+		return
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	ownership, err := chain.VerifyOwnership(callCtx, client, schemaName, asset.ContractAddress, asset.CollectibleTokenId, asset.UserMetamaskID)
+	if err != nil {
+		logs.FromContext(ctx).Warnf("verify ownership of %s/%s: %s", asset.ContractAddress, asset.CollectibleTokenId, err)
+		return
+	}
+
+	asset.OnChainOwner = ownership.Owner
+	asset.OwnershipCheckTime = time.Now().UnixMilli()
+	quantity, known := ownedQuantity(asset)
+	switch strings.ToUpper(schemaName) {
+	case chain.SCHEMA_ERC721:
+		asset.OwnershipMismatch = ownership.Owner != strings.ToLower(asset.OwnerMetamaskId)
+	case chain.SCHEMA_ERC1155:
+		// opensea only returns the top owners, a wallet left out of them holds an unknown quantity
+		if known {
+			asset.OwnershipMismatch = ownership.Balance.Cmp(quantity) != 0
+		} else {
+			asset.OwnershipMismatch = ownership.Balance.Sign() == 0
+		}
+	}
+	if !asset.OwnershipMismatch {
+		return
+	}
+
+	logs.FromContext(ctx).Warnf("ownership mismatch on %s/%s: opensea owner %s quantity %s, chain owner %s quantity %s",
+		asset.ContractAddress, asset.CollectibleTokenId, asset.OwnerMetamaskId, quantity, ownership.Owner, ownership.Balance)
+	if conf.CorrectOwnership {
+		if ownership.Owner != "" {
+			asset.OwnerMetamaskId = ownership.Owner
+		}
+		for i := range asset.AssetsTopOwnerships {
+			if strings.EqualFold(asset.AssetsTopOwnerships[i].Owner, asset.UserMetamaskID) {
+				asset.AssetsTopOwnerships[i].Quantity = ownership.Balance.String()
+			}
+		}
+		// the copies the wallet holds, 0 once it no longer holds the token
+		if ownership.Balance.IsInt64() {
+			asset.NumOfCopies = int(ownership.Balance.Int64())
+		}
+	}
+}
+
+// ownedQuantity the quantity of asset opensea says its wallet holds, whether the wallet is among the top owners
+func ownedQuantity(asset *Asset) (*big.Int, bool) {
+	for _, ownership := range asset.AssetsTopOwnerships {
+		if !strings.EqualFold(ownership.Owner, asset.UserMetamaskID) {
+			continue
+		}
+		if quantity, ok := new(big.Int).SetString(ownership.Quantity, 10); ok {
+			return quantity, true
+		}
+	}
+	return big.NewInt(0), false
+}
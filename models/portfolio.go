@@ -0,0 +1,208 @@
+package models
+
+import (
+	"context"
+	"math/big"
+	"openseasync/database"
+	"openseasync/logs"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	VALUATION_FLOOR     = "floor"     // collection floor price per copy
+	VALUATION_LAST_SALE = "last_sale" // last successful sale of the token, per copy
+	VALUATION_LISTING   = "listing"   // price of the active sell order, listed assets only
+)
+
+// PortfolioPart value of the assets of one collection, or of the whole wallet
+type PortfolioPart struct {
+	CollectionId   string           `json:"collectionId,omitempty"`
+	CollectionName string           `json:"collectionName,omitempty"`
+	Count          int              `json:"count"`        // 资产数量
+	ValuedCount    int              `json:"valuedCount"`  // 有估值的资产数量
+	Value          string           `json:"value"`        // 估值 ETH wei, 各支付代币按其ETH价格换算
+	StatusCounts   map[string]int64 `json:"statusCounts"` // onHold onSale onAuction 各自数量
+	value          *big.Int
+}
+
+// Portfolio the value of a wallet broken down by collection
+type Portfolio struct {
+	Valuation   string          `json:"valuation"`
+	Total       PortfolioPart   `json:"total"`
+	Collections []PortfolioPart `json:"collections"` // highest value first
+}
+
+func newPortfolioPart(collectionId, collectionName string) *PortfolioPart {
+	return &PortfolioPart{
+		CollectionId:   collectionId,
+		CollectionName: collectionName,
+		StatusCounts:   map[string]int64{"onHold": 0, "onSale": 0, "onAuction": 0},
+		value:          new(big.Int),
+	}
+}
+
+func (p *PortfolioPart) add(asset Asset, value *big.Int) {
+	p.Count++
+	if asset.Status != "" {
+		p.StatusCounts[asset.Status]++
+	}
+	if value != nil {
+		p.ValuedCount++
+		p.value.Add(p.value, value)
+	}
+}
+
+// FindWalletPortfolio value the assets held by user with valuation
+func FindWalletPortfolio(network, user, valuation string) (*Portfolio, error) {
+	var assets = make([]Asset, 0)
+	db := database.GetMongoClient()
+	cursor, err := db.Collection("assets").Find(context.TODO(),
+		withChain(bson.M{"userMetamaskId": user, "isDelete": 0}, network),
+		options.Find().SetProjection(bson.M{"_id": 0, "id": 1, "chain": 1, "contractAddress": 1, "collectibleTokenId": 1,
+			"collectionId": 1, "collectionName": 1, "status": 1, "price": 1, "numOfCopies": 1, "ownershipCheckTime": 1,
+			"sellOrders.payTokenContract": 1}))
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	if err = cursor.All(context.TODO(), &assets); err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+
+	var (
+		floors    map[string]*big.Int
+		lastSales map[string]*big.Int
+	)
+	switch valuation {
+	case VALUATION_FLOOR:
+		var collectionIds []string
+		seen := make(map[string]bool)
+		for _, v := range assets {
+			if !seen[v.CollectionID] {
+				seen[v.CollectionID] = true
+				collectionIds = append(collectionIds, v.CollectionID)
+			}
+		}
+		if floors, err = FindCollectionFloorPrices(network, collectionIds); err != nil {
+			return nil, err
+		}
+	case VALUATION_LAST_SALE:
+		if lastSales, err = findLastSaleUnitPrices(network, assets); err != nil {
+			return nil, err
+		}
+	}
+
+	portfolio := &Portfolio{Valuation: valuation, Collections: make([]PortfolioPart, 0)}
+	total := newPortfolioPart("", "")
+	parts := make(map[string]*PortfolioPart)
+	for _, v := range assets {
+		// assets synced before copies were kept count as one, a corrected count of 0 is kept
+		copies := int64(v.NumOfCopies)
+		if copies < 1 && v.OwnershipCheckTime == 0 {
+			copies = 1
+		}
+		var value *big.Int
+		switch valuation {
+		case VALUATION_FLOOR:
+			if floor, ok := floors[v.CollectionID]; ok {
+				value = new(big.Int).Mul(floor, big.NewInt(copies))
+			}
+		case VALUATION_LAST_SALE:
+			if price, ok := lastSales[v.ContractAddress+"/"+v.CollectibleTokenId]; ok {
+				value = new(big.Int).Mul(price, big.NewInt(copies))
+			}
+		case VALUATION_LISTING:
+			payToken := v.SellOrders.PayTokenContract
+			if price, ok := TokenEthWei(v.Price, payToken.TokenDecimals(), payToken.Symbol, payToken.EthPrice); ok && v.Status != "onHold" {
+				value = price
+			}
+		}
+
+		part, ok := parts[v.CollectionID]
+		if !ok {
+			part = newPortfolioPart(v.CollectionID, v.CollectionName)
+			parts[v.CollectionID] = part
+		}
+		part.add(v, value)
+		total.add(v, value)
+	}
+
+	for _, part := range parts {
+		part.Value = part.value.String()
+		portfolio.Collections = append(portfolio.Collections, *part)
+	}
+	sort.Slice(portfolio.Collections, func(i, j int) bool {
+		if c := portfolio.Collections[i].value.Cmp(portfolio.Collections[j].value); c != 0 {
+			return c > 0
+		}
+		return portfolio.Collections[i].CollectionId < portfolio.Collections[j].CollectionId
+	})
+	total.Value = total.value.String()
+	portfolio.Total = *total
+	return portfolio, nil
+}
+
+// findLastSaleUnitPrices price of one copy at the last successful sale of each asset in ETH wei, converted at the
+// rate of its payment token at the trade, or at the last synced rate when that is unknown, keyed by contract/tokenId
+func findLastSaleUnitPrices(network string, assets []Asset) (map[string]*big.Int, error) {
+	result := make(map[string]*big.Int)
+	if len(assets) == 0 {
+		return result, nil
+	}
+	tokens := make(bson.A, 0, len(assets))
+	for _, v := range assets {
+		tokens = append(tokens, bson.M{"contractAddress": v.ContractAddress, "tokenId": v.CollectibleTokenId})
+	}
+
+	db := database.GetMongoClient()
+	pipe := mongo.Pipeline{
+		{{Key: "$match", Value: withoutSuspicious(withChain(bson.M{"tradeType": "successful", "isDelete": 0, "$or": tokens}, network))}},
+		{{Key: "$sort", Value: bson.M{"createDate": -1}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":      bson.M{"contractAddress": "$contractAddress", "tokenId": "$tokenId"},
+			"price":    bson.M{"$first": "$price"},
+			"quantity": bson.M{"$first": "$quantity"},
+			"payToken": bson.M{"$first": "$payTokenContract"},
+			"ethPrice": bson.M{"$first": "$tradeEthPrice"},
+		}}},
+	}
+	cursor, err := db.Collection("item_activitys").Aggregate(context.TODO(), pipe)
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	var sales []struct {
+		Id struct {
+			ContractAddress string `bson:"contractAddress"`
+			TokenId         string `bson:"tokenId"`
+		} `bson:"_id"`
+		Price    string           `bson:"price"`
+		Quantity string           `bson:"quantity"`
+		PayToken PayTokenContract `bson:"payToken"`
+		EthPrice string           `bson:"ethPrice"`
+	}
+	if err = cursor.All(context.TODO(), &sales); err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	for _, v := range sales {
+		ethPrice := v.EthPrice
+		if ethPrice == "" {
+			ethPrice = v.PayToken.EthPrice
+		}
+		price, ok := TokenEthWei(v.Price, v.PayToken.TokenDecimals(), v.PayToken.Symbol, ethPrice)
+		if !ok {
+			continue
+		}
+		if quantity, ok := new(big.Int).SetString(v.Quantity, 10); ok && quantity.Sign() > 0 {
+			price.Div(price, quantity)
+		}
+		result[v.Id.ContractAddress+"/"+v.Id.TokenId] = price
+	}
+	return result, nil
+}
@@ -0,0 +1,247 @@
+package models
+
+import (
+	"context"
+	"math/big"
+	"openseasync/database"
+	"openseasync/logs"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	RANKING_METRIC_VOLUME       = "volume"
+	RANKING_METRIC_SALES        = "sales"
+	RANKING_METRIC_FLOOR_CHANGE = "floor_change"
+)
+
+// RankingWindows windows rankings are computed over
+var RankingWindows = map[string]time.Duration{
+	"1d":  24 * time.Hour,
+	"7d":  7 * 24 * time.Hour,
+	"30d": 30 * 24 * time.Hour,
+}
+
+// rankingSortFields field each metric is ranked by
+var rankingSortFields = map[string]string{
+	RANKING_METRIC_VOLUME:       "volumeEth",
+	RANKING_METRIC_SALES:        "sales",
+	RANKING_METRIC_FLOOR_CHANGE: "floorChange",
+}
+
+// CollectionRanking the figures of a collection over a window, cached in collection_rankings
+type CollectionRanking struct {
+	Chain          string  `json:"chain" bson:"chain"`                   // 所在链
+	Window         string  `json:"window" bson:"window"`                 // 统计窗口 1d 7d 30d
+	CollectionId   string  `json:"collectionId" bson:"collectionId"`     // 集合ID
+	CollectionName string  `json:"collectionName" bson:"collectionName"` // 集合名
+	Volume         string  `json:"volume" bson:"volume"`                 // 成交额 ETH wei, 各支付代币按成交时ETH价格换算
+	VolumeEth      float64 `json:"volumeEth" bson:"volumeEth"`           // 成交额 ETH, 用于排序
+	Sales          int64   `json:"sales" bson:"sales"`                   // 成交笔数
+	FloorPrice     string  `json:"floorPrice" bson:"floorPrice"`         // 当前最低价格 wei
+	FloorChange    float64 `json:"floorChange" bson:"floorChange"`       // 窗口内最低价格变化百分比
+	UpdateTime     int64   `json:"updateTime" bson:"updateTime"`         // 计算时间
+}
+
+// RefreshCollectionRankings recompute the rankings of every synced collection over window
+func RefreshCollectionRankings(ctx context.Context, window string) error {
+	now := time.Now()
+	since := now.Add(-RankingWindows[window]).UnixMilli()
+	db := database.GetMongoClient()
+
+	rankings := make(map[string]*CollectionRanking)
+	ranking := func(network, collectionId string) *CollectionRanking {
+		key := network + "/" + collectionId
+		if _, ok := rankings[key]; !ok {
+			rankings[key] = &CollectionRanking{Chain: network, Window: window, CollectionId: collectionId, Volume: "0", UpdateTime: now.UnixMilli()}
+		}
+		return rankings[key]
+	}
+
+	// every synced collection with its current floor, the lowest of the copies kept per wallet
+	cursor, err := db.Collection("collections").Find(context.TODO(), bson.M{"isDelete": 0},
+		options.Find().SetProjection(bson.M{"_id": 0, "chain": 1, "id": 1, "collectionName": 1, "floorPrice": 1}))
+	if err != nil {
+		logs.FromContext(ctx).Error(err)
+		return err
+	}
+	var collections []Collection
+	if err = cursor.All(context.TODO(), &collections); err != nil {
+		logs.FromContext(ctx).Error(err)
+		return err
+	}
+	floors := make(map[string]*big.Int)
+	for _, v := range collections {
+		r := ranking(v.Chain, v.ID)
+		r.CollectionName = v.CollectionName
+		floor, ok := new(big.Int).SetString(v.FloorPrice, 10)
+		if !ok || floor.Sign() <= 0 {
+			continue
+		}
+		if current, exists := floors[v.Chain+"/"+v.ID]; !exists || floor.Cmp(current) < 0 {
+			floors[v.Chain+"/"+v.ID] = floor
+			r.FloorPrice = floor.String()
+		}
+	}
+
+	// volume and number of sales, each sale in ETH wei, its price scaled from the decimals of its token and converted at the token's rate at the
+	// trade, or at the last synced rate when that is unknown, ETH and WETH and tokens without a symbol being worth one
+	zero := primitive.NewDecimal128(0, 0)
+	toDecimal := func(input interface{}, onMissing interface{}) bson.M {
+		return bson.M{"$convert": bson.M{"input": input, "to": "decimal", "onError": onMissing, "onNull": onMissing}}
+	}
+	saleEthWei := bson.M{"$multiply": bson.A{
+		toDecimal("$price", zero),
+		bson.M{"$cond": bson.A{
+			bson.M{"$in": bson.A{bson.M{"$toUpper": bson.M{"$ifNull": bson.A{"$payTokenContract.symbol", ""}}}, bson.A{"", "ETH", "WETH"}}},
+			1,
+			toDecimal(bson.M{"$cond": bson.A{
+				bson.M{"$gt": bson.A{bson.M{"$ifNull": bson.A{"$tradeEthPrice", ""}}, ""}}, "$tradeEthPrice", "$payTokenContract.ethPrice"}}, zero),
+		}},
+		bson.M{"$pow": bson.A{toDecimal(10, zero), bson.M{"$subtract": bson.A{defaultTokenDecimals, bson.M{"$ifNull": bson.A{
+			"$payTokenContract.decimals", defaultTokenDecimals}}}}}},
+	}}
+	salesPipe := mongo.Pipeline{
+		{{Key: "$match", Value: withoutSuspicious(bson.M{"tradeType": "successful", "isDelete": 0, "createDate": bson.M{"$gte": since}})}},
+		{{Key: "$group", Value: bson.M{
+			"_id":            bson.M{"chain": "$chain", "collectionId": "$collectionId"},
+			"collectionName": bson.M{"$last": "$collectionName"},
+			"volume":         bson.M{"$sum": saleEthWei},
+			"sales":          bson.M{"$sum": 1},
+		}}},
+	}
+	cursor, err = db.Collection("item_activitys").Aggregate(context.TODO(), salesPipe)
+	if err != nil {
+		logs.FromContext(ctx).Error(err)
+		return err
+	}
+	var sales []struct {
+		Id struct {
+			Chain        string `bson:"chain"`
+			CollectionId string `bson:"collectionId"`
+		} `bson:"_id"`
+		CollectionName string               `bson:"collectionName"`
+		Volume         primitive.Decimal128 `bson:"volume"`
+		Sales          int64                `bson:"sales"`
+	}
+	if err = cursor.All(context.TODO(), &sales); err != nil {
+		logs.FromContext(ctx).Error(err)
+		return err
+	}
+	for _, v := range sales {
+		r := ranking(v.Id.Chain, v.Id.CollectionId)
+		if r.CollectionName == "" {
+			r.CollectionName = v.CollectionName
+		}
+		r.Sales = v.Sales
+		if volume := decimalToInt(v.Volume); volume != nil {
+			r.Volume = volume.String()
+			r.VolumeEth, _ = new(big.Float).Quo(new(big.Float).SetInt(volume), big.NewFloat(1e18)).Float64()
+		}
+	}
+
+	// floor at the start of the window, the old value of the first floor change inside it
+	changesPipe := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"kind": CHANGE_KIND_COLLECTION, "field": "floorPrice", "createDate": bson.M{"$gte": since}}}},
+		{{Key: "$sort", Value: bson.M{"createDate": 1}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":      bson.M{"chain": "$chain", "collectionId": "$collectionId"},
+			"oldValue": bson.M{"$first": "$oldValue"},
+		}}},
+	}
+	cursor, err = db.Collection("asset_changes").Aggregate(context.TODO(), changesPipe)
+	if err != nil {
+		logs.FromContext(ctx).Error(err)
+		return err
+	}
+	var changes []struct {
+		Id struct {
+			Chain        string `bson:"chain"`
+			CollectionId string `bson:"collectionId"`
+		} `bson:"_id"`
+		OldValue string `bson:"oldValue"`
+	}
+	if err = cursor.All(context.TODO(), &changes); err != nil {
+		logs.FromContext(ctx).Error(err)
+		return err
+	}
+	for _, v := range changes {
+		key := v.Id.Chain + "/" + v.Id.CollectionId
+		start, ok := new(big.Int).SetString(v.OldValue, 10)
+		current, exists := floors[key]
+		if !ok || start.Sign() <= 0 || !exists {
+			continue
+		}
+		change := new(big.Float).Quo(new(big.Float).SetInt(new(big.Int).Sub(current, start)), new(big.Float).SetInt(start))
+		percent, _ := change.Float64()
+		ranking(v.Id.Chain, v.Id.CollectionId).FloorChange = percent * 100
+	}
+
+	for _, v := range rankings {
+		if _, err := db.Collection("collection_rankings").ReplaceOne(context.TODO(),
+			bson.M{"chain": v.Chain, "window": window, "collectionId": v.CollectionId}, v, options.Replace().SetUpsert(true)); err != nil {
+			logs.FromContext(ctx).Error(err)
+			return err
+		}
+	}
+	// collections no longer synced
+	if _, err := db.Collection("collection_rankings").DeleteMany(context.TODO(),
+		bson.M{"window": window, "updateTime": bson.M{"$lt": now.UnixMilli()}}); err != nil {
+		logs.FromContext(ctx).Error(err)
+		return err
+	}
+	return nil
+}
+
+// FindCollectionRankings page through the cached rankings of window by metric, highest first
+func FindCollectionRankings(network, metric, window string, page, pageSize int64) (map[string]interface{}, error) {
+	var (
+		rankings = make([]CollectionRanking, 0)
+		result   = make(map[string]interface{})
+	)
+	db := database.GetMongoClient()
+	filter := withChain(bson.M{"window": window}, network)
+	total, err := db.Collection("collection_rankings").CountDocuments(context.TODO(), filter)
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	totalPage := total / pageSize
+	if total%pageSize != 0 {
+		totalPage++
+	}
+
+	cursor, err := db.Collection("collection_rankings").Find(context.TODO(), filter,
+		options.Find().SetSort(bson.D{{Key: rankingSortFields[metric], Value: -1}, {Key: "collectionId", Value: 1}}).
+			SetSkip((page-1)*pageSize).SetLimit(pageSize).SetProjection(bson.M{"_id": 0}))
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	if err = cursor.All(context.TODO(), &rankings); err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+
+	result["data"] = rankings
+	result["metadata"] = map[string]int64{"page": page, "pageSize": pageSize, "total": total, "totalPage": totalPage}
+	return result, nil
+}
+
+// decimalToInt the integer value of d, nil when it is not a finite number
+func decimalToInt(d primitive.Decimal128) *big.Int {
+	value, exp, err := d.BigInt()
+	if err != nil {
+		return nil
+	}
+	if exp > 0 {
+		value.Mul(value, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exp)), nil))
+	} else if exp < 0 {
+		value.Quo(value, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-exp)), nil))
+	}
+	return value
+}
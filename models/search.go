@@ -0,0 +1,179 @@
+package models
+
+import (
+	"context"
+	"openseasync/database"
+	"openseasync/logs"
+	"regexp"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	SEARCH_TYPE_ASSET      = "asset"
+	SEARCH_TYPE_COLLECTION = "collection"
+	SEARCH_TYPE_USER       = "user"
+)
+
+// textIndexes the text index of each searchable collection, mongo allows one per collection
+var textIndexes = map[string]bson.D{
+	"assets":      {{Key: "collectibleName", Value: "text"}, {Key: "description", Value: "text"}},
+	"collections": {{Key: "collectionName", Value: "text"}, {Key: "description", Value: "text"}},
+	"users":       {{Key: "userName", Value: "text"}},
+}
+
+// textIndexWeights names weigh more than descriptions
+var textIndexWeights = map[string]bson.M{
+	"assets":      {"collectibleName": 10, "description": 1},
+	"collections": {"collectionName": 10, "description": 1},
+	"users":       {"userName": 1},
+}
+
+// EnsureSearchIndexes create the text indexes search relies on, a conflicting text index is left in place
+func EnsureSearchIndexes() error {
+	var result error
+	db := database.GetMongoClient()
+	for name, keys := range textIndexes {
+		model := mongo.IndexModel{
+			Keys:    keys,
+			Options: options.Index().SetName(name + "_text").SetWeights(textIndexWeights[name]).SetDefaultLanguage("none"),
+		}
+		if _, err := db.Collection(name).Indexes().CreateOne(context.TODO(), model); err != nil {
+			logs.GetLogger().Error(err)
+			result = err
+		}
+	}
+	return result
+}
+
+// SearchResult the best matches of each entity type, best first
+type SearchResult struct {
+	Assets      []SearchAsset      `json:"assets"`
+	Collections []SearchCollection `json:"collections"`
+	Users       []SearchUser       `json:"users"`
+}
+
+type SearchAsset struct {
+	Id                 int     `json:"id" bson:"id"`
+	Chain              string  `json:"chain" bson:"chain"`
+	ContractAddress    string  `json:"contractAddress" bson:"contractAddress"`
+	CollectibleTokenId string  `json:"collectibleTokenId" bson:"collectibleTokenId"`
+	CollectibleName    string  `json:"collectibleName" bson:"collectibleName"`
+	CoverImageUrl      string  `json:"coverImageUrl" bson:"coverImageUrl"`
+	CollectionId       string  `json:"collectionId" bson:"collectionId"`
+	CollectionName     string  `json:"collectionName" bson:"collectionName"`
+	Score              float64 `json:"score" bson:"score"` // 相关度
+}
+
+type SearchCollection struct {
+	Id             string  `json:"id" bson:"id"`
+	Chain          string  `json:"chain" bson:"chain"`
+	CollectionName string  `json:"collectionName" bson:"collectionName"`
+	CoverImageUrl  string  `json:"coverImageUrl" bson:"coverImageUrl"`
+	Score          float64 `json:"score" bson:"score"` // 相关度
+}
+
+type SearchUser struct {
+	UserMetamaskId string  `json:"userMetamaskId" bson:"userMetamaskId"`
+	UserName       string  `json:"userName" bson:"userName"`
+	AvatarUrl      string  `json:"avatarUrl" bson:"avatarUrl"`
+	Score          float64 `json:"score" bson:"score"` // 相关度
+}
+
+// EscapeRegex quote user input used inside a regex so it only ever matches itself
+func EscapeRegex(value string) string {
+	return regexp.QuoteMeta(value)
+}
+
+// Search look q up in asset, collection and user names and descriptions, limit results per type
+func Search(network, q string, types map[string]bool, limit int64) (*SearchResult, error) {
+	result := &SearchResult{Assets: make([]SearchAsset, 0), Collections: make([]SearchCollection, 0), Users: make([]SearchUser, 0)}
+	db := database.GetMongoClient()
+	search := bson.M{"$search": q}
+	score := bson.M{"$meta": "textScore"}
+
+	if types[SEARCH_TYPE_ASSET] {
+		// every synced wallet keeps its own copy of an asset, keep one per token
+		pipe := mongo.Pipeline{
+			{{Key: "$match", Value: withChain(bson.M{"$text": search, "isDelete": 0}, network)}},
+			{{Key: "$addFields", Value: bson.M{"score": score}}},
+			{{Key: "$sort", Value: bson.M{"score": -1}}},
+			{{Key: "$group", Value: bson.M{
+				"_id":                bson.M{"chain": "$chain", "contractAddress": "$contractAddress", "collectibleTokenId": "$collectibleTokenId"},
+				"id":                 bson.M{"$first": "$id"},
+				"chain":              bson.M{"$first": "$chain"},
+				"contractAddress":    bson.M{"$first": "$contractAddress"},
+				"collectibleTokenId": bson.M{"$first": "$collectibleTokenId"},
+				"collectibleName":    bson.M{"$first": "$collectibleName"},
+				"coverImageUrl":      bson.M{"$first": "$coverImageUrl"},
+				"collectionId":       bson.M{"$first": "$collectionId"},
+				"collectionName":     bson.M{"$first": "$collectionName"},
+				"score":              bson.M{"$max": "$score"},
+			}}},
+			{{Key: "$sort", Value: bson.D{{Key: "score", Value: -1}, {Key: "id", Value: 1}}}},
+			{{Key: "$limit", Value: limit}},
+			{{Key: "$project", Value: bson.M{"_id": 0}}},
+		}
+		if err := aggregateInto(db.Collection("assets"), pipe, &result.Assets); err != nil {
+			return nil, err
+		}
+	}
+
+	if types[SEARCH_TYPE_COLLECTION] {
+		pipe := mongo.Pipeline{
+			{{Key: "$match", Value: withChain(bson.M{"$text": search, "isDelete": 0}, network)}},
+			{{Key: "$addFields", Value: bson.M{"score": score}}},
+			{{Key: "$sort", Value: bson.M{"score": -1}}},
+			{{Key: "$group", Value: bson.M{
+				"_id":            bson.M{"chain": "$chain", "id": "$id"},
+				"id":             bson.M{"$first": "$id"},
+				"chain":          bson.M{"$first": "$chain"},
+				"collectionName": bson.M{"$first": "$collectionName"},
+				"coverImageUrl":  bson.M{"$first": "$coverImageUrl "},
+				"score":          bson.M{"$max": "$score"},
+			}}},
+			{{Key: "$sort", Value: bson.D{{Key: "score", Value: -1}, {Key: "id", Value: 1}}}},
+			{{Key: "$limit", Value: limit}},
+			{{Key: "$project", Value: bson.M{"_id": 0}}},
+		}
+		if err := aggregateInto(db.Collection("collections"), pipe, &result.Collections); err != nil {
+			return nil, err
+		}
+	}
+
+	if types[SEARCH_TYPE_USER] {
+		filter, userScore := bson.M{"$text": search}, interface{}(score)
+		// an address prefix is matched on the address itself, anchored and escaped
+		if prefix := strings.ToLower(q); strings.HasPrefix(prefix, "0x") {
+			filter, userScore = bson.M{"userMetamaskId": primitive.Regex{Pattern: "^" + EscapeRegex(prefix)}}, 0
+		}
+		pipe := mongo.Pipeline{
+			{{Key: "$match", Value: filter}},
+			{{Key: "$addFields", Value: bson.M{"score": userScore}}},
+			{{Key: "$sort", Value: bson.D{{Key: "score", Value: -1}, {Key: "userMetamaskId", Value: 1}}}},
+			{{Key: "$limit", Value: limit}},
+			{{Key: "$project", Value: bson.M{"_id": 0, "userMetamaskId": 1, "userName": 1, "avatarUrl": 1, "score": 1}}},
+		}
+		if err := aggregateInto(db.Collection("users"), pipe, &result.Users); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func aggregateInto(collection *mongo.Collection, pipe mongo.Pipeline, results interface{}) error {
+	cursor, err := collection.Aggregate(context.TODO(), pipe)
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return err
+	}
+	if err = cursor.All(context.TODO(), results); err != nil {
+		logs.GetLogger().Error(err)
+		return err
+	}
+	return nil
+}
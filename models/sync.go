@@ -0,0 +1,248 @@
+package models
+
+import (
+	"context"
+	"openseasync/database"
+	"openseasync/logs"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	SYNC_STATUS_RUNNING   = "running"
+	SYNC_STATUS_SUCCESS   = "success"
+	SYNC_STATUS_FAILED    = "failed"
+	SYNC_STATUS_CANCELLED = "cancelled"
+
+	SYNC_TRIGGER_API   = "api"   // the public sync route
+	SYNC_TRIGGER_ADMIN = "admin" // forced through the admin api
+	SYNC_TRIGGER_CLI   = "cli"   // the command line
+)
+
+// SyncRun one sync of the collections and assets of a wallet
+type SyncRun struct {
+	Id             string `json:"id" bson:"id"`
+	Chain          string `json:"chain" bson:"chain"`                   // 所在链
+	UserMetamaskId string `json:"userMetamaskId" bson:"userMetamaskId"` // 同步的钱包地址
+	Trigger        string `json:"trigger" bson:"trigger"`               // api admin cli
+	Status         string `json:"status" bson:"status"`                 // running success failed cancelled
+	Error          string `json:"error" bson:"error"`                   // 失败原因
+	StartTime      int64  `json:"startTime" bson:"startTime"`           // 开始时间
+	EndTime        int64  `json:"endTime" bson:"endTime"`               // 结束时间
+}
+
+// WalletSync a synced wallet, when its data was last refreshed and its latest run
+type WalletSync struct {
+	Chain          string   `json:"chain" bson:"chain"`
+	UserMetamaskId string   `json:"userMetamaskId" bson:"userMetamaskId"`
+	RefreshTime    int64    `json:"refreshTime" bson:"refreshTime"` // 最后刷新时间
+	LastRun        *SyncRun `json:"lastRun" bson:"-"`               // 没有记录时为空
+}
+
+// InsertSyncRun save a run being started
+func InsertSyncRun(run *SyncRun) error {
+	db := database.GetMongoClient()
+	if _, err := db.Collection("sync_runs").InsertOne(context.TODO(), run); err != nil {
+		logs.GetLogger().Error(err)
+		return err
+	}
+	return nil
+}
+
+// FinishSyncRun record how a run ended
+func FinishSyncRun(id, status, reason string) error {
+	db := database.GetMongoClient()
+	if _, err := db.Collection("sync_runs").UpdateOne(context.TODO(), bson.M{"id": id},
+		bson.M{"$set": bson.M{"status": status, "error": reason, "endTime": time.Now().UnixMilli()}}); err != nil {
+		logs.GetLogger().Error(err)
+		return err
+	}
+	return nil
+}
+
+// AbortSyncRuns fail the runs still marked running, they were cut short by a restart
+func AbortSyncRuns() error {
+	db := database.GetMongoClient()
+	if _, err := db.Collection("sync_runs").UpdateMany(context.TODO(), bson.M{"status": SYNC_STATUS_RUNNING},
+		bson.M{"$set": bson.M{"status": SYNC_STATUS_FAILED, "error": "interrupted by a restart", "endTime": time.Now().UnixMilli()}}); err != nil {
+		logs.GetLogger().Error(err)
+		return err
+	}
+	return nil
+}
+
+// FindSyncErrors the failed runs, most recent first
+func FindSyncErrors(network string, page, pageSize int64) (map[string]interface{}, error) {
+	var (
+		runs   = make([]SyncRun, 0)
+		result = make(map[string]interface{})
+	)
+	db := database.GetMongoClient()
+	filter := withChain(bson.M{"status": SYNC_STATUS_FAILED}, network)
+	total, err := db.Collection("sync_runs").CountDocuments(context.TODO(), filter)
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	totalPage := total / pageSize
+	if total%pageSize != 0 {
+		totalPage++
+	}
+
+	cursor, err := db.Collection("sync_runs").Find(context.TODO(), filter,
+		options.Find().SetSort(bson.M{"endTime": -1}).SetSkip((page-1)*pageSize).SetLimit(pageSize))
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	if err = cursor.All(context.TODO(), &runs); err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	result["data"] = runs
+	result["metadata"] = map[string]int64{"page": page, "pageSize": pageSize, "total": total, "totalPage": totalPage}
+	return result, nil
+}
+
+// FindWalletSyncs the wallets with synced assets, least recently refreshed first, with their latest run
+func FindWalletSyncs(network string, page, pageSize int64) (map[string]interface{}, error) {
+	var (
+		wallets = make([]WalletSync, 0)
+		result  = make(map[string]interface{})
+	)
+	db := database.GetMongoClient()
+	group := mongo.Pipeline{
+		{{Key: "$match", Value: withChain(bson.M{}, network)}},
+		{{Key: "$group", Value: bson.M{
+			"_id":         bson.M{"chain": "$chain", "userMetamaskId": "$userMetamaskId"},
+			"refreshTime": bson.M{"$max": "$refreshTime"},
+		}}},
+	}
+
+	var counted []struct {
+		Total int64 `bson:"total"`
+	}
+	cursor, err := db.Collection("assets").Aggregate(context.TODO(), append(group, bson.D{{Key: "$count", Value: "total"}}))
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	if err = cursor.All(context.TODO(), &counted); err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	var total int64
+	if len(counted) > 0 {
+		total = counted[0].Total
+	}
+	totalPage := total / pageSize
+	if total%pageSize != 0 {
+		totalPage++
+	}
+
+	cursor, err = db.Collection("assets").Aggregate(context.TODO(), append(group,
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "refreshTime", Value: 1}, {Key: "_id.userMetamaskId", Value: 1}}}},
+		bson.D{{Key: "$skip", Value: (page - 1) * pageSize}},
+		bson.D{{Key: "$limit", Value: pageSize}},
+		bson.D{{Key: "$project", Value: bson.M{"_id": 0, "chain": "$_id.chain", "userMetamaskId": "$_id.userMetamaskId", "refreshTime": 1}}},
+	))
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	if err = cursor.All(context.TODO(), &wallets); err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+
+	users := make([]string, len(wallets))
+	for i, v := range wallets {
+		users[i] = v.UserMetamaskId
+	}
+	latest, err := findLatestSyncRuns(users)
+	if err != nil {
+		return nil, err
+	}
+	for i, v := range wallets {
+		wallets[i].LastRun = latest[v.Chain+"|"+v.UserMetamaskId]
+	}
+
+	result["data"] = wallets
+	result["metadata"] = map[string]int64{"page": page, "pageSize": pageSize, "total": total, "totalPage": totalPage}
+	return result, nil
+}
+
+// findLatestSyncRuns the latest run of each chain and wallet of users, keyed by chain|wallet
+func findLatestSyncRuns(users []string) (map[string]*SyncRun, error) {
+	result := make(map[string]*SyncRun)
+	if len(users) == 0 {
+		return result, nil
+	}
+	db := database.GetMongoClient()
+	cursor, err := db.Collection("sync_runs").Aggregate(context.TODO(), mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"userMetamaskId": bson.M{"$in": users}}}},
+		{{Key: "$sort", Value: bson.M{"startTime": -1}}},
+		{{Key: "$group", Value: bson.M{"_id": bson.M{"chain": "$chain", "userMetamaskId": "$userMetamaskId"}, "run": bson.M{"$first": "$$ROOT"}}}},
+		{{Key: "$replaceRoot", Value: bson.M{"newRoot": "$run"}}},
+	})
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	var runs []SyncRun
+	if err = cursor.All(context.TODO(), &runs); err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	for i := range runs {
+		result[runs[i].Chain+"|"+runs[i].UserMetamaskId] = &runs[i]
+	}
+	return result, nil
+}
+
+// FindCollectionHolders the wallets holding assets of a collection, or owning it
+func FindCollectionHolders(network, collectionId string) ([]string, error) {
+	db := database.GetMongoClient()
+	owners, err := db.Collection("collections").Distinct(context.TODO(), "userMetamaskId",
+		withChain(bson.M{"id": collectionId, "isDelete": 0}, network))
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	holders, err := db.Collection("assets").Distinct(context.TODO(), "userMetamaskId",
+		withChain(bson.M{"collectionId": collectionId, "isDelete": 0}, network))
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	return distinctWallets(append(owners, holders...)), nil
+}
+
+// FindAssetHolders the wallets holding an asset
+func FindAssetHolders(network, contractAddress, tokenId string) ([]string, error) {
+	db := database.GetMongoClient()
+	holders, err := db.Collection("assets").Distinct(context.TODO(), "userMetamaskId",
+		withChain(bson.M{"contractAddress": contractAddress, "collectibleTokenId": tokenId, "isDelete": 0}, network))
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	return distinctWallets(holders), nil
+}
+
+func distinctWallets(values []interface{}) []string {
+	var (
+		wallets = make([]string, 0, len(values))
+		seen    = make(map[string]bool)
+	)
+	for _, v := range values {
+		if wallet, ok := v.(string); ok && wallet != "" && !seen[wallet] {
+			seen[wallet] = true
+			wallets = append(wallets, wallet)
+		}
+	}
+	return wallets
+}
@@ -0,0 +1,164 @@
+package models
+
+import (
+	"context"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// tradePriceWindow rates of a payment token observed this close to a trade are its rates at the trade,
+	// rates observed further away value it approximately
+	tradePriceWindow = time.Hour
+	// defaultTokenDecimals decimals of ETH and WETH, assumed for tokens synced before their decimals were kept
+	defaultTokenDecimals = 18
+)
+
+// TokenPrice rates of a payment token observed by a sync, one per token and hour in token_prices
+type TokenPrice struct {
+	Chain       string `json:"chain" bson:"chain"`             // 所在链
+	Symbol      string `json:"symbol" bson:"symbol"`           // 代币符号
+	EthPrice    string `json:"ethPrice" bson:"ethPrice"`       // ETH 价格
+	UsdPrice    string `json:"usdPrice" bson:"usdPrice"`       // USD 价格
+	Hour        int64  `json:"hour" bson:"hour"`               // 观察时间所在小时
+	ObserveTime int64  `json:"observeTime" bson:"observeTime"` // 观察时间
+}
+
+// IsEthToken whether symbol is ETH or WETH, worth one ETH
+func IsEthToken(symbol string) bool {
+	symbol = strings.ToUpper(symbol)
+	return symbol == "ETH" || symbol == "WETH"
+}
+
+// TokenDecimals the decimals of the token, defaultTokenDecimals when they were not recorded
+func (p PayTokenContract) TokenDecimals() int {
+	if p.Decimals == nil {
+		return defaultTokenDecimals
+	}
+	return *p.Decimals
+}
+
+func tokenDecimals(decimals int) *int {
+	return &decimals
+}
+
+// TokenAmount amount in the smallest unit of a token with decimals as a number of tokens
+func TokenAmount(amount string, decimals int) (float64, bool) {
+	value, ok := new(big.Float).SetString(amount)
+	if !ok || decimals < 0 {
+		return 0, false
+	}
+	value.Quo(value, new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)))
+	tokens, _ := value.Float64()
+	return tokens, true
+}
+
+// TokenValue amount in the smallest unit of a token with decimals at rate per token, false when either is unknown
+func TokenValue(amount string, decimals int, rate string) (float64, bool) {
+	tokens, ok := TokenAmount(amount, decimals)
+	if !ok {
+		return 0, false
+	}
+	perToken, err := strconv.ParseFloat(rate, 64)
+	if err != nil {
+		return 0, false
+	}
+	return tokens * perToken, true
+}
+
+// TokenEthWei amount in the smallest unit of a token with decimals in wei of ETH at ethPrice per token,
+// ETH and WETH being worth one, false when the amount or the rate is unknown
+func TokenEthWei(amount string, decimals int, symbol, ethPrice string) (*big.Int, bool) {
+	if IsEthToken(symbol) || symbol == "" {
+		ethPrice = "1"
+	}
+	eth, ok := TokenValue(amount, decimals, ethPrice)
+	if !ok {
+		return nil, false
+	}
+	wei, _ := new(big.Float).Mul(big.NewFloat(eth), big.NewFloat(1e18)).Int(nil)
+	return wei, true
+}
+
+// recordTokenPrice keep the rates of token observed at now, the first observation of each hour is kept
+func recordTokenPrice(db *mongo.Database, network string, token PayTokenContract, now time.Time) error {
+	if token.Symbol == "" || (token.EthPrice == "" && token.UsdPrice == "") {
+		return nil
+	}
+	price := TokenPrice{Chain: network, Symbol: token.Symbol, EthPrice: token.EthPrice, UsdPrice: token.UsdPrice,
+		Hour: now.Truncate(time.Hour).UnixMilli(), ObserveTime: now.UnixMilli()}
+	_, err := db.Collection("token_prices").UpdateOne(context.TODO(),
+		bson.M{"chain": network, "symbol": price.Symbol, "hour": price.Hour},
+		bson.M{"$setOnInsert": price}, options.Update().SetUpsert(true))
+	return err
+}
+
+// tokenPriceAt the rates of symbol observed closest to at, nil when none was ever recorded
+func tokenPriceAt(db *mongo.Database, network, symbol string, at int64) (*TokenPrice, error) {
+	var closest *TokenPrice
+	for _, query := range []struct {
+		observed bson.M
+		sort     int
+	}{
+		{bson.M{"$lte": at}, -1},
+		{bson.M{"$gt": at}, 1},
+	} {
+		var price TokenPrice
+		err := db.Collection("token_prices").FindOne(context.TODO(),
+			bson.M{"chain": network, "symbol": symbol, "observeTime": query.observed},
+			options.FindOne().SetSort(bson.M{"observeTime": query.sort})).Decode(&price)
+		if err == mongo.ErrNoDocuments {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if closest == nil || abs(price.ObserveTime-at) < abs(closest.ObserveTime-at) {
+			closest = &price
+		}
+	}
+	return closest, nil
+}
+
+// setTradePrices value activity at the rates of its payment token observed closest to the trade. Rates are only
+// recorded from the first sync on, so older trades are valued at the earliest ones, see TradeRatesApproximate.
+// They are left empty when the token's rates were never recorded.
+func setTradePrices(db *mongo.Database, network string, activity *ItemActivity) error {
+	activity.TradeEthPrice, activity.TradeUsdPrice, activity.PriceInUsd, activity.TradePriceTime = "", "", "", 0
+	symbol := activity.PayTokenContract.Symbol
+	if symbol == "" {
+		return nil
+	}
+	price, err := tokenPriceAt(db, network, symbol, activity.CreateDate)
+	if err != nil {
+		return err
+	}
+	if price != nil {
+		activity.TradeEthPrice, activity.TradeUsdPrice, activity.TradePriceTime = price.EthPrice, price.UsdPrice, price.ObserveTime
+	}
+	if IsEthToken(symbol) {
+		activity.TradeEthPrice = "1"
+	}
+	if usd, ok := TokenValue(activity.Price, activity.PayTokenContract.TokenDecimals(), activity.TradeUsdPrice); ok {
+		activity.PriceInUsd = strconv.FormatFloat(usd, 'f', 2, 64)
+	}
+	return nil
+}
+
+// TradeRatesApproximate whether the rates activity is valued at were observed further than tradePriceWindow from the trade
+func (a ItemActivity) TradeRatesApproximate() bool {
+	return a.TradePriceTime != 0 && abs(a.TradePriceTime-a.CreateDate) > tradePriceWindow.Milliseconds()
+}
+
+func abs(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
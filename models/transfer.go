@@ -0,0 +1,277 @@
+package models
+
+import (
+	"context"
+	"openseasync/chain"
+	"openseasync/database"
+	"openseasync/events"
+	"openseasync/logs"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	ACTIVITY_SOURCE_OPENSEA = "opensea"
+	ACTIVITY_SOURCE_CHAIN   = "chain"
+
+	TRADE_TYPE_TRANSFER = "transfer"
+
+	CHAIN_STATUS_PENDING   = "pending"
+	CHAIN_STATUS_CONFIRMED = "confirmed"
+	CHAIN_STATUS_REORGED   = "reorged"
+)
+
+// IndexerState last block indexed for a contract
+type IndexerState struct {
+	Chain           string `json:"chain" bson:"chain"`
+	ContractAddress string `json:"contractAddress" bson:"contractAddress"`
+	LastBlock       int64  `json:"lastBlock" bson:"lastBlock"`
+	LastBlockHash   string `json:"lastBlockHash" bson:"lastBlockHash"`
+	UpdateTime      int64  `json:"updateTime" bson:"updateTime"`
+}
+
+// FindIndexedContracts addresses of every contract of network known from synced assets
+func FindIndexedContracts(network string) ([]string, error) {
+	db := database.GetMongoClient()
+	values, err := db.Collection("contracts").Distinct(context.TODO(), "address", bson.M{"chain": network})
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	contracts := make([]string, 0, len(values))
+	for _, v := range values {
+		if address, ok := v.(string); ok && address != "" {
+			contracts = append(contracts, address)
+		}
+	}
+	return contracts, nil
+}
+
+// FindIndexerState get the indexer state of contract, nil when it was never indexed
+func FindIndexerState(network, contractAddress string) (*IndexerState, error) {
+	var state IndexerState
+	db := database.GetMongoClient()
+	err := db.Collection("indexer_states").FindOne(context.TODO(), bson.M{"chain": network, "contractAddress": contractAddress}).Decode(&state)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	return &state, nil
+}
+
+// UpdateIndexerState record lastBlock, whose hash is lastBlockHash, as indexed for contract
+func UpdateIndexerState(network, contractAddress string, lastBlock int64, lastBlockHash string) error {
+	db := database.GetMongoClient()
+	if _, err := db.Collection("indexer_states").UpdateOne(
+		context.TODO(),
+		bson.M{"chain": network, "contractAddress": contractAddress},
+		bson.M{"$set": bson.M{"lastBlock": lastBlock, "lastBlockHash": lastBlockHash, "updateTime": time.Now().UnixMilli()}},
+		options.Update().SetUpsert(true)); err != nil {
+		logs.GetLogger().Error(err)
+		return err
+	}
+	return nil
+}
+
+// InsertChainTransfers insert transfers read from the chain into item_activitys,
+// those with less than confirmations blocks on top of them up to head stay pending
+func InsertChainTransfers(ctx context.Context, network string, transfers []chain.Transfer, head uint64, confirmations uint64) error {
+	db := database.GetMongoClient()
+	for _, v := range transfers {
+		var asset Asset
+		err := db.Collection("assets").FindOne(
+			context.TODO(), bson.M{"chain": network, "contractAddress": v.ContractAddress, "collectibleTokenId": v.TokenId}).Decode(&asset)
+		if err != nil && err != mongo.ErrNoDocuments {
+			logs.FromContext(ctx).Error(err)
+			return err
+		}
+
+		var itemActivity = ItemActivity{
+			Chain:            network,
+			CollectibleId:    asset.Id,
+			CollectibleName:  asset.CollectibleName,
+			CollectionId:     asset.CollectionID,
+			CollectionName:   asset.CollectionName,
+			ContractAddress:  v.ContractAddress,
+			TokenId:          v.TokenId,
+			CreateDate:       v.BlockTime,
+			SellerMetamaskId: v.From,
+			BuyerMetamaskId:  v.To,
+			TradeType:        TRADE_TYPE_TRANSFER,
+			Quantity:         v.Quantity,
+			Source:           ACTIVITY_SOURCE_CHAIN,
+			LogIndex:         int(v.LogIndex),
+			BlockNumber:      int64(v.BlockNumber),
+			ChainStatus:      CHAIN_STATUS_CONFIRMED,
+		}
+		if chain.Confirmations(head, v.BlockNumber) < confirmations {
+			itemActivity.ChainStatus = CHAIN_STATUS_PENDING
+		}
+		itemActivity.Transaction.BlockHash = v.BlockHash
+		itemActivity.Transaction.BlockNumber = strconv.FormatUint(v.BlockNumber, 10)
+		itemActivity.Transaction.TransactionHash = v.TxHash
+		itemActivity.Transaction.TransactionIndex = strconv.FormatUint(uint64(v.TxIndex), 10)
+		itemActivity.Transaction.Timestamp = time.UnixMilli(v.BlockTime).UTC().Format("2006-01-02T15:04:05")
+		itemActivity.Transaction.FromAccount.Address = v.From
+		itemActivity.Transaction.ToAccount.Address = v.To
+
+		filter := bson.M{"chain": network, "transaction.transaction_hash": v.TxHash, "logIndex": itemActivity.LogIndex,
+			"contractAddress": v.ContractAddress, "tokenId": v.TokenId, "source": ACTIVITY_SOURCE_CHAIN}
+		count, err := db.Collection("item_activitys").CountDocuments(context.TODO(), filter)
+		if err != nil {
+			logs.FromContext(ctx).Error(err)
+			return err
+		}
+		if count == 0 {
+			if _, err = db.Collection("item_activitys").InsertOne(context.TODO(), &itemActivity); err != nil {
+				logs.FromContext(ctx).Error(err)
+				return err
+			}
+			events.Publish(ctx, events.Event{
+				Type:            events.EVENT_TYPE_ACTIVITY,
+				Chain:           network,
+				Wallets:         []string{v.From, v.To},
+				CollectionId:    itemActivity.CollectionId,
+				CollectibleId:   itemActivity.CollectibleId,
+				ContractAddress: v.ContractAddress,
+				TokenId:         v.TokenId,
+				Data:            itemActivity,
+			})
+		} else {
+			// update
+			itemActivityByte, err := bson.Marshal(itemActivity)
+			if err != nil {
+				logs.FromContext(ctx).Error(err)
+				return err
+			}
+			var tmpItemActivity bson.M
+			if err := bson.Unmarshal(itemActivityByte, &tmpItemActivity); err != nil {
+				logs.FromContext(ctx).Error(err)
+				return err
+			}
+			if _, err = db.Collection("item_activitys").UpdateOne(
+				context.TODO(), filter, bson.M{"$set": tmpItemActivity}); err != nil {
+				logs.FromContext(ctx).Error(err)
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ChainBlock a block some indexed activity was read from
+type ChainBlock struct {
+	Number int64  `bson:"_id"`
+	Hash   string `bson:"hash"`
+}
+
+// FindPendingChainBlocks blocks holding pending activity of contract, in ascending order
+func FindPendingChainBlocks(network, contractAddress string) ([]ChainBlock, error) {
+	var blocks = make([]ChainBlock, 0)
+	db := database.GetMongoClient()
+	pipe := mongo.Pipeline{
+		{{"$match", bson.M{"chain": network, "contractAddress": contractAddress, "source": ACTIVITY_SOURCE_CHAIN,
+			"chainStatus": CHAIN_STATUS_PENDING, "isDelete": 0}}},
+		{{"$group", bson.M{"_id": "$blockNumber", "hash": bson.M{"$first": "$transaction.block_hash"}}}},
+		{{"$sort", bson.M{"_id": 1}}},
+	}
+	cursor, err := db.Collection("item_activitys").Aggregate(context.TODO(), pipe)
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	if err = cursor.All(context.TODO(), &blocks); err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	return blocks, nil
+}
+
+// ConfirmChainActivities mark pending activity of contract up to block confirmed and derive ownership from it
+func ConfirmChainActivities(ctx context.Context, network, contractAddress string, upToBlock int64) error {
+	db := database.GetMongoClient()
+	filter := bson.M{"chain": network, "contractAddress": contractAddress, "source": ACTIVITY_SOURCE_CHAIN,
+		"chainStatus": CHAIN_STATUS_PENDING, "blockNumber": bson.M{"$lte": upToBlock}, "isDelete": 0}
+	tokenIds, err := db.Collection("item_activitys").Distinct(context.TODO(), "tokenId", filter)
+	if err != nil {
+		logs.FromContext(ctx).Error(err)
+		return err
+	}
+	if len(tokenIds) == 0 {
+		return nil
+	}
+	if _, err = db.Collection("item_activitys").UpdateMany(
+		context.TODO(), filter, bson.M{"$set": bson.M{"chainStatus": CHAIN_STATUS_CONFIRMED}}); err != nil {
+		logs.FromContext(ctx).Error(err)
+		return err
+	}
+	return refreshChainOwnership(ctx, db, network, contractAddress, tokenIds)
+}
+
+// RollbackChainActivities drop the activity of contract read from fromBlock onwards, which a reorg replaced,
+// and derive ownership again from what is left
+func RollbackChainActivities(ctx context.Context, network, contractAddress string, fromBlock int64) error {
+	db := database.GetMongoClient()
+	filter := bson.M{"chain": network, "contractAddress": contractAddress, "source": ACTIVITY_SOURCE_CHAIN,
+		"blockNumber": bson.M{"$gte": fromBlock}, "isDelete": 0}
+	tokenIds, err := db.Collection("item_activitys").Distinct(context.TODO(), "tokenId", filter)
+	if err != nil {
+		logs.FromContext(ctx).Error(err)
+		return err
+	}
+	if _, err = db.Collection("item_activitys").UpdateMany(
+		context.TODO(), filter, bson.M{"$set": bson.M{"chainStatus": CHAIN_STATUS_REORGED, "isDelete": 1, "deleteTime": time.Now().UnixMilli()}}); err != nil {
+		logs.FromContext(ctx).Error(err)
+		return err
+	}
+	logs.FromContext(ctx).Warnf("reorg: rolled back activity of %s from block %d on %d tokens", contractAddress, fromBlock, len(tokenIds))
+	return refreshChainOwnership(ctx, db, network, contractAddress, tokenIds)
+}
+
+// refreshChainOwnership set the on-chain owner of ERC721 tokens to the receiver of their latest confirmed transfer
+// and flag the assets whose synced owner differs, tokens left without confirmed transfers are no longer known
+// on chain
+func refreshChainOwnership(ctx context.Context, db *mongo.Database, network, contractAddress string, tokenIds []interface{}) error {
+	var contract Contract
+	err := db.Collection("contracts").FindOne(context.TODO(), bson.M{"chain": network, "address": contractAddress}).Decode(&contract)
+	if err != nil && err != mongo.ErrNoDocuments {
+		logs.FromContext(ctx).Error(err)
+		return err
+	}
+	// ERC1155 balances can't be derived from a single transfer
+	if strings.ToUpper(contract.SchemaName) != chain.SCHEMA_ERC721 {
+		return nil
+	}
+
+	opts := options.FindOne().SetSort(bson.D{{"blockNumber", -1}, {"logIndex", -1}})
+	for _, tokenId := range tokenIds {
+		var latest ItemActivity
+		err := db.Collection("item_activitys").FindOne(context.TODO(), bson.M{"chain": network, "contractAddress": contractAddress,
+			"tokenId": tokenId, "source": ACTIVITY_SOURCE_CHAIN, "chainStatus": CHAIN_STATUS_CONFIRMED, "isDelete": 0}, opts).Decode(&latest)
+		if err != nil && err != mongo.ErrNoDocuments {
+			logs.FromContext(ctx).Error(err)
+			return err
+		}
+		ownership := bson.M{"onChainOwner": "", "ownershipMismatch": false}
+		if err == nil {
+			ownership = bson.M{"onChainOwner": latest.BuyerMetamaskId,
+				"ownershipMismatch": bson.M{"$ne": bson.A{bson.M{"$toLower": "$ownerMetamaskId"}, latest.BuyerMetamaskId}}}
+		}
+		if _, err := db.Collection("assets").UpdateMany(
+			context.TODO(),
+			bson.M{"chain": network, "contractAddress": contractAddress, "collectibleTokenId": tokenId},
+			mongo.Pipeline{{{"$set", ownership}}}); err != nil {
+			logs.FromContext(ctx).Error(err)
+			return err
+		}
+	}
+	return nil
+}
@@ -1,6 +1,9 @@
 package models
 
-import "math/big"
+import (
+	"math/big"
+	"openseasync/chain"
+)
 
 type User struct {
 	Id               string `json:"id" bson:"id"`                             // 用户 ID
@@ -16,6 +19,7 @@ type User struct {
 
 type Asset struct {
 	Id                   int    `json:"id" bson:"id"`                                     // NFT ID
+	Chain                string `json:"chain" bson:"chain"`                               // 所在链
 	UserMetamaskID       string `json:"userMetamaskId" bson:"userMetamaskId"`             // 用户地址
 	CollectibleName      string `json:"collectibleName" bson:"collectibleName"`           // NFT作品标题
 	CoverImageUrl        string `json:"coverImageUrl" bson:"coverImageUrl"`               // 封面图片
@@ -47,7 +51,7 @@ type Asset struct {
 	SellOrders SellOrder `json:"sellOrders" bson:"sellOrders"`
 
 	NumOfCopies int `json:"numOfCopies" bson:"numOfCopies"`
-	TotalCopies int `json:"totalCopies " bson:"totalCopies "`
+	TotalCopies int `json:"totalCopies" bson:"totalCopies "`
 
 	Price string `json:"price" bson:"price"`
 
@@ -56,6 +60,10 @@ type Asset struct {
 
 	Status string `json:"status" bson:"status"`
 
+	OnChainOwner       string `json:"onChainOwner" bson:"onChainOwner"`             // 链上拥有者地址
+	OwnershipMismatch  bool   `json:"ownershipMismatch" bson:"ownershipMismatch"`   // 链上所有权与opensea不一致
+	OwnershipCheckTime int64  `json:"ownershipCheckTime" bson:"ownershipCheckTime"` // 链上校验时间
+
 	IsDelete    int8        `json:"isDelete" bson:"isDelete"`       // 是否删除 1删除 0未删除 默认为0
 	RefreshTime int64       `json:"refreshTime" bson:"refreshTime"` // 刷新时间
 	CreateDate  int64       `json:"createDate" bson:"createDate"`   // 创建时间
@@ -65,12 +73,13 @@ type Asset struct {
 
 type Collection struct {
 	ID                 string  `json:"id" bson:"id"`                                 // 集合ID
+	Chain              string  `json:"chain" bson:"chain"`                           // 所在链
 	UserMetamaskID     string  `json:"userMetamaskId" bson:"userMetamaskId"`         // 用户地址
 	CreatorMetamaskId  string  `json:"creatorMetamaskId" bson:"creatorMetamaskId"`   // 集合创造者地址
 	CollectionName     string  `json:"collectionName" bson:"collectionName"`         // 集合名称
 	UserCoverUrl       string  `json:"userCoverUrl" bson:"userCoverUrl"`             // 集合背景图
 	Description        string  `json:"description" bson:"description"`               // 集合描述
-	CoverImageUrl      string  `json:"coverImageUrl " bson:"coverImageUrl "`         // 封面图片
+	CoverImageUrl      string  `json:"coverImageUrl" bson:"coverImageUrl "`          // 封面图片
 	CoverLargeImageUrl string  `json:"coverLargeImageURL" bson:"coverLargeImageURL"` // 头像大图
 	IsDelete           int8    `json:"isDelete" bson:"isDelete"`                     // 是否删除 1删除 0未删除 默认为0
 	CreateDate         int64   `json:"createDate" bson:"createDate"`                 // 集合创建时间
@@ -86,6 +95,7 @@ type Collection struct {
 
 type Contract struct {
 	Address      string `json:"address" bson:"address"`           // 合约地址
+	Chain        string `json:"chain" bson:"chain"`               // 所在链
 	ContractType string `json:"contractType" bson:"contractType"` // 合约类型 semi-fungible可替代 non-fungible 不可替代
 	ContractName string `json:"contractName" bson:"contractName"` // 合约名字
 	Symbol       string `json:"symbol" bson:"symbol"`             // 符号
@@ -105,19 +115,30 @@ type SellOrder struct {
 type Orders struct {
 	UUID              string           `json:"uuid" bson:"uuid"`
 	Id                string           `json:"id" bson:"id"`                       // 订单hash
+	Chain             string           `json:"chain" bson:"chain"`                 // 所在链
 	CollectibleId     int              `json:"collectibleId" bson:"collectibleId"` // NFT id
-	StartTime         int64            `json:"startTime" bson:"startTime"`         // 创建时间
-	BidTime           int64            `json:"bidTime" bson:"bidTime"`             // 投标时间
-	EndTime           int64            `json:"endTime" bson:"endTime"`             // 结束时间
-	Price             string           `json:"price" bson:"price"`                 // 当前价格
-	BasePrice         string           `json:"basePrice" bson:"basePrice"`         // 基础价格
+	CollectionId      string           `json:"collectionId" bson:"collectionId"`   // 集合ID
+	ContractAddress   string           `json:"contractAddress" bson:"contractAddress"`
+	TokenId           string           `json:"tokenId" bson:"tokenId"`
+	StartTime         int64            `json:"startTime" bson:"startTime"` // 创建时间
+	BidTime           int64            `json:"bidTime" bson:"bidTime"`     // 投标时间
+	EndTime           int64            `json:"endTime" bson:"endTime"`     // 结束时间
+	Price             string           `json:"price" bson:"price"`         // 当前价格
+	BasePrice         string           `json:"basePrice" bson:"basePrice"` // 基础价格
 	CurrentBounty     string           `json:"currentBounty" bson:"currentBounty"`
 	AuctionUserId     string           `json:"auctionUserId" bson:"auctionUserId"`
 	AuctionMetamaskId string           `json:"auctionMetamaskId" bson:"auctionMetamaskId"`
 	AuctionUserName   string           `json:"auctionUserName" bson:"auctionUserName"`
 	PayTokenContract  PayTokenContract `json:"payTokenContract" bson:"payTokenContract"` // 支付方式
 	IsDelete          int8             `json:"isDelete" bson:"isDelete"`                 // 是否删除 1删除 0未删除 默认为0
-	TradeType         string           `json:"tradeType " bson:"tradeType"`              // 事件类型
+	TradeType         string           `json:"tradeType" bson:"tradeType"`               // 事件类型
+	Status            string           `json:"status" bson:"status"`                     // 订单状态 active filled cancelled expired
+	StatusTime        int64            `json:"statusTime" bson:"statusTime"`             // 状态变化时间
+	Protocol          string           `json:"protocol" bson:"protocol"`                 // 订单协议 wyvern seaport
+	Side              string           `json:"side" bson:"side"`                         // 订单方向 sell 挂单 buy 出价
+	Signer            string           `json:"signer" bson:"signer"`                     // 签名恢复出的地址
+	SignatureStatus   string           `json:"signatureStatus" bson:"signatureStatus"`   // 签名校验结果 valid invalid unverified
+	SignatureError    string           `json:"signatureError" bson:"signatureError"`     // 签名无效或未校验的原因
 }
 
 type PayTokenContract struct {
@@ -125,6 +146,7 @@ type PayTokenContract struct {
 	ImageURL string `json:"imageURL" bson:"imageURL"`
 	EthPrice string `json:"ethPrice" bson:"ethPrice"`
 	UsdPrice string `json:"usdPrice" bson:"usdPrice"`
+	Decimals *int   `json:"decimals" bson:"decimals"` // 代币精度 未记录时为空 按18位计
 }
 
 type Trait struct {
@@ -153,30 +175,40 @@ type AssetsTopOwnership struct {
 }
 
 type ItemActivity struct {
-	Id               int              `json:"id" bson:"id"`
-	CollectibleId    int              `json:"collectibleId" bson:"collectibleId"`     // NFT id
-	CollectibleName  string           `json:"collectibleName" bson:"collectibleName"` // NFT 名字
-	CollectionId     string           `json:"collectionId" bson:"collectionId"`       // 集合ID
-	CollectionName   string           `json:"collectionName" bson:"collectionName"`   // 集合名
-	ContractAddress  string           `json:"contractAddress" bson:"contractAddress"`
-	TokenId          string           `json:"tokenId" bson:"tokenId"`
-	BidAmount        string           `json:"bidAmount" bson:"bidAmount"` // 投标金额
-	CreateDate       int64            `json:"createDate" bson:"createDate"`
-	Price            string           `json:"price" bson:"price"`                       // 成交价格ETH
-	PriceInUsd       string           `json:"priceInUsd " bson:"priceInUsd "`           // 成交价格USD
-	SellerId         int              `json:"sellerId" bson:"sellerId"`                 // 售卖者ID
-	SellerMetamaskId string           `json:"sellerMetamaskId" bson:"sellerMetamaskId"` // 售卖者地址
-	SellerName       string           `json:"sellerName" bson:"sellerName"`             // 售卖者名字
-	SellerImgUrl     string           `json:"sellerImgUrl" bson:"sellerImgUrl"`         // 售卖者头像
-	BuyerId          int              `json:"buyerId" bson:"buyerId"`                   // 购买者ID
-	BuyerMetamaskId  string           `json:"buyerMetamaskId" bson:"buyerMetamaskId"`   // 购买者地址
-	BuyerName        string           `json:"buyerName" bson:"buyerName"`               // 购买者名字
-	BuyerImgURL      string           `json:"buyerImgUrl" bson:"buyerImgUrl"`           // 购买者头像
-	Quantity         string           `json:"quantity" bson:"quantity"`                 // 数量
-	IsDelete         int8             `json:"isDelete" bson:"isDelete"`                 // 是否删除 1删除 0未删除 默认为0
-	TradeType        string           `json:"tradeType " bson:"tradeType"`              // 事件类型
-	PayTokenContract PayTokenContract `json:"payTokenContract" bson:"payTokenContract"` // 支付方式
-	Transaction      struct {
+	Id                int              `json:"id" bson:"id"`
+	Chain             string           `json:"chain" bson:"chain"`                     // 所在链
+	CollectibleId     int              `json:"collectibleId" bson:"collectibleId"`     // NFT id
+	CollectibleName   string           `json:"collectibleName" bson:"collectibleName"` // NFT 名字
+	CollectionId      string           `json:"collectionId" bson:"collectionId"`       // 集合ID
+	CollectionName    string           `json:"collectionName" bson:"collectionName"`   // 集合名
+	ContractAddress   string           `json:"contractAddress" bson:"contractAddress"`
+	TokenId           string           `json:"tokenId" bson:"tokenId"`
+	BidAmount         string           `json:"bidAmount" bson:"bidAmount"` // 投标金额
+	CreateDate        int64            `json:"createDate" bson:"createDate"`
+	Price             string           `json:"price" bson:"price"`                         // 成交价格ETH
+	PriceInUsd        string           `json:"priceInUsd" bson:"priceInUsd "`              // 成交价格USD
+	TradeEthPrice     string           `json:"tradeEthPrice" bson:"tradeEthPrice"`         // 成交时支付代币的ETH价格
+	TradeUsdPrice     string           `json:"tradeUsdPrice" bson:"tradeUsdPrice"`         // 成交时支付代币的USD价格
+	TradePriceTime    int64            `json:"tradePriceTime" bson:"tradePriceTime"`       // 所用汇率的观察时间
+	SellerId          int              `json:"sellerId" bson:"sellerId"`                   // 售卖者ID
+	SellerMetamaskId  string           `json:"sellerMetamaskId" bson:"sellerMetamaskId"`   // 售卖者地址
+	SellerName        string           `json:"sellerName" bson:"sellerName"`               // 售卖者名字
+	SellerImgUrl      string           `json:"sellerImgUrl" bson:"sellerImgUrl"`           // 售卖者头像
+	BuyerId           int              `json:"buyerId" bson:"buyerId"`                     // 购买者ID
+	BuyerMetamaskId   string           `json:"buyerMetamaskId" bson:"buyerMetamaskId"`     // 购买者地址
+	BuyerName         string           `json:"buyerName" bson:"buyerName"`                 // 购买者名字
+	BuyerImgURL       string           `json:"buyerImgUrl" bson:"buyerImgUrl"`             // 购买者头像
+	Quantity          string           `json:"quantity" bson:"quantity"`                   // 数量
+	IsDelete          int8             `json:"isDelete" bson:"isDelete"`                   // 是否删除 1删除 0未删除 默认为0
+	TradeType         string           `json:"tradeType" bson:"tradeType"`                 // 事件类型
+	PayTokenContract  PayTokenContract `json:"payTokenContract" bson:"payTokenContract"`   // 支付方式
+	Source            string           `json:"source" bson:"source"`                       // 数据来源 opensea 或 chain
+	LogIndex          int              `json:"logIndex" bson:"logIndex"`                   // 链上日志序号
+	BlockNumber       int64            `json:"blockNumber" bson:"blockNumber"`             // 链上区块高度
+	ChainStatus       string           `json:"chainStatus" bson:"chainStatus"`             // 链上确认状态 pending confirmed reorged
+	Suspicious        bool             `json:"suspicious" bson:"suspicious"`               // 疑似刷单
+	SuspiciousReasons []string         `json:"suspiciousReasons" bson:"suspiciousReasons"` // 疑似刷单的原因
+	Transaction       struct {
 		BlockHash   string `json:"block_hash" bson:"block_hash"`
 		BlockNumber string `json:"block_number" bson:"block_number"`
 		FromAccount struct {
@@ -486,6 +518,7 @@ type AutoAsset struct {
 		Extra           string `json:"extra"`
 		Quantity        string `json:"quantity"`
 		Salt            string `json:"salt"`
+		Nonce           string `json:"nonce"`
 		V               int    `json:"v"`
 		R               string `json:"r"`
 		S               string `json:"s"`
@@ -495,6 +528,26 @@ type AutoAsset struct {
 		MarkedInvalid   bool   `json:"marked_invalid"`
 		PrefixedHash    string `json:"prefixed_hash"`
 	} `json:"orders"`
+	SeaportSellOrders []struct {
+		CreatedDate     string `json:"created_date"`
+		ClosingDate     string `json:"closing_date"`
+		OrderHash       string `json:"order_hash"`
+		ProtocolAddress string `json:"protocol_address"`
+		ProtocolData    struct {
+			Parameters chain.SeaportOrder `json:"parameters"`
+			Signature  string             `json:"signature"`
+		} `json:"protocol_data"`
+		Maker struct {
+			User struct {
+				Username string `json:"username"`
+			} `json:"user"`
+			Address string `json:"address"`
+		} `json:"maker"`
+		CurrentPrice  string `json:"current_price"`
+		Cancelled     bool   `json:"cancelled"`
+		Finalized     bool   `json:"finalized"`
+		MarkedInvalid bool   `json:"marked_invalid"`
+	} `json:"seaport_sell_orders"`
 	Creator struct {
 		User struct {
 			Username string `json:"username"`
@@ -779,55 +832,122 @@ type Params struct {
 	MinPrice float64 `form:"minPrice" binding:"numeric,min=0"`
 	MaxPrice float64 `form:"maxPrice" binding:"numeric,min=0"`
 	Field    string  `form:"field"`
+	Chain    string  `form:"chain" binding:"omitempty,chain"`
 }
 
+// ResponseCollection a collection with its creator, /collection/getInfo
 type ResponseCollection struct {
-	Id             string  `json:"id"`
-	UserId         string  `json:"userId"`
-	UserMetamaskId string  `json:"userMetamaskId"`
-	UserCoverUrl   string  `json:"userCoverUrl"`
-	AvatarUrl      string  `json:"avatarUrl"`
-	UserName       string  `json:"userName"`
-	ItemsCount     int     `json:"itemsCount"`
-	OwnersCount    int     `json:"ownersCount"`
-	LikesCount     int     `json:"likesCount"`
-	ViewsCount     int     `json:"viewsCount"`
-	FloorPrice     int     `json:"floorPrice"`
-	HighestPrice   float64 `json:"highestPrice"`
-	CollectionName string  `json:"collectionName"`
-	Description    string  `json:"description"`
+	Id             string `json:"id" bson:"id"`
+	Chain          string `json:"chain" bson:"chain"`
+	UserId         string `json:"userId" bson:"userId"`
+	UserMetamaskId string `json:"userMetamaskId" bson:"userMetamaskId"`
+	UserCoverUrl   string `json:"userCoverUrl" bson:"userCoverUrl"`
+	AvatarUrl      string `json:"avatarUrl" bson:"avatarUrl"`
+	UserName       string `json:"userName" bson:"userName"`
+	ItemsCount     int    `json:"itemsCount" bson:"itemsCount"`
+	OwnersCount    int    `json:"ownersCount" bson:"ownersCount"`
+	LikesCount     int    `json:"likesCount" bson:"likesCount"`
+	ViewsCount     int    `json:"viewsCount" bson:"viewsCount"`
+	FloorPrice     string `json:"floorPrice" bson:"floorPrice"`     // wei
+	HighestPrice   string `json:"highestPrice" bson:"highestPrice"` // wei
+	CollectionName string `json:"collectionName" bson:"collectionName"`
+	Description    string `json:"description" bson:"description"`
 }
 
+// ResponseAsset an asset in full, /collectibles/generalInfo
 type ResponseAsset struct {
-	Id                  int     `json:"id"`
-	CollectibleName     string  `json:"collectibleName"`
-	CollectionId        string  `json:"collectionId"`
-	CollectionName      string  `json:"collectionName"`
-	CreatorId           string  `json:"creatorId"`
-	CreatorMetamaskId   string  `json:"creatorMetamaskId"`
-	CreatorName         string  `json:"creatorName"`
-	CreatorPersonalSite string  `json:"creatorPersonalSite"`
-	Description         string  `json:"description"`
-	FileUrl             string  `json:"fileUrl"`
-	OwnerId             string  `json:"ownerId"`
-	OwnerMetamaskId     string  `json:"ownerMetamaskId"`
-	OwnerName           string  `json:"ownerName"`
-	Price               float64 `json:"price"`
-	Status              string  `json:"status"`
-	ThumbnailUrl        string  `json:"thumbnailUrl"`
-	CollectibleTokenId  string  `json:"collectibleTokenId"`
-	RecordId            string  `json:"recordId"`
-	StartTime           string  `json:"startTime"`
-	EndTime             string  `json:"endTime"`
+	Id                   int         `json:"id" bson:"id"`
+	Chain                string      `json:"chain" bson:"chain"`
+	CollectibleName      string      `json:"collectibleName" bson:"collectibleName"`
+	CollectionId         string      `json:"collectionId" bson:"collectionId"`
+	CollectionName       string      `json:"collectionName" bson:"collectionName"`
+	CreatorMetamaskId    string      `json:"creatorMetamaskId" bson:"creatorMetamaskId"`
+	CreatorName          string      `json:"creatorName" bson:"creatorName"`
+	CreatorPersonalSite  string      `json:"creatorPersonalSite" bson:"creatorPersonalSite"`
+	Description          string      `json:"description" bson:"description"`
+	FileUrl              string      `json:"fileUrl" bson:"fileUrl"`
+	OwnerMetamaskId      string      `json:"ownerMetamaskId" bson:"ownerMetamaskId"`
+	OwnerName            string      `json:"ownerName" bson:"ownerName"`
+	Price                string      `json:"price" bson:"price"` // wei
+	Status               string      `json:"status" bson:"status"`
+	ThumbnailUrl         string      `json:"thumbnailUrl" bson:"thumbnailUrl"`
+	AnimationUrl         string      `json:"animationUrl" bson:"animationUrl"`
+	AnimationOriginalUrl string      `json:"animationOriginalUrl" bson:"animationOriginalUrl"`
+	CollectibleTokenId   string      `json:"collectibleTokenId" bson:"collectibleTokenId"`
+	RecordId             string      `json:"recordId" bson:"recordId"`
+	StartTime            interface{} `json:"startTime" bson:"startTime"`
+	EndTime              interface{} `json:"endTime" bson:"endTime"`
+	OnChainOwner         string      `json:"onChainOwner" bson:"onChainOwner"`
+	OwnershipMismatch    bool        `json:"ownershipMismatch" bson:"ownershipMismatch"`
+}
+
+// ResponseAssetItem an asset in a list
+type ResponseAssetItem struct {
+	Id                   int         `json:"id" bson:"id"`
+	CoverImageUrl        string      `json:"coverImageUrl" bson:"coverImageUrl"`
+	CollectibleName      string      `json:"collectibleName" bson:"collectibleName"`
+	CreatorMetamaskId    string      `json:"creatorMetamaskId" bson:"creatorMetamaskId"`
+	CreatorName          string      `json:"creatorName" bson:"creatorName"`
+	Price                string      `json:"price" bson:"price"` // wei
+	LikesCount           int         `json:"likesCount" bson:"likesCount"`
+	ViewsCount           int         `json:"viewsCount" bson:"viewsCount"`
+	NumOfCopies          int         `json:"numOfCopies" bson:"numOfCopies"`
+	TotalCopies          int         `json:"totalCopies" bson:"totalCopies "`
+	Status               string      `json:"status" bson:"status"`
+	OwnerMetamaskId      string      `json:"ownerMetamaskId" bson:"ownerMetamaskId"`
+	CreateDate           int64       `json:"createDate" bson:"createDate"`
+	EndTime              interface{} `json:"endTime" bson:"endTime"`
+	AnimationUrl         string      `json:"animationUrl" bson:"animationUrl"`
+	AnimationOriginalUrl string      `json:"animationOriginalUrl" bson:"animationOriginalUrl"`
 }
 
+// ResponseColl a collection of a wallet with its creator, /collectionsByUserId
 type ResponseColl struct {
 	Id             string `json:"id" bson:"id"`
 	UserId         string `json:"userId" bson:"userId"`
 	UserMetamaskId string `json:"userMetamaskId" bson:"userMetamaskId"`
-	CoverImageUrl  string `json:"coverImageUrl" bson:"coverImageUrl"`
+	CoverImageUrl  string `json:"coverImageUrl" bson:"coverImageUrl "`
 	AvatarUrl      string `json:"avatarUrl" bson:"avatarUrl"`
 	UserName       string `json:"userName" bson:"userName"`
 	CollectionName string `json:"collectionName" bson:"collectionName"`
 	Description    string `json:"description" bson:"description"`
 }
+
+// ResponseItemActivity a sale, bid or transfer in a list
+type ResponseItemActivity struct {
+	Id                int      `json:"id" bson:"id"`
+	Chain             string   `json:"chain" bson:"chain"`
+	TradeType         string   `json:"tradeType" bson:"tradeType"`
+	Price             string   `json:"price" bson:"price"`
+	PriceInUsd        string   `json:"priceInUsd" bson:"priceInUsd "`
+	CollectibleId     int      `json:"collectibleId" bson:"collectibleId"`
+	CollectibleName   string   `json:"collectibleName" bson:"collectibleName"`
+	Quantity          string   `json:"quantity" bson:"quantity"`
+	BuyerId           int      `json:"buyerId" bson:"buyerId"`
+	BuyerMetamaskId   string   `json:"buyerMetamaskId" bson:"buyerMetamaskId"`
+	BuyerName         string   `json:"buyerName" bson:"buyerName"`
+	SellerId          int      `json:"sellerId" bson:"sellerId"`
+	SellerMetamaskId  string   `json:"sellerMetamaskId" bson:"sellerMetamaskId"`
+	SellerName        string   `json:"sellerName" bson:"sellerName"`
+	CreateDate        int64    `json:"createDate" bson:"createDate"`
+	Suspicious        bool     `json:"suspicious" bson:"suspicious"`
+	SuspiciousReasons []string `json:"suspiciousReasons" bson:"suspiciousReasons"`
+}
+
+// ResponseOrder a bid or listing of an asset
+type ResponseOrder struct {
+	Id                string `json:"id" bson:"id"`
+	AuctionUserId     string `json:"auctionUserId" bson:"auctionUserId"`
+	AuctionMetamaskId string `json:"auctionMetamaskId" bson:"auctionMetamaskId"`
+	AuctionUserName   string `json:"auctionUserName" bson:"auctionUserName"`
+	Price             string `json:"price" bson:"price"`
+	TradeType         string `json:"tradeType" bson:"tradeType"`
+	BidTime           int64  `json:"bidTime" bson:"bidTime"`
+	StartTime         int64  `json:"startTime" bson:"startTime"`
+	EndTime           int64  `json:"endTime" bson:"endTime"`
+	Status            string `json:"status" bson:"status"`                   // active filled cancelled expired
+	StatusTime        int64  `json:"statusTime" bson:"statusTime"`           // when the status last changed
+	Protocol          string `json:"protocol" bson:"protocol"`               // wyvern seaport
+	SignatureStatus   string `json:"signatureStatus" bson:"signatureStatus"` // valid invalid unverified, empty for orders synced before verification
+	Signer            string `json:"signer" bson:"signer"`                   // address recovered from the maker signature
+}
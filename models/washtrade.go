@@ -0,0 +1,254 @@
+package models
+
+import (
+	"context"
+	"math/big"
+	"openseasync/config"
+	"openseasync/database"
+	"openseasync/logs"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	SUSPICIOUS_PAIR_TRADING        = "pair_trading"        // the buyer and seller also traded the collection the other way
+	SUSPICIOUS_BUYER_FUNDED_SELLER = "buyer_funded_seller" // the buyer transferred the seller tokens of the contract before
+	SUSPICIOUS_ROUND_TRIP          = "round_trip"          // the token came back to an earlier seller within the round trip window
+	SUSPICIOUS_PRICE_OUTLIER       = "price_outlier"       // the unit price is far from the median of the collection
+
+	// minMedianSales sales of a collection below which its median is not trusted to find outliers
+	minMedianSales = 5
+)
+
+// WashTradeRules thresholds of the suspicious sale detection
+type WashTradeRules struct {
+	Since           int64         // unix milli, sales and transfers before are not analyzed
+	RoundTripWindow time.Duration // a token sold back to an earlier seller within it is a round trip
+	PriceDeviation  float64       // unit prices above median*deviation or below median/deviation are outliers
+}
+
+// washSale a sale being analyzed, with the reasons found so far
+type washSale struct {
+	Id                primitive.ObjectID `bson:"_id"`
+	Chain             string             `bson:"chain"`
+	CollectionId      string             `bson:"collectionId"`
+	ContractAddress   string             `bson:"contractAddress"`
+	TokenId           string             `bson:"tokenId"`
+	SellerMetamaskId  string             `bson:"sellerMetamaskId"`
+	BuyerMetamaskId   string             `bson:"buyerMetamaskId"`
+	Price             string             `bson:"price"`
+	Quantity          string             `bson:"quantity"`
+	CreateDate        int64              `bson:"createDate"`
+	Suspicious        bool               `bson:"suspicious"`
+	SuspiciousReasons []string           `bson:"suspiciousReasons"`
+	Transaction       struct {
+		TransactionHash string `bson:"transaction_hash"`
+	} `bson:"transaction"`
+
+	reasons []string
+}
+
+func (s *washSale) flag(reason string) {
+	for _, v := range s.reasons {
+		if v == reason {
+			return
+		}
+	}
+	s.reasons = append(s.reasons, reason)
+}
+
+// withoutSuspicious leave out the sales flagged as suspicious when configured to
+func withoutSuspicious(filter bson.M) bson.M {
+	if config.GetConfig().WashTrade.ExcludeFromStats {
+		filter["suspicious"] = bson.M{"$ne": true}
+	}
+	return filter
+}
+
+// FlagSuspiciousSales analyze the sales since rules.Since and store whether each is suspicious and why,
+// the number of sales whose flag changed. Sales before rules.Since keep the flag they were given.
+func FlagSuspiciousSales(ctx context.Context, rules WashTradeRules) (int64, error) {
+	db := database.GetMongoClient()
+	cursor, err := db.Collection("item_activitys").Find(context.TODO(),
+		bson.M{"tradeType": "successful", "isDelete": 0, "createDate": bson.M{"$gte": rules.Since}},
+		options.Find().SetSort(bson.M{"createDate": 1}))
+	if err != nil {
+		logs.FromContext(ctx).Error(err)
+		return 0, err
+	}
+	var sales []*washSale
+	if err = cursor.All(context.TODO(), &sales); err != nil {
+		logs.FromContext(ctx).Error(err)
+		return 0, err
+	}
+	if len(sales) == 0 {
+		return 0, nil
+	}
+	for _, v := range sales {
+		v.SellerMetamaskId = strings.ToLower(v.SellerMetamaskId)
+		v.BuyerMetamaskId = strings.ToLower(v.BuyerMetamaskId)
+	}
+
+	funding, err := findFundingTransfers(ctx, rules.Since, sales)
+	if err != nil {
+		return 0, err
+	}
+	flagPairTrading(sales)
+	for _, v := range sales {
+		if at, ok := funding[v.Chain+"|"+v.ContractAddress+"|"+v.BuyerMetamaskId+"|"+v.SellerMetamaskId]; ok && at <= v.CreateDate {
+			v.flag(SUSPICIOUS_BUYER_FUNDED_SELLER)
+		}
+	}
+	flagRoundTrips(sales, rules.RoundTripWindow)
+	flagPriceOutliers(sales, rules.PriceDeviation)
+
+	var changed int64
+	for _, v := range sales {
+		sort.Strings(v.reasons)
+		if v.Suspicious == (len(v.reasons) > 0) && strings.Join(v.SuspiciousReasons, ",") == strings.Join(v.reasons, ",") {
+			continue
+		}
+		reasons := v.reasons
+		if reasons == nil {
+			reasons = []string{}
+		}
+		if _, err := db.Collection("item_activitys").UpdateOne(context.TODO(), bson.M{"_id": v.Id},
+			bson.M{"$set": bson.M{"suspicious": len(reasons) > 0, "suspiciousReasons": reasons}}); err != nil {
+			logs.FromContext(ctx).Error(err)
+			return changed, err
+		}
+		changed++
+	}
+	return changed, nil
+}
+
+// findFundingTransfers the earliest transfer from a wallet to another since, keyed by chain|contract|from|to.
+// Transfers made by a sale, sharing its transaction, do not fund anyone.
+func findFundingTransfers(ctx context.Context, since int64, sales []*washSale) (map[string]int64, error) {
+	saleTxs := make(map[string]bool, len(sales))
+	for _, v := range sales {
+		if v.Transaction.TransactionHash != "" {
+			saleTxs[v.Transaction.TransactionHash] = true
+		}
+	}
+
+	db := database.GetMongoClient()
+	cursor, err := db.Collection("item_activitys").Find(context.TODO(),
+		bson.M{"tradeType": TRADE_TYPE_TRANSFER, "isDelete": 0, "createDate": bson.M{"$gte": since}},
+		options.Find().SetProjection(bson.M{"_id": 0, "chain": 1, "contractAddress": 1, "sellerMetamaskId": 1, "buyerMetamaskId": 1,
+			"createDate": 1, "transaction.transaction_hash": 1}))
+	if err != nil {
+		logs.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	var transfers []washSale
+	if err = cursor.All(context.TODO(), &transfers); err != nil {
+		logs.FromContext(ctx).Error(err)
+		return nil, err
+	}
+
+	funding := make(map[string]int64)
+	for _, v := range transfers {
+		from, to := strings.ToLower(v.SellerMetamaskId), strings.ToLower(v.BuyerMetamaskId)
+		if from == "" || to == "" || from == to || saleTxs[v.Transaction.TransactionHash] {
+			continue
+		}
+		key := v.Chain + "|" + v.ContractAddress + "|" + from + "|" + to
+		if at, ok := funding[key]; !ok || v.CreateDate < at {
+			funding[key] = v.CreateDate
+		}
+	}
+	return funding, nil
+}
+
+// flagPairTrading flag the sales between two wallets that traded a collection both ways
+func flagPairTrading(sales []*washSale) {
+	directions := make(map[string]bool)
+	for _, v := range sales {
+		directions[v.Chain+"|"+v.CollectionId+"|"+v.SellerMetamaskId+"|"+v.BuyerMetamaskId] = true
+	}
+	for _, v := range sales {
+		if v.SellerMetamaskId == "" || v.BuyerMetamaskId == "" {
+			continue
+		}
+		if directions[v.Chain+"|"+v.CollectionId+"|"+v.BuyerMetamaskId+"|"+v.SellerMetamaskId] {
+			v.flag(SUSPICIOUS_PAIR_TRADING)
+		}
+	}
+}
+
+// flagRoundTrips flag the sales of a token from an earlier seller until it buys the token back within window
+func flagRoundTrips(sales []*washSale, window time.Duration) {
+	tokens := make(map[string][]*washSale)
+	for _, v := range sales {
+		key := v.Chain + "|" + v.ContractAddress + "|" + v.TokenId
+		tokens[key] = append(tokens[key], v)
+	}
+	for _, token := range tokens {
+		for j := range token {
+			for i := j - 1; i >= 0 && token[j].CreateDate-token[i].CreateDate <= window.Milliseconds(); i-- {
+				if token[i].SellerMetamaskId == "" || token[i].SellerMetamaskId != token[j].BuyerMetamaskId {
+					continue
+				}
+				for k := i; k <= j; k++ {
+					token[k].flag(SUSPICIOUS_ROUND_TRIP)
+				}
+				break
+			}
+		}
+	}
+}
+
+// flagPriceOutliers flag the sales whose unit price is deviation times above or below the median of their collection
+func flagPriceOutliers(sales []*washSale, deviation float64) {
+	if deviation <= 1 {
+		return
+	}
+	type priced struct {
+		sale  *washSale
+		price *big.Float
+	}
+	collections := make(map[string][]priced)
+	for _, v := range sales {
+		price, ok := new(big.Float).SetString(v.Price)
+		if !ok {
+			continue
+		}
+		if quantity, ok := new(big.Float).SetString(v.Quantity); ok && quantity.Sign() > 0 {
+			price.Quo(price, quantity)
+		}
+		key := v.Chain + "|" + v.CollectionId
+		collections[key] = append(collections[key], priced{sale: v, price: price})
+	}
+
+	factor := big.NewFloat(deviation)
+	for _, prices := range collections {
+		if len(prices) < minMedianSales {
+			continue
+		}
+		sorted := make([]*big.Float, len(prices))
+		for i, v := range prices {
+			sorted[i] = v.price
+		}
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i].Cmp(sorted[j]) < 0 })
+		median := new(big.Float).Set(sorted[len(sorted)/2])
+		if len(sorted)%2 == 0 {
+			median.Add(median, sorted[len(sorted)/2-1]).Quo(median, big.NewFloat(2))
+		}
+		if median.Sign() <= 0 {
+			continue
+		}
+		high := new(big.Float).Mul(median, factor)
+		low := new(big.Float).Quo(median, factor)
+		for _, v := range prices {
+			if v.price.Cmp(high) > 0 || v.price.Cmp(low) < 0 {
+				v.sale.flag(SUSPICIOUS_PRICE_OUTLIER)
+			}
+		}
+	}
+}
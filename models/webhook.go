@@ -0,0 +1,231 @@
+package models
+
+import (
+	"context"
+	"openseasync/database"
+	"openseasync/events"
+	"openseasync/logs"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	WEBHOOK_DELIVERY_STATUS_PENDING = "pending"
+	WEBHOOK_DELIVERY_STATUS_SUCCESS = "success"
+	WEBHOOK_DELIVERY_STATUS_RETRY   = "retrying"
+	WEBHOOK_DELIVERY_STATUS_DEAD    = "dead"
+)
+
+// Webhook a subscription, empty filters match everything
+type Webhook struct {
+	Id            string   `json:"id" bson:"id"`
+	Url           string   `json:"url" bson:"url"`                     // 回调地址
+	Secret        string   `json:"-" bson:"secret"`                    // HMAC 签名密钥
+	Chain         string   `json:"chain" bson:"chain"`                 // 所在链 空为全部
+	Wallets       []string `json:"wallets" bson:"wallets"`             // 关注的钱包地址
+	CollectionIds []string `json:"collectionIds" bson:"collectionIds"` // 关注的集合ID
+	EventTypes    []string `json:"eventTypes" bson:"eventTypes"`       // 关注的事件类型
+	IsDelete      int8     `json:"isDelete" bson:"isDelete"`           // 是否删除 1删除 0未删除 默认为0
+	CreateDate    int64    `json:"createDate" bson:"createDate"`
+}
+
+// WebhookDelivery one event sent, or being sent, to one webhook
+type WebhookDelivery struct {
+	Id           string `json:"id" bson:"id"`
+	WebhookId    string `json:"webhookId" bson:"webhookId"`
+	Url          string `json:"url" bson:"url"`
+	EventType    string `json:"eventType" bson:"eventType"`       // 事件类型
+	Payload      string `json:"payload" bson:"payload"`           // 签名并发送的 JSON 正文
+	Status       string `json:"status" bson:"status"`             // pending success retrying dead
+	Attempts     int    `json:"attempts" bson:"attempts"`         // 已尝试次数
+	ResponseCode int    `json:"responseCode" bson:"responseCode"` // 最后一次响应码
+	LastError    string `json:"lastError" bson:"lastError"`       // 最后一次错误
+	CreateDate   int64  `json:"createDate" bson:"createDate"`
+	UpdateDate   int64  `json:"updateDate" bson:"updateDate"`
+}
+
+// InsertWebhook save a new subscription
+func InsertWebhook(webhook *Webhook) error {
+	db := database.GetMongoClient()
+	if _, err := db.Collection("webhooks").InsertOne(context.TODO(), webhook); err != nil {
+		logs.GetLogger().Error(err)
+		return err
+	}
+	return nil
+}
+
+// FindWebhooks list the subscriptions not deleted
+func FindWebhooks() ([]Webhook, error) {
+	var webhooks = make([]Webhook, 0)
+	db := database.GetMongoClient()
+	cursor, err := db.Collection("webhooks").Find(context.TODO(), bson.M{"isDelete": 0},
+		options.Find().SetSort(bson.M{"createDate": -1}))
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	if err = cursor.All(context.TODO(), &webhooks); err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// FindWebhookById find a subscription, nil when it does not exist
+func FindWebhookById(id string) (*Webhook, error) {
+	var webhook Webhook
+	db := database.GetMongoClient()
+	err := db.Collection("webhooks").FindOne(context.TODO(), bson.M{"id": id, "isDelete": 0}).Decode(&webhook)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// DeleteWebhookById delete a subscription, false when it does not exist
+func DeleteWebhookById(id string) (bool, error) {
+	db := database.GetMongoClient()
+	result, err := db.Collection("webhooks").UpdateOne(context.TODO(),
+		bson.M{"id": id, "isDelete": 0}, bson.M{"$set": bson.M{"isDelete": 1}})
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// FindWebhooksForEvent the subscriptions whose filters all match event
+func FindWebhooksForEvent(event events.Event) ([]Webhook, error) {
+	var webhooks = make([]Webhook, 0)
+	wallets := event.Wallets
+	if wallets == nil {
+		wallets = []string{}
+	}
+	db := database.GetMongoClient()
+	filter := bson.M{
+		"isDelete": 0,
+		"chain":    bson.M{"$in": bson.A{"", event.Chain}},
+		"$and": bson.A{
+			bson.M{"$or": bson.A{bson.M{"eventTypes": bson.M{"$size": 0}}, bson.M{"eventTypes": event.Type}}},
+			bson.M{"$or": bson.A{bson.M{"collectionIds": bson.M{"$size": 0}}, bson.M{"collectionIds": event.CollectionId}}},
+			bson.M{"$or": bson.A{bson.M{"wallets": bson.M{"$size": 0}}, bson.M{"wallets": bson.M{"$in": wallets}}}},
+		},
+	}
+	cursor, err := db.Collection("webhooks").Find(context.TODO(), filter)
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	if err = cursor.All(context.TODO(), &webhooks); err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// SaveWebhookDelivery insert or update a delivery in the delivery log
+func SaveWebhookDelivery(delivery *WebhookDelivery) error {
+	db := database.GetMongoClient()
+	delivery.UpdateDate = time.Now().UnixMilli()
+	if _, err := db.Collection("webhook_deliveries").ReplaceOne(context.TODO(),
+		bson.M{"id": delivery.Id}, delivery, options.Replace().SetUpsert(true)); err != nil {
+		logs.GetLogger().Error(err)
+		return err
+	}
+	return nil
+}
+
+// FindWebhookDeliveryById find a delivery of the delivery log, nil when it does not exist
+func FindWebhookDeliveryById(id string) (*WebhookDelivery, error) {
+	var delivery WebhookDelivery
+	db := database.GetMongoClient()
+	err := db.Collection("webhook_deliveries").FindOne(context.TODO(), bson.M{"id": id}).Decode(&delivery)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// InsertWebhookDeadLetter keep a delivery that ran out of retries
+func InsertWebhookDeadLetter(delivery *WebhookDelivery) error {
+	db := database.GetMongoClient()
+	if _, err := db.Collection("webhook_dead_letters").ReplaceOne(context.TODO(),
+		bson.M{"id": delivery.Id}, delivery, options.Replace().SetUpsert(true)); err != nil {
+		logs.GetLogger().Error(err)
+		return err
+	}
+	return nil
+}
+
+// FindWebhookDeadLetterById find a dead letter, nil when it does not exist
+func FindWebhookDeadLetterById(id string) (*WebhookDelivery, error) {
+	var delivery WebhookDelivery
+	db := database.GetMongoClient()
+	err := db.Collection("webhook_dead_letters").FindOne(context.TODO(), bson.M{"id": id}).Decode(&delivery)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// DeleteWebhookDeadLetterById remove a dead letter once it is queued again
+func DeleteWebhookDeadLetterById(id string) error {
+	db := database.GetMongoClient()
+	if _, err := db.Collection("webhook_dead_letters").DeleteOne(context.TODO(), bson.M{"id": id}); err != nil {
+		logs.GetLogger().Error(err)
+		return err
+	}
+	return nil
+}
+
+// FindWebhookDeliveries page through the delivery log, or the dead letters, of a webhook
+func FindWebhookDeliveries(webhookId string, deadLetters bool, page, pageSize int64) (map[string]interface{}, error) {
+	var (
+		deliveries = make([]WebhookDelivery, 0)
+		result     = make(map[string]interface{})
+		name       = "webhook_deliveries"
+	)
+	if deadLetters {
+		name = "webhook_dead_letters"
+	}
+	db := database.GetMongoClient()
+	total, err := db.Collection(name).CountDocuments(context.TODO(), bson.M{"webhookId": webhookId})
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	totalPage := total / pageSize
+	if total%pageSize != 0 {
+		totalPage++
+	}
+
+	cursor, err := db.Collection(name).Find(context.TODO(), bson.M{"webhookId": webhookId},
+		options.Find().SetSort(bson.M{"createDate": -1}).SetSkip((page-1)*pageSize).SetLimit(pageSize))
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	if err = cursor.All(context.TODO(), &deliveries); err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+
+	result["data"] = deliveries
+	result["metadata"] = map[string]int64{"page": page, "pageSize": pageSize, "total": total, "totalPage": totalPage}
+	return result, nil
+}
@@ -0,0 +1,429 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	VERSION         = "3.0.3"
+	SECURITY_BEARER = "bearerAuth" // the security scheme of the routes with Auth
+)
+
+// Document an OpenAPI 3 document, only the parts this service uses
+type Document struct {
+	OpenAPI    string                           `json:"openapi"`
+	Info       Info                             `json:"info"`
+	Paths      map[string]map[string]*Operation `json:"paths"`
+	Components Components                       `json:"components"`
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme,omitempty"`
+}
+
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Default              interface{}        `json:"default,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	// Order the properties in declaration order, an extension the client generator reads
+	Order []string `json:"x-order,omitempty"`
+}
+
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type Operation struct {
+	OperationId string                `json:"operationId"`
+	Summary     string                `json:"summary,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []*Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+// Route what a handler takes and returns.
+// Request holds the path and query parameters by form tag, Body the json body,
+// Response the data of the success response, nil when there is none.
+type Route struct {
+	Method      string
+	Path        string // gin syntax, /wallet/:user/pnl
+	OperationId string
+	Summary     string
+	Tag         string
+	Request     interface{}
+	Body        interface{}
+	Response    interface{}
+	Paged       bool   // metadata carries a PageMetadata
+	ContentType string // of the success response, application/json when empty
+	Errors      []int  // statuses answered with an ErrorResponse besides 400 and 500
+	Auth        bool   // requires a bearer token, answered with 401 without one
+}
+
+// PageMetadata paging of a list response
+type PageMetadata struct {
+	Page      int64 `json:"page"`
+	PageSize  int64 `json:"pageSize"`
+	Total     int64 `json:"total"`
+	TotalPage int64 `json:"totalPage"`
+}
+
+// FieldError why a request parameter was rejected
+type FieldError struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// ErrorResponse the body of every error response
+type ErrorResponse struct {
+	Status  string       `json:"status"`
+	Code    string       `json:"code"`
+	Message string       `json:"message"`
+	Errors  []FieldError `json:"errors,omitempty"`
+}
+
+var pathParam = regexp.MustCompile(`:([A-Za-z0-9_]+)`)
+
+// OpenAPIPath /wallet/:user/pnl as /wallet/{user}/pnl
+func OpenAPIPath(path string) string {
+	return pathParam.ReplaceAllString(path, "{$1}")
+}
+
+// Build describe routes, schemas are derived from the json tags of the Go types
+func Build(title, version string, routes []Route) *Document {
+	doc := &Document{
+		OpenAPI:    VERSION,
+		Info:       Info{Title: title, Version: version},
+		Paths:      make(map[string]map[string]*Operation),
+		Components: Components{Schemas: make(map[string]*Schema)},
+	}
+	g := &generator{schemas: doc.Components.Schemas, names: make(map[reflect.Type]string)}
+	errorSchema := g.schema(reflect.TypeOf(ErrorResponse{}))
+
+	for _, route := range routes {
+		op := &Operation{
+			OperationId: route.OperationId,
+			Summary:     route.Summary,
+			Responses:   make(map[string]*Response),
+		}
+		if route.Tag != "" {
+			op.Tags = []string{route.Tag}
+		}
+		if route.Request != nil {
+			op.Parameters = g.parameters(reflect.TypeOf(route.Request), route.Path)
+		}
+		if route.Body != nil {
+			op.RequestBody = &RequestBody{Required: true, Content: map[string]*MediaType{
+				"application/json": {Schema: g.schema(reflect.TypeOf(route.Body))},
+			}}
+		}
+
+		contentType := route.ContentType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		var success *Schema
+		if contentType == "application/json" {
+			success = g.envelope(route)
+		} else {
+			success = &Schema{Type: "string"}
+		}
+		op.Responses["200"] = &Response{Description: "success", Content: map[string]*MediaType{contentType: {Schema: success}}}
+		if op.Parameters != nil || op.RequestBody != nil {
+			op.Responses["400"] = &Response{Description: "invalid request", Content: map[string]*MediaType{"application/json": {Schema: errorSchema}}}
+		}
+		if route.Auth {
+			if doc.Components.SecuritySchemes == nil {
+				doc.Components.SecuritySchemes = map[string]*SecurityScheme{SECURITY_BEARER: {Type: "http", Scheme: "bearer"}}
+			}
+			op.Security = []map[string][]string{{SECURITY_BEARER: {}}}
+			op.Responses["401"] = &Response{Description: http.StatusText(http.StatusUnauthorized), Content: map[string]*MediaType{"application/json": {Schema: errorSchema}}}
+		}
+		for _, status := range route.Errors {
+			op.Responses[strconv.Itoa(status)] = &Response{Description: http.StatusText(status), Content: map[string]*MediaType{"application/json": {Schema: errorSchema}}}
+		}
+		op.Responses["500"] = &Response{Description: "server error", Content: map[string]*MediaType{"application/json": {Schema: errorSchema}}}
+
+		path := OpenAPIPath(route.Path)
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = make(map[string]*Operation)
+		}
+		doc.Paths[path][strings.ToLower(route.Method)] = op
+	}
+	return doc
+}
+
+// Operation the operation of method on a path in gin syntax, nil when undocumented
+func (d *Document) Operation(method, path string) *Operation {
+	return d.Paths[OpenAPIPath(path)][strings.ToLower(method)]
+}
+
+// Resolve follow a $ref, or the single $ref wrapped to make it nullable
+func (d *Document) Resolve(schema *Schema) *Schema {
+	for schema != nil {
+		if len(schema.AllOf) == 1 {
+			schema = schema.AllOf[0]
+		} else if schema.Ref != "" {
+			schema = d.Components.Schemas[RefName(schema.Ref)]
+		} else {
+			return schema
+		}
+	}
+	return schema
+}
+
+// RefName the component name a $ref points to
+func RefName(ref string) string {
+	return strings.TrimPrefix(ref, "#/components/schemas/")
+}
+
+type generator struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+// envelope common.BasicResponse around the data of route
+func (g *generator) envelope(route Route) *Schema {
+	envelope := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"status": {Type: "string", Enum: []string{"success"}},
+			"code":   {Type: "string"},
+		},
+		Required: []string{"status", "code"},
+		Order:    []string{"status", "code"},
+	}
+	if route.Response != nil {
+		data := g.schema(reflect.TypeOf(route.Response))
+		envelope.Properties["data"] = data
+		envelope.Order = append(envelope.Order, "data")
+		if !data.Nullable {
+			envelope.Required = append(envelope.Required, "data")
+		}
+	}
+	if route.Paged {
+		envelope.Properties["metadata"] = g.schema(reflect.TypeOf(PageMetadata{}))
+		envelope.Required = append(envelope.Required, "metadata")
+		envelope.Order = append(envelope.Order, "metadata")
+	}
+	return envelope
+}
+
+func (g *generator) schema(t reflect.Type) *Schema {
+	switch t.Kind() {
+	case reflect.Ptr:
+		s := g.schema(t.Elem())
+		if s.Ref != "" {
+			// siblings of $ref are ignored in 3.0, wrap it
+			return &Schema{AllOf: []*Schema{s}, Nullable: true}
+		}
+		s.Nullable = true
+		return s
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Interface:
+		return &Schema{}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+		name, ok := g.names[t]
+		if !ok {
+			name = g.name(t)
+			g.names[t] = name
+			g.schemas[name] = &Schema{}
+			*g.schemas[name] = *g.object(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+	return &Schema{}
+}
+
+// name the component name of t, qualified by its package when the bare name is taken
+func (g *generator) name(t reflect.Type) string {
+	name := t.Name()
+	if _, taken := g.schemas[name]; taken {
+		pkg := t.PkgPath()
+		name = strings.Title(pkg[strings.LastIndex(pkg, "/")+1:]) + name
+	}
+	return name
+}
+
+// object the properties of a struct as encoding/json writes them
+func (g *generator) object(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for _, field := range fields(t) {
+		name, omitempty := jsonName(field)
+		if name == "" {
+			continue
+		}
+		if _, exists := s.Properties[name]; exists {
+			continue
+		}
+		s.Properties[name] = g.schema(field.Type)
+		s.Order = append(s.Order, name)
+		if !omitempty {
+			s.Required = append(s.Required, name)
+		}
+	}
+	return s
+}
+
+// parameters the path and query parameters of a request type
+func (g *generator) parameters(t reflect.Type, path string) []*Parameter {
+	inPath := make(map[string]bool)
+	for _, match := range pathParam.FindAllStringSubmatch(path, -1) {
+		inPath[match[1]] = true
+	}
+	var params []*Parameter
+	for _, field := range fields(t) {
+		tag := field.Tag.Get("form")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		options := strings.Split(tag, ",")
+		param := &Parameter{Name: options[0], In: "query", Schema: g.schema(field.Type)}
+		for _, option := range options[1:] {
+			if value := strings.TrimPrefix(option, "default="); value != option {
+				param.Schema.Default = value
+				if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+					param.Schema.Default = n
+				}
+			}
+		}
+		for _, rule := range strings.Split(field.Tag.Get("binding"), ",") {
+			key, value := rule, ""
+			if i := strings.Index(rule, "="); i >= 0 {
+				key, value = rule[:i], rule[i+1:]
+			}
+			switch key {
+			case "required":
+				param.Required = true
+			case "oneof":
+				param.Schema.Enum = strings.Fields(value)
+			case "min":
+				if n, err := strconv.ParseFloat(value, 64); err == nil {
+					param.Schema.Minimum = &n
+				}
+			case "max":
+				if n, err := strconv.ParseFloat(value, 64); err == nil {
+					param.Schema.Maximum = &n
+				}
+			case "ethaddr":
+				param.Schema.Pattern = "^0x[0-9a-fA-F]{40}$"
+			}
+		}
+		if inPath[param.Name] {
+			param.In, param.Required = "path", true
+		}
+		params = append(params, param)
+	}
+	sort.SliceStable(params, func(i, j int) bool {
+		return params[i].In == "path" && params[j].In != "path"
+	})
+	return params
+}
+
+// fields the exported fields of t with the fields of embedded structs promoted
+func fields(t reflect.Type) []reflect.StructField {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	var result []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Tag.Get("json") == "" {
+			embedded := field.Type
+			for embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				result = append(result, fields(embedded)...)
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		result = append(result, field)
+	}
+	return result
+}
+
+// jsonName the key encoding/json writes field under, empty when it is skipped
+func jsonName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	options := strings.Split(tag, ",")
+	name := options[0]
+	if name == "" {
+		name = field.Name
+	}
+	omitempty := false
+	for _, option := range options[1:] {
+		if option == "omitempty" {
+			omitempty = true
+		}
+	}
+	return name, omitempty
+}
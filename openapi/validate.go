@@ -0,0 +1,121 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ValidateResponse check a json response body against the documented response of an operation,
+// every key the spec does not know and every required key missing is reported
+func (d *Document) ValidateResponse(method, path string, status int, body []byte) []string {
+	op := d.Operation(method, path)
+	if op == nil {
+		return []string{fmt.Sprintf("%s %s is not documented", method, path)}
+	}
+	response, ok := op.Responses[fmt.Sprint(status)]
+	if !ok {
+		return []string{fmt.Sprintf("%s %s does not document status %d", method, path, status)}
+	}
+	media, ok := response.Content["application/json"]
+	if !ok {
+		return nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return []string{err.Error()}
+	}
+	return d.Validate(media.Schema, value, "$")
+}
+
+// Validate check a decoded json value against schema
+func (d *Document) Validate(schema *Schema, value interface{}, at string) []string {
+	nullable := schema != nil && schema.Nullable
+	schema = d.Resolve(schema)
+	if schema == nil || (schema.Type == "" && schema.Properties == nil) {
+		return nil
+	}
+	if value == nil {
+		if nullable || schema.Nullable {
+			return nil
+		}
+		// encoding/json writes nil slices and maps as null
+		if schema.Type == "array" || schema.AdditionalProperties != nil {
+			return nil
+		}
+		return []string{at + ": null where " + schema.Type + " is expected"}
+	}
+
+	var problems []string
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s: %T where object is expected", at, value)}
+		}
+		if schema.AdditionalProperties != nil {
+			for key, v := range object {
+				problems = append(problems, d.Validate(schema.AdditionalProperties, v, at+"."+key)...)
+			}
+			break
+		}
+		for _, key := range schema.Required {
+			if _, ok := object[key]; !ok {
+				problems = append(problems, at+"."+key+": missing")
+			}
+		}
+		keys := make([]string, 0, len(object))
+		for key := range object {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			property, ok := schema.Properties[key]
+			if !ok {
+				problems = append(problems, fmt.Sprintf("%s.%q: not in the spec", at, key))
+				continue
+			}
+			problems = append(problems, d.Validate(property, object[key], at+"."+key)...)
+		}
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s: %T where array is expected", at, value)}
+		}
+		for i, v := range array {
+			problems = append(problems, d.Validate(schema.Items, v, fmt.Sprintf("%s[%d]", at, i))...)
+		}
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return []string{fmt.Sprintf("%s: %T where string is expected", at, value)}
+		}
+		if len(schema.Enum) > 0 && !contains(schema.Enum, s) {
+			problems = append(problems, fmt.Sprintf("%s: %q is not one of %s", at, s, strings.Join(schema.Enum, ", ")))
+		}
+	case "integer":
+		n, ok := value.(float64)
+		if !ok || n != float64(int64(n)) {
+			return []string{fmt.Sprintf("%s: %v where integer is expected", at, value)}
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			return []string{fmt.Sprintf("%s: %T where number is expected", at, value)}
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return []string{fmt.Sprintf("%s: %T where boolean is expected", at, value)}
+		}
+	}
+	return problems
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package rankings
+
+import (
+	"context"
+	"openseasync/config"
+	"openseasync/logs"
+	"openseasync/models"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const defaultRefreshInterval = 10 * time.Minute
+
+// Run refresh the cached collection rankings of every window until ctx is done
+func Run(ctx context.Context) {
+	interval := defaultRefreshInterval
+	if seconds := config.GetConfig().Rankings.RefreshInterval; seconds > 0 {
+		interval = time.Duration(seconds) * time.Second
+	}
+	ctx = logs.WithFields(ctx, logrus.Fields{"component": "rankings"})
+
+	for {
+		RefreshOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// RefreshOnce refresh the rankings of every window, a failing window does not stop the others
+func RefreshOnce(ctx context.Context) {
+	for window := range models.RankingWindows {
+		start := time.Now()
+		if err := models.RefreshCollectionRankings(ctx, window); err != nil {
+			logs.FromContext(ctx).WithField("window", window).Error(err)
+			continue
+		}
+		logs.FromContext(ctx).WithFields(logrus.Fields{"window": window, "duration": time.Since(start).String()}).Debug("rankings refreshed")
+	}
+}
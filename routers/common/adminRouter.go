@@ -0,0 +1,245 @@
+package common
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"openseasync/chain"
+	"openseasync/common"
+	"openseasync/common/constants"
+	"openseasync/common/errorinfo"
+	"openseasync/config"
+	"openseasync/logs"
+	"openseasync/syncer"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+func AdminManager(router *gin.RouterGroup) {
+	router.Use(AdminAuth())
+	router.GET(constants.URL_ADMIN_SYNCS, GetWalletSyncs)
+	router.POST(constants.URL_ADMIN_SYNC, ForceSync)
+	router.DELETE(constants.URL_ADMIN_SYNC, CancelSync)
+	router.GET(constants.URL_ADMIN_SYNC_ERRORS, GetSyncErrors)
+	router.POST(constants.URL_ADMIN_PURGE, PurgeDeleted)
+	router.POST(constants.URL_ADMIN_RESTORE_ASSET, RestoreAsset)
+	router.POST(constants.URL_ADMIN_RESTORE_COLLECTION, RestoreCollection)
+	router.POST(constants.URL_ADMIN_RECOMPUTE_COLLECTION, RecomputeCollection)
+	router.POST(constants.URL_ADMIN_RECOMPUTE_RANKINGS, RecomputeRankings)
+	router.POST(constants.URL_ADMIN_RECOMPUTE_SUSPICIOUS, RecomputeSuspicious)
+	router.DELETE(constants.URL_ADMIN_WALLET, EraseWallet)
+	router.POST(constants.URL_ADMIN_WALLET_OPT_IN, OptInWallet)
+	router.GET(constants.URL_ADMIN_ERASURES, GetErasures)
+}
+
+// AdminAuth reject requests without the configured bearer token, every request when none is configured
+func AdminAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := config.GetConfig().Admin.Token
+		given := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if token == "" || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, common.CreateErrorResponse(errorinfo.ADMIN_UNAUTHORIZED_ERROR_CODE, errorinfo.ADMIN_UNAUTHORIZED_ERROR_MSG))
+			return
+		}
+		c.Next()
+	}
+}
+
+// GetWalletSyncs synced wallets, least recently refreshed first, with the status of their latest sync
+func GetWalletSyncs(c *gin.Context) {
+	var req SyncListRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+	result, err := getWalletSyncs(req.Network(), req.Page, req.PageSize)
+	if err != nil {
+		logs.FromContext(c.Request.Context()).Error(err)
+		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse(errorinfo.GET_RECORD_lIST_ERROR_CODE, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, common.CreateSuccessResponse(result["data"], result["metadata"]))
+}
+
+// ForceSync start a sync of a wallet without waiting for it
+func ForceSync(c *gin.Context) {
+	var req WalletRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+	run, err := forceSync(chain.NormalizeNetwork(req.Chain), req.Wallet())
+	if err == syncer.RUNNING_ERR {
+		c.JSON(http.StatusConflict, common.CreateErrorResponse(errorinfo.SYNC_RUNNING_ERROR_CODE, errorinfo.SYNC_RUNNING_ERROR_MSG))
+		return
+	} else if err == syncer.ERASED_ERR {
+		c.JSON(http.StatusForbidden, common.CreateErrorResponse(errorinfo.WALLET_ERASED_ERROR_CODE, errorinfo.WALLET_ERASED_ERROR_MSG))
+		return
+	} else if err != nil {
+		logs.FromContext(c.Request.Context()).Error(err)
+		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse(errorinfo.SAVE_DATA_TO_DB_ERROR_CODE, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, common.CreateSuccessResponse(run, nil))
+}
+
+// CancelSync stop the running sync of a wallet, it ends as cancelled
+func CancelSync(c *gin.Context) {
+	var req WalletRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+	run := syncer.Cancel(chain.NormalizeNetwork(req.Chain), req.Wallet())
+	if run == nil {
+		c.JSON(http.StatusNotFound, common.CreateErrorResponse(errorinfo.SYNC_NOT_RUNNING_ERROR_CODE, errorinfo.SYNC_NOT_RUNNING_ERROR_MSG))
+		return
+	}
+	c.JSON(http.StatusOK, common.CreateSuccessResponse(run, nil))
+}
+
+func GetSyncErrors(c *gin.Context) {
+	var req SyncListRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+	result, err := getSyncErrors(req.Network(), req.Page, req.PageSize)
+	if err != nil {
+		logs.FromContext(c.Request.Context()).Error(err)
+		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse(errorinfo.GET_RECORD_lIST_ERROR_CODE, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, common.CreateSuccessResponse(result["data"], result["metadata"]))
+}
+
+// PurgeDeleted remove the documents soft deleted more than days ago for good
+func PurgeDeleted(c *gin.Context) {
+	var req PurgeRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+	result, err := purgeDeleted(req.Network(), req.Days)
+	if err != nil {
+		logs.FromContext(c.Request.Context()).Error(err)
+		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse(errorinfo.UPDATE_DATA_TO_DB_ERROR_CODE, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, common.CreateSuccessResponse(result, nil))
+}
+
+func RestoreAsset(c *gin.Context) {
+	var req DeleteAssetRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+	restored, err := restoreAsset(req.Network(), strings.ToLower(req.User), strings.ToLower(req.ContractAddress), req.TokenId)
+	if err != nil {
+		logs.FromContext(c.Request.Context()).Error(err)
+		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse(errorinfo.UPDATE_DATA_TO_DB_ERROR_CODE, err.Error()))
+		return
+	}
+	if !restored {
+		c.JSON(http.StatusNotFound, common.CreateErrorResponse(errorinfo.DELETED_RECORD_NOT_FOUND_ERROR_CODE, errorinfo.DELETED_RECORD_NOT_FOUND_ERROR_MSG))
+		return
+	}
+	c.JSON(http.StatusOK, common.CreateSuccessResponse(nil, nil))
+}
+
+func RestoreCollection(c *gin.Context) {
+	var req DeleteCollectionRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+	restored, err := restoreCollection(req.Network(), strings.ToLower(req.User), req.Slug)
+	if err != nil {
+		logs.FromContext(c.Request.Context()).Error(err)
+		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse(errorinfo.UPDATE_DATA_TO_DB_ERROR_CODE, err.Error()))
+		return
+	}
+	if !restored {
+		c.JSON(http.StatusNotFound, common.CreateErrorResponse(errorinfo.DELETED_RECORD_NOT_FOUND_ERROR_CODE, errorinfo.DELETED_RECORD_NOT_FOUND_ERROR_MSG))
+		return
+	}
+	c.JSON(http.StatusOK, common.CreateSuccessResponse(nil, nil))
+}
+
+// RecomputeCollection derive the floor price of a collection from its assets again
+func RecomputeCollection(c *gin.Context) {
+	var req CollectionRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+	collection, err := recomputeCollection(c.Request.Context(), chain.NormalizeNetwork(req.Chain), req.CollectionId)
+	if err != nil {
+		logs.FromContext(c.Request.Context()).Error(err)
+		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse(errorinfo.UPDATE_DATA_TO_DB_ERROR_CODE, err.Error()))
+		return
+	}
+	if collection == nil {
+		c.JSON(http.StatusNotFound, common.CreateErrorResponse(errorinfo.COLLECTION_NOT_FOUND_ERROR_CODE, errorinfo.COLLECTION_NOT_FOUND_ERROR_MSG))
+		return
+	}
+	c.JSON(http.StatusOK, common.CreateSuccessResponse(collection, nil))
+}
+
+// RecomputeRankings refresh the collection rankings of every window
+func RecomputeRankings(c *gin.Context) {
+	recomputeRankings(c.Request.Context())
+	c.JSON(http.StatusOK, common.CreateSuccessResponse(nil, nil))
+}
+
+// RecomputeSuspicious flag the suspicious sales now rather than at the next interval
+func RecomputeSuspicious(c *gin.Context) {
+	result, err := recomputeSuspicious(c.Request.Context())
+	if err != nil {
+		logs.FromContext(c.Request.Context()).Error(err)
+		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse(errorinfo.UPDATE_DATA_TO_DB_ERROR_CODE, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, common.CreateSuccessResponse(result, nil))
+}
+
+// EraseWallet remove every document synced for a wallet on every chain and block syncing it again
+func EraseWallet(c *gin.Context) {
+	var req EraseWalletRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+	erasure, err := eraseWallet(strings.ToLower(req.User), req.Reason)
+	if err != nil {
+		logs.FromContext(c.Request.Context()).Error(err)
+		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse(errorinfo.UPDATE_DATA_TO_DB_ERROR_CODE, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, common.CreateSuccessResponse(erasure, nil))
+}
+
+// OptInWallet clear the sync block of an erased wallet
+func OptInWallet(c *gin.Context) {
+	var req OptInRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+	cleared, err := optInWallet(strings.ToLower(req.User))
+	if err != nil {
+		logs.FromContext(c.Request.Context()).Error(err)
+		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse(errorinfo.UPDATE_DATA_TO_DB_ERROR_CODE, err.Error()))
+		return
+	}
+	if !cleared {
+		c.JSON(http.StatusNotFound, common.CreateErrorResponse(errorinfo.WALLET_NOT_BLOCKED_ERROR_CODE, errorinfo.WALLET_NOT_BLOCKED_ERROR_MSG))
+		return
+	}
+	c.JSON(http.StatusOK, common.CreateSuccessResponse(nil, nil))
+}
+
+func GetErasures(c *gin.Context) {
+	var req DefaultPageQuery
+	if !bindRequest(c, &req) {
+		return
+	}
+	result, err := getErasures(req.Page, req.PageSize)
+	if err != nil {
+		logs.FromContext(c.Request.Context()).Error(err)
+		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse(errorinfo.GET_RECORD_lIST_ERROR_CODE, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, common.CreateSuccessResponse(result["data"], result["metadata"]))
+}
@@ -0,0 +1,127 @@
+package common
+
+import (
+	"context"
+	"openseasync/cache"
+	"openseasync/logs"
+	"openseasync/models"
+	"openseasync/rankings"
+	"openseasync/syncer"
+	"openseasync/washtrade"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// PurgeResult how many soft deleted documents were removed by collection
+type PurgeResult struct {
+	Before  int64            `json:"before"` // unix milli, documents deleted earlier were removed
+	Deleted map[string]int64 `json:"deleted"`
+}
+
+// SuspiciousResult how many sales had their suspicious flag changed
+type SuspiciousResult struct {
+	Changed int64 `json:"changed"`
+}
+
+// getWalletSyncs the synced wallets with their latest run
+func getWalletSyncs(network string, page, pageSize int64) (map[string]interface{}, error) {
+	return models.FindWalletSyncs(network, page, pageSize)
+}
+
+// getSyncErrors the failed runs, most recent first
+func getSyncErrors(network string, page, pageSize int64) (map[string]interface{}, error) {
+	return models.FindSyncErrors(network, page, pageSize)
+}
+
+// forceSync start a sync of a wallet in the background, syncer.RUNNING_ERR when one is already running
+func forceSync(network, user string) (*models.SyncRun, error) {
+	ctx := logs.WithFields(context.Background(), logrus.Fields{"wallet": user, "chain": network})
+	ctx, run, err := syncer.Start(ctx, network, user, models.SYNC_TRIGGER_ADMIN)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		if err := syncer.Run(ctx, run); err != nil {
+			logs.FromContext(ctx).Error(err)
+		}
+	}()
+	return run, nil
+}
+
+// purgeDeleted remove the documents soft deleted more than days ago
+func purgeDeleted(network string, days int64) (*PurgeResult, error) {
+	before := time.Now().Add(-time.Duration(days) * 24 * time.Hour).UnixMilli()
+	deleted, err := models.PurgeDeleted(network, before)
+	if err != nil {
+		return nil, err
+	}
+	return &PurgeResult{Before: before, Deleted: deleted}, nil
+}
+
+// restoreAsset undo the deletion of an asset of a wallet, false when there is no such deleted asset
+func restoreAsset(network, user, contractAddress, tokenID string) (bool, error) {
+	restored, err := models.RestoreAsset(network, user, contractAddress, tokenID)
+	if err != nil || !restored {
+		return restored, err
+	}
+	cache.Invalidate(cache.Tag(cache.TAG_WALLET, user))
+	return true, nil
+}
+
+// restoreCollection undo the deletion of a collection of a wallet, false when there is no such deleted collection
+func restoreCollection(network, user, slug string) (bool, error) {
+	restored, err := models.RestoreCollection(network, user, slug)
+	if err != nil || !restored {
+		return restored, err
+	}
+	cache.Invalidate(cache.Tag(cache.TAG_WALLET, user), cache.Tag(cache.TAG_COLLECTION, slug))
+	return true, nil
+}
+
+// recomputeCollection derive the floor price of a collection from its assets again, nil when there is no such collection
+func recomputeCollection(ctx context.Context, network, collectionId string) (*models.Collection, error) {
+	return models.RecomputeCollectionFloorPrice(ctx, network, collectionId)
+}
+
+// recomputeRankings refresh the cached collection rankings now rather than at the next interval
+func recomputeRankings(ctx context.Context) {
+	rankings.RefreshOnce(ctx)
+}
+
+// recomputeSuspicious flag the suspicious sales now, the rankings are refreshed when a flag changed
+func recomputeSuspicious(ctx context.Context) (*SuspiciousResult, error) {
+	changed, err := washtrade.AnalyzeOnce(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if changed > 0 {
+		rankings.RefreshOnce(ctx)
+	}
+	return &SuspiciousResult{Changed: changed}, nil
+}
+
+// eraseWallet remove the data of a wallet on every chain, its running syncs are cancelled and new ones refused
+func eraseWallet(user, reason string) (*models.Erasure, error) {
+	erasure := &models.Erasure{
+		Id:         uuid.New().String(),
+		Reason:     reason,
+		CreateDate: time.Now().UnixMilli(),
+	}
+	if err := models.EraseWallet(erasure, user, func() { syncer.CancelWallet(user) }); err != nil {
+		return nil, err
+	}
+	cache.Invalidate(cache.Tag(cache.TAG_WALLET, user))
+	return erasure, nil
+}
+
+// optInWallet let an erased wallet be synced again, false when it is not blocked
+func optInWallet(user string) (bool, error) {
+	return models.ClearSyncBlock(user)
+}
+
+// getErasures the erasure audit records, most recent first
+func getErasures(page, pageSize int64) (map[string]interface{}, error) {
+	return models.FindErasures(page, pageSize)
+}
@@ -1,32 +1,44 @@
 package common
 
 import (
-	"fmt"
 	"net/http"
+	"openseasync/cache"
+	"openseasync/chain"
 	"openseasync/common"
 	"openseasync/common/constants"
 	"openseasync/common/errorinfo"
 	"openseasync/logs"
 	"openseasync/models"
-	"strconv"
+	"openseasync/syncer"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
 )
 
+// INCLUDE_HISTORY include value of the order routes adding the filled, cancelled and expired orders
+const INCLUDE_HISTORY = "history"
+
 func HostManager(router *gin.RouterGroup) {
 	router.GET(constants.URL_HOST_GET_HOST_INFO, GetSwanMinerVersion)
 	router.GET(constants.URL_OPENSEA_OWNER_ASSETS_SYNC, OpenSeaOwnerDataSync)
-	router.GET(constants.URL_FIND_ASSETS_COLLETION_SEARCH, GetAssetsSearchByOwner)
-	router.GET(constants.URL_FIND_ASSETS_COLLECTIBLESID, GetAssetGeneralInfoByCollectibleId)
-	router.GET(constants.URL_FIND_COLLECTION_USERMETAMASKID, GetCollectionsByUserMetamaskID)
-	router.GET(constants.URL_FIND_COLLECTION_COLLECTIONID, GetCollectionsByCollectionID)
-	router.GET(constants.URL_FIND_COLLECTION_ITEM_ACTIVITY_COLLECTIONID, GetItemActivityByCollectionID)
-	router.GET(constants.URL_FIND_USER_SOCIALMEDIA, GetUserMediaByUserId)
-	router.GET(constants.URL_FIND_TRADE_HISTORY, GeTradeHistoryByCollectibleId)
-	router.GET(constants.URL_FIND_ASSETS_OFFERRECORDS, GetAssetOfferRecordsByCollectibleId)
-	router.GET(constants.URL_FIND_ASSETS_HIGHESTPRICE, GetOrdersHighestPriceByCollectibleId)
-	router.GET(constants.URL_FIND_ASSETS_OTTHER, GetAssetOtherByCollection)
+	router.GET(constants.URL_FIND_ASSETS_COLLETION_SEARCH, cache.Handler("searchCollectionAssets", 30*time.Second, cache.Param(cache.TAG_COLLECTION, "collectionId")), GetAssetsSearchByOwner)
+	router.GET(constants.URL_FIND_ASSETS_COLLECTIBLESID, cache.Handler("getAsset", time.Minute, cache.Param(cache.TAG_ASSET, "collectibleId")), GetAssetGeneralInfoByCollectibleId)
+	router.GET(constants.URL_FIND_COLLECTION_USERMETAMASKID, cache.Handler("getWalletCollections", time.Minute, cache.Param(cache.TAG_WALLET, "usermetamaskid")), GetCollectionsByUserMetamaskID)
+	router.GET(constants.URL_FIND_COLLECTION_COLLECTIONID, cache.Handler("getCollection", time.Minute, cache.Param(cache.TAG_COLLECTION, "collectionId")), GetCollectionsByCollectionID)
+	router.GET(constants.URL_FIND_COLLECTION_ITEM_ACTIVITY_COLLECTIONID, cache.Handler("getCollectionActivities", 30*time.Second, cache.Param(cache.TAG_COLLECTION, "collectionId")), GetItemActivityByCollectionID)
+	router.GET(constants.URL_FIND_COLLECTION_HOLDERS, cache.Handler("getCollectionHolders", time.Minute, cache.Param(cache.TAG_COLLECTION, "collectionId")), GetCollectionHolders)
+	// rankings are refreshed by a background job, only the ttl bounds their age
+	router.GET(constants.URL_COLLECTION_RANKINGS, cache.Handler("getCollectionRankings", 5*time.Minute), GetCollectionRankings)
+	router.GET(constants.URL_SEARCH, Search)
+	router.GET(constants.URL_FIND_USER_SOCIALMEDIA, cache.Handler("getUser", 5*time.Minute, cache.Param(cache.TAG_WALLET, "userMetamaskId")), GetUserMediaByUserId)
+	router.GET(constants.URL_FIND_TRADE_HISTORY, cache.Handler("getAssetTradeHistory", 30*time.Second, cache.Param(cache.TAG_ASSET, "collectibleId")), GeTradeHistoryByCollectibleId)
+	router.GET(constants.URL_FIND_ASSETS_OFFERRECORDS, cache.Handler("getAssetOffers", 30*time.Second, cache.Param(cache.TAG_ASSET, "collectibleId")), GetAssetOfferRecordsByCollectibleId)
+	router.GET(constants.URL_FIND_ASSETS_HIGHESTPRICE, cache.Handler("getAssetHighestBid", 15*time.Second, cache.Param(cache.TAG_ASSET, "collectibleId")), GetOrdersHighestPriceByCollectibleId)
+	// siblings change with the collection, which the path does not name, keep them briefly
+	router.GET(constants.URL_FIND_ASSETS_OTTHER, cache.Handler("getAssetSiblings", 30*time.Second, cache.Param(cache.TAG_ASSET, "collectibleId")), GetAssetOtherByCollection)
+	router.GET(constants.URL_FIND_ASSETS_CHANGES, cache.Handler("getAssetChanges", time.Minute, cache.Param(cache.TAG_ASSET, "collectibleId")), GetAssetChangesByCollectibleId)
 	router.DELETE(constants.URL_DELETE_ASSET, DeleteAssetByTokenID)
 	router.DELETE(constants.URL_DELETE_COLLECTION, DeleteCollectionByCollectionId)
 
@@ -39,22 +51,22 @@ func GetSwanMinerVersion(c *gin.Context) {
 
 // sync opensea assets and collections
 func OpenSeaOwnerDataSync(c *gin.Context) {
-	user := strings.ToLower(c.Param("user"))
-	if len(user) != 42 {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse(errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_CODE, errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_MSG))
+	var req WalletRequest
+	if !bindRequest(c, &req) {
 		return
 	}
+	user, network := req.Wallet(), chain.NormalizeNetwork(req.Chain)
 
-	// sync collections
-	if err := openSeaOwnerCollectionsSync(user); err != nil {
-		logs.GetLogger().Error(err)
-		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse(errorinfo.OPENSEA_HTTP_REQUEST_ERROR_CODE, err.Error()))
+	ctx := logs.WithFields(c.Request.Context(), logrus.Fields{"wallet": user, "chain": network})
+	// sync collections then assets
+	if err := syncer.Wallet(ctx, network, user, models.SYNC_TRIGGER_API); err == syncer.RUNNING_ERR {
+		c.JSON(http.StatusConflict, common.CreateErrorResponse(errorinfo.SYNC_RUNNING_ERROR_CODE, errorinfo.SYNC_RUNNING_ERROR_MSG))
 		return
-	}
-
-	// sync assets
-	if err := openSeaOwnerAssetsSync(user); err != nil {
-		logs.GetLogger().Error(err)
+	} else if err == syncer.ERASED_ERR {
+		c.JSON(http.StatusForbidden, common.CreateErrorResponse(errorinfo.WALLET_ERASED_ERROR_CODE, errorinfo.WALLET_ERASED_ERROR_MSG))
+		return
+	} else if err != nil {
+		logs.FromContext(ctx).Error(err)
 		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse(errorinfo.OPENSEA_HTTP_REQUEST_ERROR_CODE, err.Error()))
 		return
 	}
@@ -63,18 +75,14 @@ func OpenSeaOwnerDataSync(c *gin.Context) {
 }
 
 func GetAssetsSearchByOwner(c *gin.Context) {
-	var param models.Params
-	collectionId := c.Param("collectionId")
-
-	if err := c.ShouldBindQuery(&param); err != nil {
-		fmt.Println(param)
-		fmt.Println(err)
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse(errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_CODE, errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_MSG))
+	var req AssetSearchRequest
+	if !bindRequest(c, &req) {
 		return
 	}
-	result, err := getAssetSearchByOwner(collectionId, param)
+	req.Params.Chain = strings.ToLower(strings.TrimSpace(req.Params.Chain))
+	result, err := getAssetSearchByOwner(req.CollectionId, req.Params)
 	if err != nil {
-		logs.GetLogger().Error(err)
+		logs.FromContext(c.Request.Context()).Error(err)
 		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse(errorinfo.GET_RECORD_lIST_ERROR_CODE, err.Error()))
 		return
 	}
@@ -82,133 +90,150 @@ func GetAssetsSearchByOwner(c *gin.Context) {
 }
 
 func GetAssetGeneralInfoByCollectibleId(c *gin.Context) {
-	collectibleId := c.Param("collectibleId")
-
-	if collectibleId == "" {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse(errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_CODE, errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_MSG))
-		return
-	}
-	intCollectibleId, err := strconv.ParseInt(collectibleId, 10, 64)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse(errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_CODE, errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_MSG))
+	var req CollectibleRequest
+	if !bindRequest(c, &req) {
 		return
 	}
-	if intCollectibleId < 1 {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse(errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_CODE, errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_MSG))
-		return
-	}
-	result, err := getAssetGeneralInfoByCollectibleId(intCollectibleId)
+	result, err := getAssetGeneralInfoByCollectibleId(req.Network(), req.CollectibleId)
 	if err != nil {
-		logs.GetLogger().Error(err)
+		logs.FromContext(c.Request.Context()).Error(err)
 		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse(errorinfo.GET_RECORD_lIST_ERROR_CODE, err.Error()))
 		return
 	}
 	c.JSON(http.StatusOK, common.CreateSuccessResponse(result["data"], result["metadata"]))
 }
 func GetAssetOtherByCollection(c *gin.Context) {
-	collectibleId := c.Param("collectibleId")
-
-	if collectibleId == "" {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse(errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_CODE, errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_MSG))
+	var req CollectibleRequest
+	if !bindRequest(c, &req) {
 		return
 	}
-	intCollectibleId, err := strconv.ParseInt(collectibleId, 10, 64)
+	result, err := getAssetOtherByCollection(req.Network(), req.CollectibleId)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse(errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_CODE, errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_MSG))
+		logs.FromContext(c.Request.Context()).Error(err)
+		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse(errorinfo.GET_RECORD_lIST_ERROR_CODE, err.Error()))
 		return
 	}
-	if intCollectibleId < 1 {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse(errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_CODE, errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_MSG))
+	c.JSON(http.StatusOK, common.CreateSuccessResponse(result["data"], nil))
+}
+
+func GetOrdersHighestPriceByCollectibleId(c *gin.Context) {
+	var req OrdersRequest
+	if !bindRequest(c, &req) {
 		return
 	}
-	result, err := getAssetOtherByCollection(intCollectibleId)
+	result, err := getOrdersHighestPriceByCollectibleId(req.Network(), req.CollectibleId, req.History())
 	if err != nil {
-		logs.GetLogger().Error(err)
+		logs.FromContext(c.Request.Context()).Error(err)
 		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse(errorinfo.GET_RECORD_lIST_ERROR_CODE, err.Error()))
 		return
 	}
-	c.JSON(http.StatusOK, common.CreateSuccessResponse(result["data"], nil))
+	c.JSON(http.StatusOK, common.CreateSuccessResponse(result, nil))
 }
 
-func GetOrdersHighestPriceByCollectibleId(c *gin.Context) {
-	collectibleId := c.Param("collectibleId")
-	if collectibleId == "" {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse(errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_CODE, errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_MSG))
+func GetCollectionsByUserMetamaskID(c *gin.Context) {
+	var req UserCollectionsRequest
+	if !bindRequest(c, &req) {
 		return
 	}
-	intCollectibleId, err := strconv.ParseInt(collectibleId, 10, 64)
+	result, err := getCollectionsByUserMetamaskID(req.Network(), strings.ToLower(req.UserMetamaskId), req.Page, req.PageSize)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse(errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_CODE, errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_MSG))
+		logs.FromContext(c.Request.Context()).Error(err)
+		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse(errorinfo.GET_RECORD_lIST_ERROR_CODE, err.Error()))
 		return
 	}
-	if intCollectibleId < 1 {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse(errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_CODE, errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_MSG))
+	c.JSON(http.StatusOK, common.CreateSuccessResponse(result["data"], result["metadata"]))
+}
+
+func GetCollectionsByCollectionID(c *gin.Context) {
+	var req CollectionRequest
+	if !bindRequest(c, &req) {
 		return
 	}
-	result, err := getOrdersHighestPriceByCollectibleId(intCollectibleId)
+	result, err := getCollectionsByCollectionID(req.Network(), req.CollectionId)
 	if err != nil {
-		logs.GetLogger().Error(err)
+		logs.FromContext(c.Request.Context()).Error(err)
 		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse(errorinfo.GET_RECORD_lIST_ERROR_CODE, err.Error()))
 		return
 	}
-	c.JSON(http.StatusOK, common.CreateSuccessResponse(result, nil))
+	c.JSON(http.StatusOK, common.CreateSuccessResponse(result["data"], nil))
 }
 
-func GetCollectionsByUserMetamaskID(c *gin.Context) {
-	usermetamaskid := strings.ToLower(c.Param("usermetamaskid"))
-	page := c.Query("page")
-	pageSize := c.Query("pageSize")
-	if len(usermetamaskid) != 42 {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse(errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_CODE, errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_MSG))
+// GetCollectionHolders top holders, holding distribution and churn of a collection from its synced assets
+func GetCollectionHolders(c *gin.Context) {
+	var req HoldersRequest
+	if !bindRequest(c, &req) {
 		return
 	}
-	pageInt, err := strconv.ParseInt(page, 10, 64)
+	result, err := getHolderAnalytics(req.Network(), req.CollectionId, req.Top)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse(errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_CODE, errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_MSG))
+		logs.FromContext(c.Request.Context()).Error(err)
+		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse(errorinfo.GET_RECORD_lIST_ERROR_CODE, err.Error()))
 		return
 	}
-	pageSizeInt, err := strconv.ParseInt(pageSize, 10, 64)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse(errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_CODE, errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_MSG))
+	if result == nil {
+		c.JSON(http.StatusNotFound, common.CreateErrorResponse(errorinfo.COLLECTION_NOT_FOUND_ERROR_CODE, errorinfo.COLLECTION_NOT_FOUND_ERROR_MSG))
 		return
 	}
-	if pageInt < 1 || pageSizeInt < 1 {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse(errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_CODE, errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_MSG))
+	c.JSON(http.StatusOK, common.CreateSuccessResponse(result, nil))
+}
+
+// GetCollectionRankings collections ranked by volume, sales or floor change over 1d, 7d or 30d
+func GetCollectionRankings(c *gin.Context) {
+	var req RankingsRequest
+	if !bindRequest(c, &req) {
 		return
 	}
-	result, err := getCollectionsByUserMetamaskID(usermetamaskid, pageInt, pageSizeInt)
+	result, err := getCollectionRankings(req.Network(), req.Metric, req.Window, req.Page, req.PageSize)
 	if err != nil {
-		logs.GetLogger().Error(err)
+		logs.FromContext(c.Request.Context()).Error(err)
 		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse(errorinfo.GET_RECORD_lIST_ERROR_CODE, err.Error()))
 		return
 	}
 	c.JSON(http.StatusOK, common.CreateSuccessResponse(result["data"], result["metadata"]))
 }
 
-func GetCollectionsByCollectionID(c *gin.Context) {
-	collectionId := c.Param("collectionId")
-	if collectionId == "" {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse(errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_CODE, errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_MSG))
-		return
-	}
-	result, err := getCollectionsByCollectionID(collectionId)
+// Search full text search of assets, collections and users, best matches of each type first
+func Search(c *gin.Context) {
+	var req SearchRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+	q := strings.TrimSpace(req.Q)
+	if q == "" {
+		c.JSON(http.StatusBadRequest, common.CreateValidationErrorResponse(errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_CODE, errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_MSG,
+			[]common.FieldError{{Field: "q", Message: "is required"}}))
+		return
+	}
+	types := map[string]bool{models.SEARCH_TYPE_ASSET: true, models.SEARCH_TYPE_COLLECTION: true, models.SEARCH_TYPE_USER: true}
+	if req.Type != "" {
+		types = make(map[string]bool)
+		for _, v := range strings.Split(req.Type, ",") {
+			v = strings.TrimSpace(v)
+			if v != models.SEARCH_TYPE_ASSET && v != models.SEARCH_TYPE_COLLECTION && v != models.SEARCH_TYPE_USER {
+				c.JSON(http.StatusBadRequest, common.CreateValidationErrorResponse(errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_CODE, errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_MSG,
+					[]common.FieldError{{Field: "type", Message: "must be asset, collection or user, comma separated"}}))
+				return
+			}
+			types[v] = true
+		}
+	}
+	result, err := search(req.Network(), q, types, req.Limit)
 	if err != nil {
-		logs.GetLogger().Error(err)
+		logs.FromContext(c.Request.Context()).Error(err)
 		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse(errorinfo.GET_RECORD_lIST_ERROR_CODE, err.Error()))
 		return
 	}
-	c.JSON(http.StatusOK, common.CreateSuccessResponse(result["data"], nil))
+	c.JSON(http.StatusOK, common.CreateSuccessResponse(result, nil))
 }
 
 func GetUserMediaByUserId(c *gin.Context) {
-	userId := strings.ToLower(c.Param("userMetamaskId"))
-	if userId == "" {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse(errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_CODE, errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_MSG))
+	var req UserMediaRequest
+	if !bindRequest(c, &req) {
 		return
 	}
-	result, err := getUserMediaByUserId(userId)
+	result, err := getUserMediaByUserId(strings.ToLower(req.UserMetamaskId))
 	if err != nil {
-		logs.GetLogger().Error(err)
+		logs.FromContext(c.Request.Context()).Error(err)
 		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse(errorinfo.GET_RECORD_lIST_ERROR_CODE, err.Error()))
 		return
 	}
@@ -216,23 +241,13 @@ func GetUserMediaByUserId(c *gin.Context) {
 }
 
 func GetAssetOfferRecordsByCollectibleId(c *gin.Context) {
-	collectibleId := c.Param("collectibleId")
-	if collectibleId == "" {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse(errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_CODE, errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_MSG))
+	var req OrdersRequest
+	if !bindRequest(c, &req) {
 		return
 	}
-	collectibleIdInt, err := strconv.ParseInt(collectibleId, 10, 64)
+	result, err := getAssetOfferRecordsByCollectibleId(req.Network(), req.CollectibleId, req.History())
 	if err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse(errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_CODE, errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_MSG))
-		return
-	}
-	if collectibleIdInt < 1 {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse(errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_CODE, errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_MSG))
-		return
-	}
-	result, err := getAssetOfferRecordsByCollectibleId(collectibleIdInt)
-	if err != nil {
-		logs.GetLogger().Error(err)
+		logs.FromContext(c.Request.Context()).Error(err)
 		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse(errorinfo.GET_RECORD_lIST_ERROR_CODE, err.Error()))
 		return
 	}
@@ -240,30 +255,13 @@ func GetAssetOfferRecordsByCollectibleId(c *gin.Context) {
 }
 
 func GetItemActivityByCollectionID(c *gin.Context) {
-	collectionId := c.Param("collectionId")
-	page := c.Query("page")
-	pageSize := c.Query("pageSize")
-	if collectionId == "" {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse(errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_CODE, errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_MSG))
-		return
-	}
-	pageInt, err := strconv.ParseInt(page, 10, 64)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse(errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_CODE, errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_MSG))
-		return
-	}
-	pageSizeInt, err := strconv.ParseInt(pageSize, 10, 64)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse(errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_CODE, errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_MSG))
+	var req CollectionPageRequest
+	if !bindRequest(c, &req) {
 		return
 	}
-	if pageInt < 1 || pageSizeInt < 1 {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse(errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_CODE, errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_MSG))
-		return
-	}
-	result, err := getItemActivityByCollectionId(collectionId, pageInt, pageSizeInt)
+	result, err := getItemActivityByCollectionId(req.Network(), req.CollectionId, req.Page, req.PageSize)
 	if err != nil {
-		logs.GetLogger().Error(err)
+		logs.FromContext(c.Request.Context()).Error(err)
 		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse(errorinfo.GET_RECORD_lIST_ERROR_CODE, err.Error()))
 		return
 	}
@@ -271,39 +269,28 @@ func GetItemActivityByCollectionID(c *gin.Context) {
 }
 
 func GeTradeHistoryByCollectibleId(c *gin.Context) {
-	collectibleId := c.Param("collectibleId")
-	page := c.Query("page")
-	pageSize := c.Query("pageSize")
-	if collectibleId == "" {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse(errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_CODE, errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_MSG))
+	var req CollectiblePageRequest
+	if !bindRequest(c, &req) {
 		return
 	}
-	intCollectibleId, err := strconv.ParseInt(collectibleId, 10, 64)
+	result, err := getTradeHistoryByCollectibleId(req.Network(), req.CollectibleId, req.Page, req.PageSize)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse(errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_CODE, errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_MSG))
-		return
-	}
-	if intCollectibleId < 1 {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse(errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_CODE, errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_MSG))
-		return
-	}
-	pageInt, err := strconv.ParseInt(page, 10, 64)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse(errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_CODE, errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_MSG))
-		return
-	}
-	pageSizeInt, err := strconv.ParseInt(pageSize, 10, 64)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse(errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_CODE, errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_MSG))
+		logs.FromContext(c.Request.Context()).Error(err)
+		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse(errorinfo.GET_RECORD_lIST_ERROR_CODE, err.Error()))
 		return
 	}
-	if pageInt < 1 || pageSizeInt < 1 {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse(errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_CODE, errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_MSG))
+	c.JSON(http.StatusOK, common.CreateSuccessResponse(result["data"], result["metadata"]))
+}
+
+// GetAssetChangesByCollectibleId fields changed by each sync of an asset
+func GetAssetChangesByCollectibleId(c *gin.Context) {
+	var req CollectiblePageRequest
+	if !bindRequest(c, &req) {
 		return
 	}
-	result, err := getTradeHistoryByCollectibleId(intCollectibleId, pageInt, pageSizeInt)
+	result, err := getAssetChangesByCollectibleId(req.Network(), req.CollectibleId, req.Page, req.PageSize)
 	if err != nil {
-		logs.GetLogger().Error(err)
+		logs.FromContext(c.Request.Context()).Error(err)
 		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse(errorinfo.GET_RECORD_lIST_ERROR_CODE, err.Error()))
 		return
 	}
@@ -311,17 +298,13 @@ func GeTradeHistoryByCollectibleId(c *gin.Context) {
 }
 
 func DeleteAssetByTokenID(c *gin.Context) {
-	user := c.Param("user")
-	contractAddress := c.Param("contract_address")
-	tokenID := c.Param("token_id")
-
-	if len(user) != 42 && len(contractAddress) != 42 && tokenID != "" {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse(errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_CODE, errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_MSG))
+	var req DeleteAssetRequest
+	if !bindRequest(c, &req) {
 		return
 	}
-	err := deleteAssetByTokenID(user, contractAddress, tokenID)
+	err := deleteAssetByTokenID(req.Network(), strings.ToLower(req.User), strings.ToLower(req.ContractAddress), req.TokenId)
 	if err != nil {
-		logs.GetLogger().Error(err)
+		logs.FromContext(c.Request.Context()).Error(err)
 		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse(errorinfo.UPDATE_DATA_TO_DB_ERROR_CODE, err.Error()))
 		return
 	}
@@ -329,19 +312,17 @@ func DeleteAssetByTokenID(c *gin.Context) {
 }
 
 func DeleteCollectionByCollectionId(c *gin.Context) {
-	user := c.Param("user")
-	slug := c.Param("slug")
-	if len(user) != 42 && slug != "" {
-		c.JSON(http.StatusBadRequest, common.CreateErrorResponse(errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_CODE, errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_MSG))
+	var req DeleteCollectionRequest
+	if !bindRequest(c, &req) {
 		return
 	}
-	err := deleteCollectionByCollectionId(user, slug)
+	err := deleteCollectionByCollectionId(req.Network(), strings.ToLower(req.User), req.Slug)
 	if err == models.CONNOT_DELETE_COLLECTION_ERR {
-		c.JSON(http.StatusOK, common.CreateErrorResponse(errorinfo.UPDATE_DATA_TO_DB_ERROR_CODE, err.Error()))
+		c.JSON(http.StatusConflict, common.CreateErrorResponse(errorinfo.UPDATE_DATA_TO_DB_ERROR_CODE, err.Error()))
 		return
 	} else if err != nil {
-		logs.GetLogger().Error(err)
-		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse(errorinfo.GET_RECORD_lIST_ERROR_CODE, err.Error()))
+		logs.FromContext(c.Request.Context()).Error(err)
+		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse(errorinfo.UPDATE_DATA_TO_DB_ERROR_CODE, err.Error()))
 		return
 	}
 	c.JSON(http.StatusOK, common.CreateSuccessResponse(nil, nil))
@@ -1,15 +1,11 @@
 package common
 
 import (
-	"encoding/json"
+	"openseasync/cache"
 	"openseasync/common"
-	"openseasync/common/utils"
 	"openseasync/logs"
 	"openseasync/models"
 	"runtime"
-	"time"
-
-	"go.mongodb.org/mongo-driver/bson"
 )
 
 func getSwanMinerHostInfo() *common.HostInfo {
@@ -21,69 +17,6 @@ func getSwanMinerHostInfo() *common.HostInfo {
 	return info
 }
 
-// openSeaOwnerAssetsSync get all assets by owner
-func openSeaOwnerAssetsSync(user string) error {
-	var n int64 = 1
-	refreshTime := time.Now().UnixMilli()
-	for {
-		time.Sleep(time.Second * 2)
-		// If the number of requests is too many, a 429 error code will be thrown
-		content, err := utils.RequestOpenSeaAssets(user, 50*(n-1), 50)
-		if err != nil {
-			logs.GetLogger().Error(err)
-			return err
-		}
-		var assets models.OwnerAsset
-		if err = json.Unmarshal(content, &assets); err != nil {
-			logs.GetLogger().Error(err)
-			return err
-		}
-		if len(assets.Assets) < 1 {
-			break
-		}
-		if err = models.InsertOpenSeaAsset(&assets, user, refreshTime); err != nil {
-			logs.GetLogger().Error(err)
-			return err
-		}
-		if len(assets.Assets) < 50 {
-			break
-		}
-		n++
-
-	}
-
-	return nil
-}
-
-// openSeaOwnerCollectionsSync get all collections by owner
-func openSeaOwnerCollectionsSync(user string) error {
-	var n int64 = 1
-	refreshTime := time.Now().UnixMilli()
-	for {
-
-		content, err := utils.RequestOpenSeaCollections(user, 300*(n-1), 300*n)
-		if err != nil {
-			logs.GetLogger().Error(err)
-			return err
-		}
-		var collections models.OwnerCollection
-		if err = json.Unmarshal(content, &collections.Collections); err != nil {
-			logs.GetLogger().Error(err)
-			return err
-		}
-		if err = models.InsertOpenSeaCollection(&collections, user, refreshTime); err != nil {
-			return err
-		}
-		if len(collections.Collections) < 300 {
-			break
-		}
-		n++
-		time.Sleep(time.Second)
-	}
-
-	return nil
-}
-
 // getAssetSearchByOwner get assets by owner
 func getAssetSearchByOwner(collectionId string, param models.Params) (map[string]interface{}, error) {
 	result, err := models.FindAssetSearchByOwner(collectionId, param)
@@ -95,8 +28,8 @@ func getAssetSearchByOwner(collectionId string, param models.Params) (map[string
 }
 
 // getAssetGeneralInfoByCollectibleId get assets by collectibleId
-func getAssetGeneralInfoByCollectibleId(collectibleId int64) (map[string]interface{}, error) {
-	result, err := models.FindAssetByGeneralInfoCollectibleId(collectibleId)
+func getAssetGeneralInfoByCollectibleId(network string, collectibleId int64) (map[string]interface{}, error) {
+	result, err := models.FindAssetByGeneralInfoCollectibleId(network, collectibleId)
 	if err != nil {
 		logs.GetLogger().Error(err)
 		return nil, err
@@ -105,8 +38,8 @@ func getAssetGeneralInfoByCollectibleId(collectibleId int64) (map[string]interfa
 }
 
 // getAssetOtherByCollection get assets other by collectibleId
-func getAssetOtherByCollection(collectibleId int64) (map[string]interface{}, error) {
-	result, err := models.FindAssetOtherByCollection(collectibleId)
+func getAssetOtherByCollection(network string, collectibleId int64) (map[string]interface{}, error) {
+	result, err := models.FindAssetOtherByCollection(network, collectibleId)
 	if err != nil {
 		logs.GetLogger().Error(err)
 		return nil, err
@@ -115,8 +48,8 @@ func getAssetOtherByCollection(collectibleId int64) (map[string]interface{}, err
 }
 
 // getOrdersHighestPriceByCollectibleId find highest price by collectibled
-func getOrdersHighestPriceByCollectibleId(collectibleId int64) (interface{}, error) {
-	result, err := models.FindOrdersHighestPriceByCollectibleId(collectibleId)
+func getOrdersHighestPriceByCollectibleId(network string, collectibleId int64, history bool) (*models.ResponseOrder, error) {
+	result, err := models.FindOrdersHighestPriceByCollectibleId(network, collectibleId, history)
 	if err != nil {
 		logs.GetLogger().Error(err)
 		return nil, err
@@ -125,8 +58,8 @@ func getOrdersHighestPriceByCollectibleId(collectibleId int64) (interface{}, err
 }
 
 // getCollectionsByOwner get collection by owner
-func getCollectionsByUserMetamaskID(usermetamaskid string, page, pageSize int64) (map[string]interface{}, error) {
-	result, err := models.FindCollectionByUserMetamaskID(usermetamaskid, page, pageSize)
+func getCollectionsByUserMetamaskID(network, usermetamaskid string, page, pageSize int64) (map[string]interface{}, error) {
+	result, err := models.FindCollectionByUserMetamaskID(network, usermetamaskid, page, pageSize)
 	if err != nil {
 		logs.GetLogger().Error(err)
 		return nil, err
@@ -135,8 +68,8 @@ func getCollectionsByUserMetamaskID(usermetamaskid string, page, pageSize int64)
 }
 
 // getCollectionsByCollectionID get collection by slug
-func getCollectionsByCollectionID(collectionId string) (map[string]interface{}, error) {
-	result, err := models.FindCollectionByCollectionID(collectionId)
+func getCollectionsByCollectionID(network, collectionId string) (map[string]interface{}, error) {
+	result, err := models.FindCollectionByCollectionID(network, collectionId)
 	if err != nil {
 		logs.GetLogger().Error(err)
 		return nil, err
@@ -145,8 +78,8 @@ func getCollectionsByCollectionID(collectionId string) (map[string]interface{},
 }
 
 // getAssetOfferRecordsByCollectibleId get asset orders by collectibleId
-func getAssetOfferRecordsByCollectibleId(collectibleId int64) ([]bson.M, error) {
-	result, err := models.FindAssetOfferRecordsByCollectibleId(collectibleId)
+func getAssetOfferRecordsByCollectibleId(network string, collectibleId int64, history bool) ([]models.ResponseOrder, error) {
+	result, err := models.FindAssetOfferRecordsByCollectibleId(network, collectibleId, history)
 	if err != nil {
 		logs.GetLogger().Error(err)
 		return nil, err
@@ -165,8 +98,8 @@ func getUserMediaByUserId(userMetamaskId string) (interface{}, error) {
 }
 
 // getItemActivityByCollectionId get item_activity by collectionId
-func getItemActivityByCollectionId(collectionId string, page, pageSize int64) (map[string]interface{}, error) {
-	result, err := models.FindItemActivityByCollectionId(collectionId, page, pageSize)
+func getItemActivityByCollectionId(network, collectionId string, page, pageSize int64) (map[string]interface{}, error) {
+	result, err := models.FindItemActivityByCollectionId(network, collectionId, page, pageSize)
 	if err != nil {
 		logs.GetLogger().Error(err)
 		return nil, err
@@ -175,8 +108,47 @@ func getItemActivityByCollectionId(collectionId string, page, pageSize int64) (m
 }
 
 // getTradeHistoryByCollectibleId get trade history by collectibleId
-func getTradeHistoryByCollectibleId(collectibleId int64, page, pageSize int64) (map[string]interface{}, error) {
-	result, err := models.FindTradeHistoryByCollectibleId(collectibleId, page, pageSize)
+func getTradeHistoryByCollectibleId(network string, collectibleId int64, page, pageSize int64) (map[string]interface{}, error) {
+	result, err := models.FindTradeHistoryByCollectibleId(network, collectibleId, page, pageSize)
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	return result, nil
+}
+
+// getHolderAnalytics holder analytics of a collection with its top holders, nil when nothing of it was synced
+func getHolderAnalytics(network, collectionId string, top int64) (*models.HolderAnalytics, error) {
+	result, err := models.FindHolderAnalytics(network, collectionId, top)
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	return result, nil
+}
+
+func getCollectionRankings(network, metric, window string, page, pageSize int64) (map[string]interface{}, error) {
+	result, err := models.FindCollectionRankings(network, metric, window, page, pageSize)
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	return result, nil
+}
+
+// search best matches of q per entity type
+func search(network, q string, types map[string]bool, limit int64) (*models.SearchResult, error) {
+	result, err := models.Search(network, q, types, limit)
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	return result, nil
+}
+
+// getAssetChangesByCollectibleId audit history of an asset
+func getAssetChangesByCollectibleId(network string, collectibleId int64, page, pageSize int64) (map[string]interface{}, error) {
+	result, err := models.FindAssetChangesByCollectibleId(network, collectibleId, page, pageSize)
 	if err != nil {
 		logs.GetLogger().Error(err)
 		return nil, err
@@ -185,19 +157,21 @@ func getTradeHistoryByCollectibleId(collectibleId int64, page, pageSize int64) (
 }
 
 // deleteAssetByTokenID delete asset
-func deleteAssetByTokenID(user, contractAddress, tokenID string) error {
-	if err := models.DeleteAssetByTokenID(user, contractAddress, tokenID); err != nil {
+func deleteAssetByTokenID(network, user, contractAddress, tokenID string) error {
+	if err := models.DeleteAssetByTokenID(network, user, contractAddress, tokenID); err != nil {
 		logs.GetLogger().Error(err)
 		return err
 	}
+	cache.Invalidate(cache.Tag(cache.TAG_WALLET, user))
 	return nil
 }
 
 // deleteCollectionByCollectionId delete collection
-func deleteCollectionByCollectionId(user, slug string) error {
-	if err := models.DeleteCollectionByCollectionId(user, slug); err != nil {
+func deleteCollectionByCollectionId(network, user, slug string) error {
+	if err := models.DeleteCollectionByCollectionId(network, user, slug); err != nil {
 		logs.GetLogger().Error(err)
 		return err
 	}
+	cache.Invalidate(cache.Tag(cache.TAG_WALLET, user), cache.Tag(cache.TAG_COLLECTION, slug))
 	return nil
 }
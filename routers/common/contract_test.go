@@ -0,0 +1,246 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"openseasync/config"
+	"openseasync/database"
+	"openseasync/models"
+	"openseasync/openapi"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// testMongoURI names the mongo the contract test seeds its fixtures into, the test is skipped without it
+const testMongoURI = "OPENSEASYNC_TEST_MONGO_URI"
+
+const (
+	testAdminToken = "contract-test"
+	testNetwork    = "ethereum"
+	testWallet     = "0x1111111111111111111111111111111111111111"
+	testSeller     = "0x2222222222222222222222222222222222222222"
+	testContract   = "0x00000000000000000000000000000000000c0de1"
+	testCollection = "contract-fixtures"
+	testWebhook    = "contract-fixture"
+)
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "openseasync")
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	file := filepath.Join(dir, "config.toml")
+	err = os.WriteFile(file, []byte(`port = "8888"
+dev = false
+
+[database]
+db_host = "localhost"
+db_port = "27017"
+db_schema_name = "openseasync"
+db_username = ""
+db_pwd = ""
+
+[log]
+output = ["none"]
+
+[chain]
+network = "`+testNetwork+`"
+
+[cache]
+enabled = false
+
+[admin]
+token = "`+testAdminToken+`"
+`), 0600)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	config.InitConfig(file)
+	gin.SetMode(gin.ReleaseMode)
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// TestRouteDrift every registered route is documented and every documented route registered
+func TestRouteDrift(t *testing.T) {
+	engine := gin.New()
+	RegisterRoutes(engine)
+	doc := OpenAPI()
+
+	registered := make(map[string]bool)
+	for _, route := range engine.Routes() {
+		registered[strings.ToLower(route.Method)+" "+openapi.OpenAPIPath(route.Path)] = true
+		if doc.Operation(route.Method, route.Path) == nil {
+			t.Errorf("%s %s is registered but not documented", route.Method, route.Path)
+		}
+	}
+	for _, path := range sortedPaths(doc) {
+		for method := range doc.Paths[path] {
+			if !registered[method+" "+path] {
+				t.Errorf("%s %s is documented but not registered", strings.ToUpper(method), path)
+			}
+		}
+	}
+}
+
+// TestContract the responses of the GET routes over seeded fixtures match their schemas
+func TestContract(t *testing.T) {
+	uri := os.Getenv(testMongoURI)
+	if uri == "" || testing.Short() {
+		t.Skipf("set %s to a mongo the fixtures can be seeded into", testMongoURI)
+	}
+	seedFixtures(t, uri)
+
+	engine := gin.New()
+	RegisterRoutes(engine)
+	doc := OpenAPI()
+	samples := map[string]string{
+		"user":           testWallet,
+		"usermetamaskid": testWallet,
+		"userMetamaskId": testWallet,
+		"wallet":         testWallet,
+		"collectionId":   testCollection,
+		"collectibleId":  "1",
+		"id":             testWebhook,
+		"chain":          testNetwork,
+		"q":              "fixture",
+		"page":           "1",
+		"pageSize":       "10",
+	}
+	for _, path := range sortedPaths(doc) {
+		op := doc.Paths[path][strings.ToLower(http.MethodGet)]
+		if op == nil || op.OperationId == "syncWallet" {
+			continue
+		}
+		if _, ok := op.Responses["200"].Content["application/json"]; !ok {
+			continue
+		}
+		target, missing := fill(path, op, samples)
+		if missing != "" {
+			t.Errorf("%s: no sample for %s", op.OperationId, missing)
+			continue
+		}
+		checkResponse(t, engine, doc, path, target)
+
+		// a malformed wallet must be rejected with a documented error
+		for _, p := range op.Parameters {
+			if p.In == "path" && p.Schema.Pattern != "" {
+				bad, _ := fill(path, op, override(samples, p.Name, "0xinvalid"))
+				checkResponse(t, engine, doc, path, bad)
+				break
+			}
+		}
+	}
+}
+
+// seedFixtures point the models at a fresh database of the mongo at uri holding a wallet with one asset,
+// its collection, a sale and a webhook, dropped when the test ends
+func seedFixtures(t *testing.T, uri string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := client.Database(fmt.Sprintf("openseasync_contract_%d", time.Now().UnixNano()))
+	t.Cleanup(func() {
+		db.Drop(context.Background())
+		client.Disconnect(context.Background())
+	})
+	database.SetMongoClient(db)
+
+	now := time.Now().UnixMilli()
+	decimals := 18
+	fixtures := map[string][]interface{}{
+		"users": {models.User{Id: "1", UserMetamaskID: testWallet, Username: "fixture"}},
+		"assets": {models.Asset{Id: 1, Chain: testNetwork, UserMetamaskID: testWallet, CollectibleName: "fixture #1",
+			ContractAddress: testContract, CollectibleTokenId: "1", OwnerMetamaskId: testWallet, CollectionID: testCollection,
+			CollectionName: "Fixtures", NumOfCopies: 1, Price: "1000000000000000000", Status: "onHold", CreateDate: now}},
+		"collections": {models.Collection{ID: testCollection, Chain: testNetwork, UserMetamaskID: testWallet,
+			CollectionName: "Fixtures"}},
+		"item_activitys": {models.ItemActivity{Id: 1, Chain: testNetwork, CollectibleId: 1, CollectibleName: "fixture #1",
+			CollectionId: testCollection, CollectionName: "Fixtures", ContractAddress: testContract, TokenId: "1",
+			CreateDate: now, Price: "1000000000000000000", SellerMetamaskId: testSeller, BuyerMetamaskId: testWallet,
+			Quantity: "1", TradeType: "successful", PayTokenContract: models.PayTokenContract{Symbol: "ETH", Decimals: &decimals},
+			TradeEthPrice: "1", Source: models.ACTIVITY_SOURCE_OPENSEA}},
+		"webhooks": {models.Webhook{Id: testWebhook, Url: "https://93.184.216.34/hook", Chain: testNetwork,
+			Wallets: []string{testWallet}, CreateDate: now}},
+	}
+	for collection, documents := range fixtures {
+		if _, err := db.Collection(collection).InsertMany(ctx, documents); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// checkResponse a GET of target against the documented responses of path
+func checkResponse(t *testing.T, engine *gin.Engine, doc *openapi.Document, path, target string) {
+	request := httptest.NewRequest(http.MethodGet, target, nil)
+	request.Header.Set("Authorization", "Bearer "+testAdminToken)
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, request)
+	body, err := io.ReadAll(recorder.Result().Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, problem := range doc.ValidateResponse(http.MethodGet, path, recorder.Code, body) {
+		t.Errorf("GET %s %d: %s", target, recorder.Code, problem)
+	}
+}
+
+// fill the url of an operation from the samples, or the name of a required parameter without one
+func fill(path string, op *openapi.Operation, samples map[string]string) (string, string) {
+	query := url.Values{}
+	for _, p := range op.Parameters {
+		value := samples[p.Name]
+		if value == "" {
+			if p.Required {
+				return "", p.Name
+			}
+			continue
+		}
+		if p.In == "path" {
+			path = strings.Replace(path, "{"+p.Name+"}", url.PathEscape(value), 1)
+		} else {
+			query.Set(p.Name, value)
+		}
+	}
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	return path, ""
+}
+
+func override(samples map[string]string, name, value string) map[string]string {
+	result := make(map[string]string, len(samples))
+	for k, v := range samples {
+		result[k] = v
+	}
+	result[name] = value
+	return result
+}
+
+func sortedPaths(doc *openapi.Document) []string {
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
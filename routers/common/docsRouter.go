@@ -0,0 +1,28 @@
+package common
+
+import (
+	_ "embed"
+	"net/http"
+	"openseasync/common/constants"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:embed docs.html
+var docsPage []byte
+
+func DocsManager(router *gin.RouterGroup) {
+	router.GET(constants.URL_OPENAPI, GetOpenAPI)
+	router.GET(constants.URL_DOCS, GetDocs)
+}
+
+// GetOpenAPI the specification, unwrapped so tools can read it directly
+func GetOpenAPI(c *gin.Context) {
+	c.Header("Content-Type", "application/vnd.oai.openapi+json")
+	c.JSON(http.StatusOK, OpenAPI())
+}
+
+// GetDocs a page rendering the specification
+func GetDocs(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", docsPage)
+}
@@ -0,0 +1,93 @@
+package common
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"openseasync/common/constants"
+	"openseasync/logs"
+	"openseasync/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	EXPORT_FORMAT_CSV    = "csv"
+	EXPORT_FORMAT_NDJSON = "ndjson"
+)
+
+func ExportManager(router *gin.RouterGroup) {
+	router.GET(constants.URL_EXPORT_WALLET_ASSETS, ExportWalletAssets)
+	router.GET(constants.URL_EXPORT_WALLET_ACTIVITY, ExportWalletActivity)
+}
+
+// ExportWalletAssets stream the assets held by a wallet as csv or ndjson
+func ExportWalletAssets(c *gin.Context) {
+	exportWallet(c, "assets", models.ExportColumns, models.ExportWalletAssets)
+}
+
+// ExportWalletActivity stream the sales and transfers of a wallet as csv or ndjson
+func ExportWalletActivity(c *gin.Context) {
+	exportWallet(c, "activity", models.ActivityExportColumns, models.ExportWalletActivity)
+}
+
+type exportFunc func(ctx context.Context, network, user string, fn func(models.ExportRow) error) error
+
+func exportWallet(c *gin.Context, name string, columns []string, export exportFunc) {
+	var req ExportRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+	user, network, format := req.Wallet(), req.Network(), req.Format
+
+	ctx := c.Request.Context()
+	filename := user + "-" + name + "." + format
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Status(http.StatusOK)
+
+	var (
+		rows  int
+		write func(models.ExportRow) error
+		flush func()
+	)
+	if format == EXPORT_FORMAT_CSV {
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		writer := csv.NewWriter(c.Writer)
+		if err := writer.Write(columns); err != nil {
+			logs.FromContext(ctx).Error(err)
+			return
+		}
+		write = func(row models.ExportRow) error {
+			return writer.Write(row.Values(columns))
+		}
+		flush = writer.Flush
+	} else {
+		c.Header("Content-Type", "application/x-ndjson")
+		encoder := json.NewEncoder(c.Writer)
+		write = func(row models.ExportRow) error {
+			return encoder.Encode(row)
+		}
+		flush = func() {}
+	}
+
+	err := export(ctx, network, user, func(row models.ExportRow) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := write(row); err != nil {
+			return err
+		}
+		if rows++; rows%100 == 0 {
+			flush()
+			c.Writer.Flush()
+		}
+		return nil
+	})
+	flush()
+	c.Writer.Flush()
+	if err != nil {
+		// the status is already sent, the client sees a truncated file
+		logs.FromContext(ctx).Error(err)
+	}
+}
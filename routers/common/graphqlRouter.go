@@ -0,0 +1,36 @@
+package common
+
+import (
+	"net/http"
+	"openseasync/common/constants"
+	"openseasync/graph"
+	"openseasync/logs"
+
+	"github.com/gin-gonic/gin"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+func GraphqlManager(router *gin.RouterGroup) {
+	schema, err := graph.NewSchema()
+	if err != nil {
+		logs.GetLogger().Fatal(err)
+	}
+	router.POST(constants.URL_GRAPHQL, Graphql(schema))
+}
+
+// Graphql answer a query over assets, collections, users and activity, the response is a graphql
+// response and not a BasicResponse, errors of the query are reported in it with status 200
+func Graphql(schema *graphql.Schema) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req GraphQLRequest
+		if !bindJSON(c, &req) {
+			return
+		}
+		ctx := graph.WithLoaders(c.Request.Context())
+		response := schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+		for _, e := range response.Errors {
+			logs.FromContext(c.Request.Context()).Warn(e)
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
@@ -0,0 +1,53 @@
+package common
+
+import (
+	"context"
+	"net/http"
+	"openseasync/common"
+	"openseasync/common/constants"
+	"openseasync/common/errorinfo"
+	"openseasync/common/utils"
+	"openseasync/config"
+	"openseasync/database"
+	"openseasync/logs"
+	"openseasync/metrics"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthStatus what Healthz reports
+type HealthStatus struct {
+	Version string `json:"version"`
+}
+
+func HealthManager(router *gin.RouterGroup) {
+	router.GET(constants.URL_HEALTHZ, Healthz)
+	router.GET(constants.URL_READYZ, Readyz)
+	router.GET(constants.URL_METRICS, metrics.Handler())
+}
+
+// Healthz the process is up
+func Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, common.CreateSuccessResponse(HealthStatus{Version: common.GetVersion()}, nil))
+}
+
+// Readyz mongo, and opensea if configured, are reachable
+func Readyz(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := database.PingMongo(ctx); err != nil {
+		logs.FromContext(c.Request.Context()).Error(err)
+		c.JSON(http.StatusServiceUnavailable, common.CreateErrorResponse(errorinfo.SERVICE_NOT_READY_ERROR_CODE, "mongo: "+err.Error()))
+		return
+	}
+
+	if config.GetConfig().OpenSea.ReadyCheck {
+		if err := utils.PingOpenSea(); err != nil {
+			logs.FromContext(c.Request.Context()).Error(err)
+			c.JSON(http.StatusServiceUnavailable, common.CreateErrorResponse(errorinfo.SERVICE_NOT_READY_ERROR_CODE, "opensea: "+err.Error()))
+			return
+		}
+	}
+	c.JSON(http.StatusOK, common.CreateSuccessResponse(nil, nil))
+}
@@ -0,0 +1,179 @@
+package common
+
+import (
+	"openseasync/models"
+	"strings"
+)
+
+// request parameters of the handlers, path and query parameters are both bound by their form tag
+
+// ChainQuery restrict a query to one chain, every chain when empty
+type ChainQuery struct {
+	Chain string `form:"chain" binding:"omitempty,chain"`
+}
+
+// Network the chain, normalized
+func (q ChainQuery) Network() string {
+	return strings.ToLower(strings.TrimSpace(q.Chain))
+}
+
+// PageQuery a page of a list, both required
+type PageQuery struct {
+	Page     int64 `form:"page" binding:"required,min=1"`
+	PageSize int64 `form:"pageSize" binding:"required,min=1"`
+}
+
+// DefaultPageQuery a page of a list, the first 10 records by default
+type DefaultPageQuery struct {
+	Page     int64 `form:"page,default=1" binding:"min=1"`
+	PageSize int64 `form:"pageSize,default=10" binding:"min=1"`
+}
+
+// WalletRequest a wallet in the path
+type WalletRequest struct {
+	User string `form:"user" binding:"required,ethaddr"`
+	ChainQuery
+}
+
+// Wallet the wallet as stored, lower case
+func (r WalletRequest) Wallet() string {
+	return strings.ToLower(r.User)
+}
+
+type AssetSearchRequest struct {
+	CollectionId string `form:"collectionId" binding:"required"`
+	models.Params
+}
+
+type CollectibleRequest struct {
+	CollectibleId int64 `form:"collectibleId" binding:"required,min=1"`
+	ChainQuery
+}
+
+type OrdersRequest struct {
+	CollectibleRequest
+	Include string `form:"include" binding:"omitempty,oneof=history"` // history adds the filled, cancelled and expired orders
+}
+
+// History whether the closed orders are wanted too
+func (r OrdersRequest) History() bool {
+	return r.Include == INCLUDE_HISTORY
+}
+
+type CollectiblePageRequest struct {
+	CollectibleRequest
+	PageQuery
+}
+
+type UserCollectionsRequest struct {
+	UserMetamaskId string `form:"usermetamaskid" binding:"required,ethaddr"`
+	PageQuery
+	ChainQuery
+}
+
+type UserMediaRequest struct {
+	UserMetamaskId string `form:"userMetamaskId" binding:"required,ethaddr"`
+}
+
+type CollectionRequest struct {
+	CollectionId string `form:"collectionId" binding:"required"`
+	ChainQuery
+}
+
+type CollectionPageRequest struct {
+	CollectionRequest
+	PageQuery
+}
+
+type HoldersRequest struct {
+	CollectionRequest
+	Top int64 `form:"top,default=10" binding:"min=1,max=100"` // number of top holders
+}
+
+type RankingsRequest struct {
+	Metric string `form:"metric,default=volume" binding:"oneof=volume sales floor_change"`
+	Window string `form:"window,default=1d" binding:"oneof=1d 7d 30d"`
+	DefaultPageQuery
+	ChainQuery
+}
+
+type SearchRequest struct {
+	Q     string `form:"q" binding:"required,max=100"`
+	Type  string `form:"type"` // asset, collection, user, comma separated, every type when empty
+	Limit int64  `form:"limit,default=10" binding:"min=1,max=100"`
+	ChainQuery
+}
+
+type DeleteAssetRequest struct {
+	User            string `form:"user" binding:"required,ethaddr"`
+	ContractAddress string `form:"contract_address" binding:"required,ethaddr"`
+	TokenId         string `form:"token_id" binding:"required"`
+	ChainQuery
+}
+
+type DeleteCollectionRequest struct {
+	User string `form:"user" binding:"required,ethaddr"`
+	Slug string `form:"slug" binding:"required"`
+	ChainQuery
+}
+
+type SyncListRequest struct {
+	DefaultPageQuery
+	ChainQuery
+}
+
+type PurgeRequest struct {
+	Days int64 `form:"days" binding:"required,min=1"` // documents soft deleted more than days ago are removed
+	ChainQuery
+}
+
+type EraseWalletRequest struct {
+	User   string `form:"user" binding:"required,ethaddr"`
+	Reason string `form:"reason" binding:"max=200"` // kept in the audit record
+}
+
+type OptInRequest struct {
+	User string `form:"user" binding:"required,ethaddr"`
+}
+
+type WalletPnlRequest struct {
+	WalletRequest
+	Method string `form:"method,default=fifo" binding:"oneof=fifo specific"`
+	Match  string `form:"match"` // saleActivityId:purchaseActivityId pairs, comma separated
+}
+
+type WalletPortfolioRequest struct {
+	WalletRequest
+	Valuation string `form:"valuation,default=floor" binding:"oneof=floor last_sale listing"`
+}
+
+type ExportRequest struct {
+	WalletRequest
+	Format string `form:"format,default=csv" binding:"oneof=csv ndjson"`
+}
+
+type StreamRequest struct {
+	CollectionId string `form:"collectionId" binding:"required_without=Wallet"`
+	Wallet       string `form:"wallet" binding:"omitempty,ethaddr"`
+	ChainQuery
+}
+
+type WebhookDeliveriesRequest struct {
+	Id string `form:"id" binding:"required"`
+	DefaultPageQuery
+}
+
+type WebhookIdRequest struct {
+	Id string `form:"id" binding:"required"`
+}
+
+type RedeliverRequest struct {
+	Id         string `form:"id" binding:"required"`
+	DeliveryId string `form:"deliveryId" binding:"required"`
+}
+
+type GraphQLRequest struct {
+	Query         string                 `json:"query" binding:"required"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
@@ -0,0 +1,149 @@
+package common
+
+import (
+	"net/http"
+	"openseasync/accounting"
+	"openseasync/common"
+	"openseasync/common/constants"
+	"openseasync/models"
+	"openseasync/openapi"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	specOnce sync.Once
+	spec     *openapi.Document
+)
+
+// RegisterRoutes the handlers of every route, each one documented in Routes
+func RegisterRoutes(r *gin.Engine) {
+	HealthManager(&r.RouterGroup)
+
+	v1 := r.Group(constants.URL_API_PREFIX)
+	DocsManager(v1)
+	HostManager(v1.Group(constants.URL_HOST_GET_COMMON))
+	WebhookManager(v1.Group(constants.URL_HOST_GET_COMMON))
+	GraphqlManager(v1.Group(constants.URL_HOST_GET_COMMON))
+	StreamManager(v1.Group(constants.URL_HOST_GET_COMMON))
+	ExportManager(v1.Group(constants.URL_HOST_GET_COMMON))
+	WalletManager(v1.Group(constants.URL_HOST_GET_COMMON))
+	AdminManager(v1.Group(constants.URL_ADMIN_PREFIX))
+}
+
+// OpenAPI the specification of every route, built once from Routes
+func OpenAPI() *openapi.Document {
+	specOnce.Do(func() {
+		spec = openapi.Build("openseasync", common.GetVersion(), Routes())
+	})
+	return spec
+}
+
+// Routes what every registered route takes and returns, the source of the OpenAPI specification
+func Routes() []openapi.Route {
+	public := constants.URL_API_PREFIX + constants.URL_HOST_GET_COMMON
+	admin := constants.URL_API_PREFIX + constants.URL_ADMIN_PREFIX
+	return []openapi.Route{
+		{Method: http.MethodGet, Path: constants.URL_HEALTHZ, OperationId: "healthz", Summary: "the process is up", Tag: "health",
+			Response: HealthStatus{}},
+		{Method: http.MethodGet, Path: constants.URL_READYZ, OperationId: "readyz", Summary: "mongo, and opensea if configured, are reachable", Tag: "health",
+			Errors: []int{http.StatusServiceUnavailable}},
+		{Method: http.MethodGet, Path: constants.URL_METRICS, OperationId: "metrics", Summary: "prometheus metrics", Tag: "health",
+			ContentType: "text/plain"},
+		{Method: http.MethodGet, Path: constants.URL_API_PREFIX + constants.URL_OPENAPI, OperationId: "openapi", Summary: "this specification", Tag: "docs",
+			ContentType: "application/vnd.oai.openapi+json"},
+		{Method: http.MethodGet, Path: constants.URL_API_PREFIX + constants.URL_DOCS, OperationId: "docs", Summary: "api documentation", Tag: "docs",
+			ContentType: "text/html"},
+
+		{Method: http.MethodGet, Path: public + constants.URL_HOST_GET_HOST_INFO, OperationId: "getHostInfo", Summary: "version and platform of the service", Tag: "host",
+			Response: common.HostInfo{}},
+		{Method: http.MethodGet, Path: public + constants.URL_OPENSEA_OWNER_ASSETS_SYNC, OperationId: "syncWallet", Summary: "sync the collections and assets of a wallet from opensea", Tag: "sync",
+			Request: WalletRequest{}, Errors: []int{http.StatusForbidden, http.StatusConflict}},
+		{Method: http.MethodGet, Path: public + constants.URL_FIND_ASSETS_COLLETION_SEARCH, OperationId: "searchCollectionAssets", Summary: "assets of a collection by status, price and name", Tag: "assets",
+			Request: AssetSearchRequest{}, Response: []models.ResponseAssetItem{}, Paged: true},
+		{Method: http.MethodGet, Path: public + constants.URL_FIND_ASSETS_COLLECTIBLESID, OperationId: "getAsset", Summary: "an asset in full", Tag: "assets",
+			Request: CollectibleRequest{}, Response: models.ResponseAsset{}},
+		{Method: http.MethodGet, Path: public + constants.URL_FIND_COLLECTION_USERMETAMASKID, OperationId: "getWalletCollections", Summary: "collections of a wallet", Tag: "collections",
+			Request: UserCollectionsRequest{}, Response: []models.ResponseColl{}, Paged: true},
+		{Method: http.MethodGet, Path: public + constants.URL_FIND_COLLECTION_COLLECTIONID, OperationId: "getCollection", Summary: "a collection with its creator", Tag: "collections",
+			Request: CollectionRequest{}, Response: models.ResponseCollection{}},
+		{Method: http.MethodGet, Path: public + constants.URL_FIND_COLLECTION_ITEM_ACTIVITY_COLLECTIONID, OperationId: "getCollectionActivities", Summary: "sales, bids and transfers of a collection", Tag: "collections",
+			Request: CollectionPageRequest{}, Response: []models.ResponseItemActivity{}, Paged: true},
+		{Method: http.MethodGet, Path: public + constants.URL_FIND_COLLECTION_HOLDERS, OperationId: "getCollectionHolders", Summary: "top holders, holding distribution and holder churn of a collection", Tag: "collections",
+			Request: HoldersRequest{}, Response: models.HolderAnalytics{}, Errors: []int{http.StatusNotFound}},
+		{Method: http.MethodGet, Path: public + constants.URL_COLLECTION_RANKINGS, OperationId: "getCollectionRankings", Summary: "collections ranked by volume, sales or floor change", Tag: "collections",
+			Request: RankingsRequest{}, Response: []models.CollectionRanking{}, Paged: true},
+		{Method: http.MethodGet, Path: public + constants.URL_SEARCH, OperationId: "search", Summary: "full text search of assets, collections and users", Tag: "search",
+			Request: SearchRequest{}, Response: models.SearchResult{}},
+		{Method: http.MethodPost, Path: public + constants.URL_GRAPHQL, OperationId: "graphql", Summary: "assets, collections, users, orders and activity with their relations, a graphql response", Tag: "search",
+			Body: GraphQLRequest{}, ContentType: "application/graphql-response+json"},
+		{Method: http.MethodGet, Path: public + constants.URL_FIND_USER_SOCIALMEDIA, OperationId: "getUser", Summary: "profile and social links of a user", Tag: "users",
+			Request: UserMediaRequest{}, Response: models.User{}},
+		{Method: http.MethodGet, Path: public + constants.URL_FIND_TRADE_HISTORY, OperationId: "getAssetTradeHistory", Summary: "sales, bids and transfers of an asset", Tag: "assets",
+			Request: CollectiblePageRequest{}, Response: []models.ResponseItemActivity{}, Paged: true},
+		{Method: http.MethodGet, Path: public + constants.URL_FIND_ASSETS_OFFERRECORDS, OperationId: "getAssetOffers", Summary: "live bids and listings of an asset with valid or unverified signatures, closed and invalid ones too with include=history", Tag: "assets",
+			Request: OrdersRequest{}, Response: []models.ResponseOrder{}},
+		{Method: http.MethodGet, Path: public + constants.URL_FIND_ASSETS_HIGHESTPRICE, OperationId: "getAssetHighestBid", Summary: "highest live auction bid of an asset, of every bid with include=history, none without bids", Tag: "assets",
+			Request: OrdersRequest{}, Response: &models.ResponseOrder{}},
+		{Method: http.MethodGet, Path: public + constants.URL_FIND_ASSETS_OTTHER, OperationId: "getAssetSiblings", Summary: "other assets of the collection of an asset", Tag: "assets",
+			Request: CollectibleRequest{}, Response: []models.ResponseAssetItem{}},
+		{Method: http.MethodGet, Path: public + constants.URL_FIND_ASSETS_CHANGES, OperationId: "getAssetChanges", Summary: "fields changed by each sync of an asset", Tag: "assets",
+			Request: CollectiblePageRequest{}, Response: []models.AssetChange{}, Paged: true},
+		{Method: http.MethodDelete, Path: public + constants.URL_DELETE_ASSET, OperationId: "deleteAsset", Summary: "soft delete an asset of a wallet", Tag: "assets",
+			Request: DeleteAssetRequest{}},
+		{Method: http.MethodDelete, Path: public + constants.URL_DELETE_COLLECTION, OperationId: "deleteCollection", Summary: "soft delete an empty collection of a wallet", Tag: "collections",
+			Request: DeleteCollectionRequest{}, Errors: []int{http.StatusConflict}},
+
+		{Method: http.MethodPost, Path: public + constants.URL_WEBHOOKS, OperationId: "createWebhook", Summary: "subscribe a url to events", Tag: "webhooks",
+			Body: WebhookParam{}, Response: CreatedWebhook{}, Auth: true},
+		{Method: http.MethodGet, Path: public + constants.URL_WEBHOOKS, OperationId: "getWebhooks", Summary: "every subscription", Tag: "webhooks",
+			Response: []models.Webhook{}, Auth: true},
+		{Method: http.MethodDelete, Path: public + constants.URL_WEBHOOK, OperationId: "deleteWebhook", Summary: "remove a subscription", Tag: "webhooks",
+			Request: WebhookIdRequest{}, Auth: true, Errors: []int{http.StatusNotFound}},
+		{Method: http.MethodGet, Path: public + constants.URL_WEBHOOK_DELIVERIES, OperationId: "getWebhookDeliveries", Summary: "delivery log of a webhook", Tag: "webhooks",
+			Request: WebhookDeliveriesRequest{}, Response: []models.WebhookDelivery{}, Paged: true, Auth: true, Errors: []int{http.StatusNotFound}},
+		{Method: http.MethodGet, Path: public + constants.URL_WEBHOOK_DEAD_LETTERS, OperationId: "getWebhookDeadLetters", Summary: "deliveries of a webhook that ran out of attempts", Tag: "webhooks",
+			Request: WebhookDeliveriesRequest{}, Response: []models.WebhookDelivery{}, Paged: true, Auth: true, Errors: []int{http.StatusNotFound}},
+		{Method: http.MethodPost, Path: public + constants.URL_WEBHOOK_REDELIVER, OperationId: "redeliverWebhookDeadLetter", Summary: "queue a dead letter again", Tag: "webhooks",
+			Request: RedeliverRequest{}, Auth: true, Errors: []int{http.StatusNotFound}},
+
+		{Method: http.MethodGet, Path: public + constants.URL_STREAM_ACTIVITIES, OperationId: "streamActivities", Summary: "new activities and orders as server-sent events of stream.Message", Tag: "stream",
+			Request: StreamRequest{}, ContentType: "text/event-stream"},
+		{Method: http.MethodGet, Path: public + constants.URL_EXPORT_WALLET_ASSETS, OperationId: "exportWalletAssets", Summary: "assets of a wallet as csv or ndjson", Tag: "export",
+			Request: ExportRequest{}, ContentType: "text/csv"},
+		{Method: http.MethodGet, Path: public + constants.URL_EXPORT_WALLET_ACTIVITY, OperationId: "exportWalletActivity", Summary: "sales and transfers of a wallet as csv or ndjson", Tag: "export",
+			Request: ExportRequest{}, ContentType: "text/csv"},
+		{Method: http.MethodGet, Path: public + constants.URL_WALLET_PNL, OperationId: "getWalletPnl", Summary: "cost basis and profit and loss of a wallet, sales are valued at the rates recorded closest to them, rates being recorded from the first sync on", Tag: "wallet",
+			Request: WalletPnlRequest{}, Response: accounting.Report{}},
+		{Method: http.MethodGet, Path: public + constants.URL_WALLET_PORTFOLIO, OperationId: "getWalletPortfolio", Summary: "value of the assets of a wallet by collection", Tag: "wallet",
+			Request: WalletPortfolioRequest{}, Response: models.Portfolio{}},
+
+		{Method: http.MethodGet, Path: admin + constants.URL_ADMIN_SYNCS, OperationId: "getWalletSyncs", Summary: "synced wallets, least recently refreshed first, with their latest sync", Tag: "admin",
+			Request: SyncListRequest{}, Response: []models.WalletSync{}, Paged: true, Auth: true},
+		{Method: http.MethodPost, Path: admin + constants.URL_ADMIN_SYNC, OperationId: "forceSync", Summary: "start a sync of a wallet in the background", Tag: "admin",
+			Request: WalletRequest{}, Response: models.SyncRun{}, Auth: true, Errors: []int{http.StatusForbidden, http.StatusConflict}},
+		{Method: http.MethodDelete, Path: admin + constants.URL_ADMIN_SYNC, OperationId: "cancelSync", Summary: "cancel the running sync of a wallet", Tag: "admin",
+			Request: WalletRequest{}, Response: models.SyncRun{}, Auth: true, Errors: []int{http.StatusNotFound}},
+		{Method: http.MethodGet, Path: admin + constants.URL_ADMIN_SYNC_ERRORS, OperationId: "getSyncErrors", Summary: "failed syncs, most recent first", Tag: "admin",
+			Request: SyncListRequest{}, Response: []models.SyncRun{}, Paged: true, Auth: true},
+		{Method: http.MethodPost, Path: admin + constants.URL_ADMIN_PURGE, OperationId: "purgeDeleted", Summary: "remove documents soft deleted more than days ago", Tag: "admin",
+			Request: PurgeRequest{}, Response: PurgeResult{}, Auth: true},
+		{Method: http.MethodPost, Path: admin + constants.URL_ADMIN_RESTORE_ASSET, OperationId: "restoreAsset", Summary: "restore a soft deleted asset with its activity and orders", Tag: "admin",
+			Request: DeleteAssetRequest{}, Auth: true, Errors: []int{http.StatusNotFound}},
+		{Method: http.MethodPost, Path: admin + constants.URL_ADMIN_RESTORE_COLLECTION, OperationId: "restoreCollection", Summary: "restore a soft deleted collection", Tag: "admin",
+			Request: DeleteCollectionRequest{}, Auth: true, Errors: []int{http.StatusNotFound}},
+		{Method: http.MethodPost, Path: admin + constants.URL_ADMIN_RECOMPUTE_COLLECTION, OperationId: "recomputeCollection", Summary: "derive the floor price of a collection from its assets again", Tag: "admin",
+			Request: CollectionRequest{}, Response: models.Collection{}, Auth: true, Errors: []int{http.StatusNotFound}},
+		{Method: http.MethodPost, Path: admin + constants.URL_ADMIN_RECOMPUTE_RANKINGS, OperationId: "recomputeRankings", Summary: "refresh the collection rankings of every window now", Tag: "admin",
+			Auth: true},
+		{Method: http.MethodPost, Path: admin + constants.URL_ADMIN_RECOMPUTE_SUSPICIOUS, OperationId: "recomputeSuspicious", Summary: "flag the suspicious sales now, and refresh the rankings when a flag changed", Tag: "admin",
+			Response: SuspiciousResult{}, Auth: true},
+		{Method: http.MethodDelete, Path: admin + constants.URL_ADMIN_WALLET, OperationId: "eraseWallet", Summary: "erase every document synced for a wallet and block syncing it again", Tag: "admin",
+			Request: EraseWalletRequest{}, Response: models.Erasure{}, Auth: true},
+		{Method: http.MethodPost, Path: admin + constants.URL_ADMIN_WALLET_OPT_IN, OperationId: "optInWallet", Summary: "let an erased wallet be synced again", Tag: "admin",
+			Request: OptInRequest{}, Auth: true, Errors: []int{http.StatusNotFound}},
+		{Method: http.MethodGet, Path: admin + constants.URL_ADMIN_ERASURES, OperationId: "getErasures", Summary: "erasure audit records, most recent first", Tag: "admin",
+			Request: DefaultPageQuery{}, Response: []models.Erasure{}, Paged: true, Auth: true},
+	}
+}
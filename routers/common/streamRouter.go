@@ -0,0 +1,45 @@
+package common
+
+import (
+	"io"
+	"openseasync/common/constants"
+	"openseasync/stream"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func StreamManager(router *gin.RouterGroup) {
+	router.GET(constants.URL_STREAM_ACTIVITIES, StreamActivities)
+}
+
+// StreamActivities push new item activities and orders of a collection or wallet as server-sent events
+func StreamActivities(c *gin.Context) {
+	var req StreamRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+
+	subscription := stream.Subscribe(stream.Filter{Chain: req.Network(), CollectionId: req.CollectionId, Wallet: strings.ToLower(req.Wallet)})
+	defer stream.Unsubscribe(subscription)
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	heartbeat := time.NewTicker(15 * time.Second)
+	defer heartbeat.Stop()
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case message, ok := <-subscription.C:
+			if !ok {
+				return false
+			}
+			c.SSEvent(message.Type, message)
+		case <-heartbeat.C:
+			c.SSEvent("heartbeat", time.Now().UnixMilli())
+		}
+		return true
+	})
+}
@@ -0,0 +1,141 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"openseasync/chain"
+	"openseasync/common"
+	"openseasync/common/errorinfo"
+	"openseasync/logs"
+	"reflect"
+	"strconv"
+	"strings"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// RegisterValidators add the ethaddr and chain tags to gin binding and report fields by their request name
+func RegisterValidators() error {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return errors.New("unexpected binding validator engine")
+	}
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, tag := range []string{"form", "json"} {
+			if name := strings.Split(field.Tag.Get(tag), ",")[0]; name != "" && name != "-" {
+				return name
+			}
+		}
+		return field.Name
+	})
+	if err := v.RegisterValidation("ethaddr", func(fl validator.FieldLevel) bool {
+		return IsEthAddress(fl.Field().String())
+	}); err != nil {
+		return err
+	}
+	return v.RegisterValidation("chain", func(fl validator.FieldLevel) bool {
+		return chain.IsSupportedNetwork(strings.ToLower(strings.TrimSpace(fl.Field().String())))
+	})
+}
+
+// IsEthAddress 0x and 40 hex digits, mixed case addresses must carry a valid EIP-55 checksum
+func IsEthAddress(address string) bool {
+	if !ethcommon.IsHexAddress(address) || !strings.HasPrefix(address, "0x") {
+		return false
+	}
+	digits := address[2:]
+	if digits == strings.ToLower(digits) || digits == strings.ToUpper(digits) {
+		return true
+	}
+	return ethcommon.HexToAddress(address).Hex() == address
+}
+
+// bindRequest fill req from the path and query parameters, both named by form tags, then validate it.
+// A failure is recorded on the context for ErrorHandler to answer.
+func bindRequest(c *gin.Context, req interface{}) bool {
+	values := c.Request.URL.Query()
+	for _, v := range c.Params {
+		values.Set(v.Key, v.Value)
+	}
+	request := c.Request.Clone(c.Request.Context())
+	request.URL.RawQuery = values.Encode()
+	if err := binding.Query.Bind(request, req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return false
+	}
+	return true
+}
+
+// bindJSON bind and validate the json body into req, recording a failure like bindRequest
+func bindJSON(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return false
+	}
+	return true
+}
+
+// ErrorHandler answer the errors handlers record instead of writing a response,
+// binding errors become 400 with a message per rejected field
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		if bindErr := c.Errors.ByType(gin.ErrorTypeBind).Last(); bindErr != nil {
+			c.JSON(http.StatusBadRequest, common.CreateValidationErrorResponse(errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_CODE,
+				errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_MSG, fieldErrors(bindErr.Err)))
+			return
+		}
+		err := c.Errors.Last()
+		logs.FromContext(c.Request.Context()).Error(err.Err)
+		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse(errorinfo.GET_RECORD_lIST_ERROR_CODE, err.Error()))
+	}
+}
+
+// fieldErrors explain err per field
+func fieldErrors(err error) []common.FieldError {
+	var (
+		validationErrors validator.ValidationErrors
+		numError         *strconv.NumError
+		result           = make([]common.FieldError, 0)
+	)
+	switch {
+	case errors.As(err, &validationErrors):
+		for _, v := range validationErrors {
+			result = append(result, common.FieldError{Field: v.Field(), Message: fieldMessage(v)})
+		}
+	case errors.As(err, &numError):
+		result = append(result, common.FieldError{Message: fmt.Sprintf("%q is not a valid number", numError.Num)})
+	default:
+		result = append(result, common.FieldError{Message: err.Error()})
+	}
+	return result
+}
+
+func fieldMessage(err validator.FieldError) string {
+	switch err.Tag() {
+	case "required":
+		return "is required"
+	case "ethaddr":
+		return "must be a 0x prefixed address of 40 hex digits with a valid EIP-55 checksum"
+	case "chain":
+		return "is not a supported chain"
+	case "min":
+		return "must be at least " + err.Param()
+	case "max":
+		return "must be at most " + err.Param()
+	case "oneof":
+		return "must be one of " + strings.ReplaceAll(err.Param(), " ", ", ")
+	case "url":
+		return "must be a url"
+	case "required_without":
+		return "is required when " + strings.ToLower(err.Param()) + " is not given"
+	}
+	return "failed the " + err.Tag() + " check"
+}
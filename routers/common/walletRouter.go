@@ -0,0 +1,82 @@
+package common
+
+import (
+	"net/http"
+	"openseasync/common"
+	"openseasync/common/constants"
+	"openseasync/common/errorinfo"
+	"openseasync/logs"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+func WalletManager(router *gin.RouterGroup) {
+	router.GET(constants.URL_WALLET_PNL, GetWalletPnl)
+	router.GET(constants.URL_WALLET_PORTFOLIO, GetWalletPortfolio)
+}
+
+// GetWalletPnl realized and unrealized profit and loss of a wallet, summed per year.
+// method is fifo or specific, specific takes match=saleActivityId:purchaseActivityId,...
+// Sales are valued at the rates recorded closest to them, rates being recorded from the first sync on,
+// the report counts the sales valued more than an hour away as approximate.
+func GetWalletPnl(c *gin.Context) {
+	var req WalletPnlRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+	matches, ok := parseLotMatches(req.Match)
+	if !ok {
+		c.JSON(http.StatusBadRequest, common.CreateValidationErrorResponse(errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_CODE, errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_MSG,
+			[]common.FieldError{{Field: "match", Message: "must be saleActivityId:purchaseActivityId pairs separated by commas"}}))
+		return
+	}
+	report, err := getWalletPnl(req.Network(), req.Wallet(), req.Method, matches)
+	if err != nil {
+		logs.FromContext(c.Request.Context()).Error(err)
+		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse(errorinfo.GET_RECORD_lIST_ERROR_CODE, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, common.CreateSuccessResponse(report, nil))
+}
+
+// GetWalletPortfolio value of the assets held by a wallet by collection,
+// valuation is floor, last_sale or listing
+func GetWalletPortfolio(c *gin.Context) {
+	var req WalletPortfolioRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+	portfolio, err := getWalletPortfolio(req.Network(), req.Wallet(), req.Valuation)
+	if err != nil {
+		logs.FromContext(c.Request.Context()).Error(err)
+		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse(errorinfo.GET_RECORD_lIST_ERROR_CODE, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, common.CreateSuccessResponse(portfolio, nil))
+}
+
+// parseLotMatches read saleActivityId:purchaseActivityId pairs
+func parseLotMatches(value string) (map[int]int, bool) {
+	matches := make(map[int]int)
+	if value == "" {
+		return matches, true
+	}
+	for _, pair := range strings.Split(value, ",") {
+		ids := strings.Split(strings.TrimSpace(pair), ":")
+		if len(ids) != 2 {
+			return nil, false
+		}
+		sale, err := strconv.Atoi(ids[0])
+		if err != nil {
+			return nil, false
+		}
+		purchase, err := strconv.Atoi(ids[1])
+		if err != nil {
+			return nil, false
+		}
+		matches[sale] = purchase
+	}
+	return matches, true
+}
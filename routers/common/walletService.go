@@ -0,0 +1,41 @@
+package common
+
+import (
+	"openseasync/accounting"
+	"openseasync/logs"
+	"openseasync/models"
+)
+
+// getWalletPnl cost basis and profit and loss of a wallet from its sales
+func getWalletPnl(network, user, method string, matches map[int]int) (*accounting.Report, error) {
+	sales, err := models.FindWalletSales(network, user)
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	var collectionIds []string
+	seen := make(map[string]bool)
+	for _, v := range sales {
+		if !seen[v.CollectionId] {
+			seen[v.CollectionId] = true
+			collectionIds = append(collectionIds, v.CollectionId)
+		}
+	}
+	floors, err := models.FindCollectionFloorPrices(network, collectionIds)
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	prices := accounting.Prices{Floors: floors, EthUsd: accounting.LatestEthUsd(sales)}
+	return accounting.Compute(user, sales, method, matches, prices), nil
+}
+
+// getWalletPortfolio value of the assets held by a wallet
+func getWalletPortfolio(network, user, valuation string) (*models.Portfolio, error) {
+	portfolio, err := models.FindWalletPortfolio(network, user, valuation)
+	if err != nil {
+		logs.GetLogger().Error(err)
+		return nil, err
+	}
+	return portfolio, nil
+}
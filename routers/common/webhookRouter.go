@@ -0,0 +1,138 @@
+package common
+
+import (
+	"net/http"
+	"openseasync/common"
+	"openseasync/common/constants"
+	"openseasync/common/errorinfo"
+	"openseasync/logs"
+	"openseasync/webhook"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookManager subscriptions name their target and secret, only admins manage them
+func WebhookManager(router *gin.RouterGroup) {
+	router.Use(AdminAuth())
+	router.POST(constants.URL_WEBHOOKS, CreateWebhook)
+	router.GET(constants.URL_WEBHOOKS, GetWebhooks)
+	router.DELETE(constants.URL_WEBHOOK, DeleteWebhook)
+	router.GET(constants.URL_WEBHOOK_DELIVERIES, GetWebhookDeliveries)
+	router.GET(constants.URL_WEBHOOK_DEAD_LETTERS, GetWebhookDeadLetters)
+	router.POST(constants.URL_WEBHOOK_REDELIVER, RedeliverWebhookDeadLetter)
+}
+
+// CreateWebhook subscribe a url to events, the secret is only returned here
+func CreateWebhook(c *gin.Context) {
+	var param WebhookParam
+	if !bindJSON(c, &param) {
+		return
+	}
+	if err := webhook.ValidateTarget(c.Request.Context(), param.Url); err != nil {
+		c.JSON(http.StatusBadRequest, common.CreateValidationErrorResponse(errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_CODE, errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_MSG,
+			[]common.FieldError{{Field: "url", Message: err.Error()}}))
+		return
+	}
+	for _, v := range param.EventTypes {
+		if !webhook.EventTypes[v] {
+			c.JSON(http.StatusBadRequest, common.CreateValidationErrorResponse(errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_CODE, errorinfo.HTTP_REQUEST_PARAM_VALUE_ERROR_MSG,
+				[]common.FieldError{{Field: "eventTypes", Message: "unknown event type " + v}}))
+			return
+		}
+	}
+	param.Chain = strings.ToLower(param.Chain)
+	for i, v := range param.Wallets {
+		param.Wallets[i] = strings.ToLower(v)
+	}
+
+	subscription, err := createWebhook(param)
+	if err != nil {
+		logs.FromContext(c.Request.Context()).Error(err)
+		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse(errorinfo.SAVE_DATA_TO_DB_ERROR_CODE, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, common.CreateSuccessResponse(CreatedWebhook{Webhook: subscription, Secret: subscription.Secret}, nil))
+}
+
+func GetWebhooks(c *gin.Context) {
+	webhooks, err := getWebhooks()
+	if err != nil {
+		logs.FromContext(c.Request.Context()).Error(err)
+		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse(errorinfo.GET_RECORD_lIST_ERROR_CODE, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, common.CreateSuccessResponse(webhooks, nil))
+}
+
+func DeleteWebhook(c *gin.Context) {
+	var req WebhookIdRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+	deleted, err := deleteWebhookById(req.Id)
+	if err != nil {
+		logs.FromContext(c.Request.Context()).Error(err)
+		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse(errorinfo.UPDATE_DATA_TO_DB_ERROR_CODE, err.Error()))
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, common.CreateErrorResponse(errorinfo.WEBHOOK_NOT_FOUND_ERROR_CODE, errorinfo.WEBHOOK_NOT_FOUND_ERROR_MSG))
+		return
+	}
+	c.JSON(http.StatusOK, common.CreateSuccessResponse(nil, nil))
+}
+
+// GetWebhookDeliveries delivery log of a webhook, newest first
+func GetWebhookDeliveries(c *gin.Context) {
+	findWebhookDeliveries(c, false)
+}
+
+// GetWebhookDeadLetters deliveries of a webhook that ran out of attempts
+func GetWebhookDeadLetters(c *gin.Context) {
+	findWebhookDeliveries(c, true)
+}
+
+func findWebhookDeliveries(c *gin.Context, deadLetters bool) {
+	var req WebhookDeliveriesRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+	id := req.Id
+	subscription, err := getWebhookById(id)
+	if err != nil {
+		logs.FromContext(c.Request.Context()).Error(err)
+		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse(errorinfo.GET_RECORD_lIST_ERROR_CODE, err.Error()))
+		return
+	}
+	if subscription == nil {
+		c.JSON(http.StatusNotFound, common.CreateErrorResponse(errorinfo.WEBHOOK_NOT_FOUND_ERROR_CODE, errorinfo.WEBHOOK_NOT_FOUND_ERROR_MSG))
+		return
+	}
+	result, err := getWebhookDeliveries(id, deadLetters, req.Page, req.PageSize)
+	if err != nil {
+		logs.FromContext(c.Request.Context()).Error(err)
+		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse(errorinfo.GET_RECORD_lIST_ERROR_CODE, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, common.CreateSuccessResponse(result["data"], result["metadata"]))
+}
+
+// RedeliverWebhookDeadLetter queue a dead letter again
+func RedeliverWebhookDeadLetter(c *gin.Context) {
+	var req RedeliverRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+	ok, err := redeliverDeadLetter(c.Request.Context(), req.Id, req.DeliveryId)
+	if err != nil {
+		logs.FromContext(c.Request.Context()).Error(err)
+		c.JSON(http.StatusInternalServerError, common.CreateErrorResponse(errorinfo.UPDATE_DATA_TO_DB_ERROR_CODE, err.Error()))
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusNotFound, common.CreateErrorResponse(errorinfo.DEAD_LETTER_NOT_FOUND_ERROR_CODE, errorinfo.DEAD_LETTER_NOT_FOUND_ERROR_MSG))
+		return
+	}
+	c.JSON(http.StatusOK, common.CreateSuccessResponse(nil, nil))
+}
@@ -0,0 +1,90 @@
+package common
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"openseasync/models"
+	"openseasync/webhook"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookParam body of a new subscription
+type WebhookParam struct {
+	Url           string   `json:"url" binding:"required,url"`
+	Secret        string   `json:"secret"`
+	Chain         string   `json:"chain" binding:"omitempty,chain"`
+	Wallets       []string `json:"wallets" binding:"dive,ethaddr"`
+	CollectionIds []string `json:"collectionIds"`
+	EventTypes    []string `json:"eventTypes"`
+}
+
+// CreatedWebhook a new subscription with its secret, which is not returned again
+type CreatedWebhook struct {
+	Webhook *models.Webhook `json:"webhook"`
+	Secret  string          `json:"secret"`
+}
+
+// createWebhook save a subscription, a secret is generated when none is given
+func createWebhook(param WebhookParam) (*models.Webhook, error) {
+	if param.Secret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+		param.Secret = hex.EncodeToString(secret)
+	}
+	wallets := make([]string, 0, len(param.Wallets))
+	for _, v := range param.Wallets {
+		wallets = append(wallets, strings.ToLower(v))
+	}
+
+	subscription := &models.Webhook{
+		Id:            uuid.New().String(),
+		Url:           param.Url,
+		Secret:        param.Secret,
+		Chain:         param.Chain,
+		Wallets:       wallets,
+		CollectionIds: append(make([]string, 0), param.CollectionIds...),
+		EventTypes:    append(make([]string, 0), param.EventTypes...),
+		CreateDate:    time.Now().UnixMilli(),
+	}
+	if err := models.InsertWebhook(subscription); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+func getWebhooks() ([]models.Webhook, error) {
+	return models.FindWebhooks()
+}
+
+func getWebhookById(id string) (*models.Webhook, error) {
+	return models.FindWebhookById(id)
+}
+
+func deleteWebhookById(id string) (bool, error) {
+	return models.DeleteWebhookById(id)
+}
+
+func getWebhookDeliveries(id string, deadLetters bool, page, pageSize int64) (map[string]interface{}, error) {
+	return models.FindWebhookDeliveries(id, deadLetters, page, pageSize)
+}
+
+// redeliverDeadLetter queue a dead letter of webhook id again, false when there is no such dead letter
+func redeliverDeadLetter(ctx context.Context, id, deliveryId string) (bool, error) {
+	delivery, err := models.FindWebhookDeadLetterById(deliveryId)
+	if err != nil {
+		return false, err
+	}
+	if delivery == nil || delivery.WebhookId != id {
+		return false, nil
+	}
+	if err = webhook.Redeliver(ctx, delivery); err != nil {
+		return false, err
+	}
+	return true, nil
+}
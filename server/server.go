@@ -0,0 +1,96 @@
+// Package server runs the api with its background jobs, for the openseasync binary and the serve command of the cli
+package server
+
+import (
+	"context"
+	"openseasync/cache"
+	"openseasync/chain"
+	"openseasync/config"
+	"openseasync/indexer"
+	"openseasync/logs"
+	"openseasync/metrics"
+	"openseasync/models"
+	"openseasync/rankings"
+	"openseasync/routers/common"
+	"openseasync/stream"
+	"openseasync/washtrade"
+	"openseasync/webhook"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	cors "github.com/itsjamie/gin-cors"
+)
+
+// Migrate bring the documents and indexes of mongo up to date, every step is tried and the first error returned
+func Migrate() error {
+	var firstErr error
+	if err := models.BackfillChain(chain.DefaultNetwork()); err != nil {
+		logs.GetLogger().Error(err)
+		firstErr = err
+	}
+	if err := models.EnsureChainIndexes(); err != nil {
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	if err := models.EnsureSearchIndexes(); err != nil {
+		logs.GetLogger().Error(err)
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Run start the background jobs and serve the api on the configured port, the configuration and mongo
+// must be initialized. It only returns when the server fails.
+func Run() error {
+	// a failed migration is logged, the api still serves what is there
+	_ = Migrate()
+	// runs left running by the previous process will not finish
+	if err := models.AbortSyncRuns(); err != nil {
+		logs.GetLogger().Error(err)
+	}
+
+	webhook.Start()
+	stream.Start(context.Background())
+	if config.GetConfig().Cache.Enabled {
+		cache.Start(nil)
+	}
+	if config.GetConfig().Rankings.Enabled {
+		go rankings.Run(context.Background())
+	}
+	if config.GetConfig().WashTrade.Enabled {
+		go washtrade.Run(context.Background())
+	}
+
+	if config.GetConfig().Chain.IndexerEnabled {
+		for _, network := range chain.RpcNetworks() {
+			client, err := chain.GetClient(network)
+			if err != nil {
+				return err
+			}
+			go indexer.Run(context.Background(), client, network)
+		}
+	}
+
+	if err := common.RegisterValidators(); err != nil {
+		return err
+	}
+
+	r := gin.New()
+	r.Use(gin.Recovery(), logs.RequestID(), metrics.Middleware(), common.ErrorHandler())
+	r.Use(cors.Middleware(cors.Config{
+		Origins:         "*",
+		Methods:         "GET, PUT, POST, DELETE",
+		RequestHeaders:  "Origin, Authorization, Content-Type, " + logs.RequestIDHeader,
+		ExposedHeaders:  logs.RequestIDHeader + ", ETag, " + cache.HeaderCache,
+		MaxAge:          50 * time.Second,
+		Credentials:     true,
+		ValidateHeaders: false,
+	}))
+
+	common.RegisterRoutes(r)
+
+	return r.Run(":" + config.GetConfig().Port)
+}
@@ -0,0 +1,239 @@
+package stream
+
+import (
+	"context"
+	"openseasync/chain"
+	"openseasync/database"
+	"openseasync/events"
+	"openseasync/logs"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	MESSAGE_TYPE_ACTIVITY = "activity" // a new item_activitys document
+	MESSAGE_TYPE_ORDER    = "order"    // a new orders document
+)
+
+// Message a stored document pushed to the subscribers it concerns
+type Message struct {
+	Type         string      `json:"type"`
+	Chain        string      `json:"chain"`
+	CollectionId string      `json:"collectionId"`
+	Wallets      []string    `json:"-"`
+	Data         interface{} `json:"data"`
+}
+
+// Filter what a subscriber wants, empty fields match everything
+type Filter struct {
+	Chain        string
+	CollectionId string
+	Wallet       string
+}
+
+func (f Filter) match(message Message) bool {
+	if f.Chain != "" && f.Chain != message.Chain {
+		return false
+	}
+	if f.CollectionId != "" && f.CollectionId != message.CollectionId {
+		return false
+	}
+	if f.Wallet != "" {
+		for _, v := range message.Wallets {
+			if strings.EqualFold(v, f.Wallet) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Subscription messages matching a filter, C is closed by Unsubscribe
+type Subscription struct {
+	C      chan Message
+	filter Filter
+}
+
+var (
+	subscriptions     = make(map[*Subscription]struct{})
+	subscriptionsLock sync.RWMutex
+
+	// watching set while mongo change streams feed the subscribers, the event bus is ignored then
+	watching   bool
+	watchingMu sync.RWMutex
+)
+
+// Subscribe start receiving the messages matching filter
+func Subscribe(filter Filter) *Subscription {
+	subscription := &Subscription{C: make(chan Message, 64), filter: filter}
+	subscriptionsLock.Lock()
+	subscriptions[subscription] = struct{}{}
+	subscriptionsLock.Unlock()
+	return subscription
+}
+
+// Unsubscribe stop receiving messages
+func Unsubscribe(subscription *Subscription) {
+	subscriptionsLock.Lock()
+	defer subscriptionsLock.Unlock()
+	if _, ok := subscriptions[subscription]; ok {
+		delete(subscriptions, subscription)
+		close(subscription.C)
+	}
+}
+
+// broadcast hand message to every matching subscriber, slow subscribers miss it rather than block the sync
+func broadcast(message Message) {
+	subscriptionsLock.RLock()
+	defer subscriptionsLock.RUnlock()
+	for subscription := range subscriptions {
+		if !subscription.filter.match(message) {
+			continue
+		}
+		select {
+		case subscription.C <- message:
+		default:
+		}
+	}
+}
+
+// Start feed the subscribers from mongo change streams, or from the in-process event bus
+// when the deployment does not support them, such as a standalone mongod
+func Start(ctx context.Context) {
+	events.Subscribe(fromEvent)
+
+	db := database.GetMongoClient()
+	activities, err := watchInserts(ctx, db.Collection("item_activitys"), nil)
+	if err != nil {
+		logs.GetLogger().Warnf("change streams unavailable, streaming from the event bus: %s", err)
+		return
+	}
+	orders, err := watchInserts(ctx, db.Collection("orders"), nil)
+	if err != nil {
+		activities.Close(ctx)
+		logs.GetLogger().Warnf("change streams unavailable, streaming from the event bus: %s", err)
+		return
+	}
+
+	setWatching(true)
+	go follow(ctx, db.Collection("item_activitys"), activities, MESSAGE_TYPE_ACTIVITY)
+	go follow(ctx, db.Collection("orders"), orders, MESSAGE_TYPE_ORDER)
+}
+
+// watchInserts open a change stream of the inserts into collection, after resumeToken when set.
+// Orders failing signature verification are kept but not streamed, as the sync does not announce them.
+func watchInserts(ctx context.Context, collection *mongo.Collection, resumeToken bson.Raw) (*mongo.ChangeStream, error) {
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: bson.M{"operationType": "insert",
+		"fullDocument.signatureStatus": bson.M{"$ne": chain.SIGNATURE_INVALID}}}}}
+	opts := options.ChangeStream()
+	if resumeToken != nil {
+		opts.SetResumeAfter(resumeToken)
+	}
+	return collection.Watch(ctx, pipeline, opts)
+}
+
+// follow broadcast the inserts seen by stream, reopening it after an error until ctx is done.
+// It resumes after the last insert seen so none is missed while reconnecting, or from now on
+// when that point is no longer in the oplog.
+func follow(ctx context.Context, collection *mongo.Collection, stream *mongo.ChangeStream, messageType string) {
+	var resumeToken bson.Raw
+	for {
+		for stream.Next(ctx) {
+			resumeToken = stream.ResumeToken()
+			var change struct {
+				FullDocument bson.M `bson:"fullDocument"`
+			}
+			if err := stream.Decode(&change); err != nil {
+				logs.GetLogger().Error(err)
+				continue
+			}
+			broadcast(fromDocument(messageType, change.FullDocument))
+		}
+		if err := stream.Err(); err != nil {
+			logs.GetLogger().Error(err)
+		}
+		if token := stream.ResumeToken(); token != nil {
+			resumeToken = token
+		}
+		stream.Close(context.TODO())
+
+		for {
+			select {
+			case <-ctx.Done():
+				setWatching(false)
+				return
+			case <-time.After(5 * time.Second):
+			}
+			var err error
+			if stream, err = watchInserts(ctx, collection, resumeToken); err == nil {
+				break
+			}
+			logs.GetLogger().Error(err)
+			if resumeToken != nil && isHistoryLost(err) {
+				logs.GetLogger().Warnf("%s changes since the last one streamed are no longer in the oplog", collection.Name())
+				resumeToken = nil
+			}
+		}
+	}
+}
+
+// isHistoryLost whether err says the resume point fell off the oplog
+func isHistoryLost(err error) bool {
+	serverErr, ok := err.(mongo.ServerError)
+	return ok && (serverErr.HasErrorCode(286) || serverErr.HasErrorCode(280))
+}
+
+func setWatching(value bool) {
+	watchingMu.Lock()
+	defer watchingMu.Unlock()
+	watching = value
+}
+
+func isWatching() bool {
+	watchingMu.RLock()
+	defer watchingMu.RUnlock()
+	return watching
+}
+
+// fromEvent broadcast new activities and orders published by the sync when change streams are not used
+func fromEvent(ctx context.Context, event events.Event) {
+	var messageType string
+	switch event.Type {
+	case events.EVENT_TYPE_ACTIVITY:
+		messageType = MESSAGE_TYPE_ACTIVITY
+	case events.EVENT_TYPE_ORDER:
+		messageType = MESSAGE_TYPE_ORDER
+	default:
+		return
+	}
+	if isWatching() {
+		return
+	}
+	broadcast(Message{
+		Type:         messageType,
+		Chain:        event.Chain,
+		CollectionId: event.CollectionId,
+		Wallets:      event.Wallets,
+		Data:         event.Data,
+	})
+}
+
+// fromDocument the message of a document read from a change stream
+func fromDocument(messageType string, document bson.M) Message {
+	delete(document, "_id")
+	message := Message{Type: messageType, Data: document}
+	message.Chain, _ = document["chain"].(string)
+	message.CollectionId, _ = document["collectionId"].(string)
+	for _, field := range []string{"sellerMetamaskId", "buyerMetamaskId", "auctionMetamaskId"} {
+		if wallet, ok := document[field].(string); ok && wallet != "" {
+			message.Wallets = append(message.Wallets, wallet)
+		}
+	}
+	return message
+}
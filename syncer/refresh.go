@@ -0,0 +1,81 @@
+package syncer
+
+import (
+	"context"
+	"encoding/json"
+	"openseasync/common/utils"
+	"openseasync/logs"
+	"openseasync/models"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Collection sync every wallet holding assets of a collection. Assets are stored by wallet and a wallet sync
+// drops what it no longer returns, so the holders are synced in full rather than the collection alone.
+// Every holder is tried, the first error is returned along with the wallets synced.
+func Collection(ctx context.Context, network, collectionId, trigger string) ([]string, error) {
+	holders, err := models.FindCollectionHolders(network, collectionId)
+	if err != nil {
+		return nil, err
+	}
+	return wallets(ctx, network, holders, trigger)
+}
+
+// Asset sync the wallets holding an asset, the ones known here and its current owner on opensea
+func Asset(ctx context.Context, network, contractAddress, tokenId, trigger string) ([]string, error) {
+	contractAddress = strings.ToLower(contractAddress)
+	holders, err := models.FindAssetHolders(network, contractAddress, tokenId)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := utils.RequestOpenSeaSingleAsset(ctx, network, contractAddress, tokenId)
+	if err != nil {
+		logs.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	var asset models.AutoAsset
+	if err = json.Unmarshal(content, &asset); err != nil {
+		logs.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	if owner := strings.ToLower(asset.Owner.Address); owner != "" && !contains(holders, owner) {
+		holders = append(holders, owner)
+	}
+	return wallets(ctx, network, holders, trigger)
+}
+
+// wallets sync each wallet in turn, the ones synced and the first error, erased wallets are skipped
+func wallets(ctx context.Context, network string, users []string, trigger string) ([]string, error) {
+	var (
+		synced   = make([]string, 0, len(users))
+		firstErr error
+	)
+	for _, user := range users {
+		if err := ctx.Err(); err != nil {
+			return synced, err
+		}
+		userCtx := logs.WithFields(ctx, logrus.Fields{"wallet": user, "chain": network})
+		if err := Wallet(userCtx, network, user, trigger); err == ERASED_ERR {
+			continue
+		} else if err != nil {
+			logs.FromContext(userCtx).Error(err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		synced = append(synced, user)
+	}
+	return synced, firstErr
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,215 @@
+// Package syncer copies the collections and assets of wallets from opensea, for the api and the command line alike
+package syncer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"openseasync/common/utils"
+	"openseasync/logs"
+	"openseasync/metrics"
+	"openseasync/models"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	RUNNING_ERR = errors.New("a sync of the wallet is already running")
+	ERASED_ERR  = errors.New("the wallet was erased, syncing it is blocked until it opts in again")
+)
+
+// runningSyncs the syncs running in this process by chain|wallet, so they can be cancelled
+var (
+	runningSyncs     = make(map[string]*runningSync)
+	runningSyncsLock sync.Mutex
+)
+
+type runningSync struct {
+	run    *models.SyncRun
+	cancel context.CancelFunc
+	done   chan struct{} // closed once the sync stopped writing
+}
+
+// Wallet sync the collections then the assets of a wallet, recorded as a SyncRun
+func Wallet(ctx context.Context, network, user, trigger string) error {
+	ctx, run, err := Start(ctx, network, user, trigger)
+	if err != nil {
+		return err
+	}
+	return Run(ctx, run)
+}
+
+// Start record a run of a wallet, RUNNING_ERR when one is already running, ERASED_ERR when the wallet
+// was erased. The run stops when the returned context is done or Cancel is called, Run must follow.
+func Start(ctx context.Context, network, user, trigger string) (context.Context, *models.SyncRun, error) {
+	key := network + "|" + user
+	runningSyncsLock.Lock()
+	if _, ok := runningSyncs[key]; ok {
+		runningSyncsLock.Unlock()
+		return nil, nil, RUNNING_ERR
+	}
+	run := &models.SyncRun{
+		Id:             uuid.New().String(),
+		Chain:          network,
+		UserMetamaskId: user,
+		Trigger:        trigger,
+		Status:         models.SYNC_STATUS_RUNNING,
+		StartTime:      time.Now().UnixMilli(),
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	runningSyncs[key] = &runningSync{run: run, cancel: cancel, done: make(chan struct{})}
+	runningSyncsLock.Unlock()
+
+	// the block is checked once the sync is registered, so an erasure either blocks it or waits for it
+	blocked, err := models.IsSyncBlocked(user)
+	if err == nil && blocked {
+		err = ERASED_ERR
+	}
+	if err == nil {
+		err = models.InsertSyncRun(run)
+	}
+	if err != nil {
+		stopped(key)
+		return nil, nil, err
+	}
+	return ctx, run, nil
+}
+
+// Run run a started sync and record how it ended
+func Run(ctx context.Context, run *models.SyncRun) error {
+	err := openSeaOwnerCollectionsSync(ctx, run.Chain, run.UserMetamaskId)
+	if err == nil {
+		err = openSeaOwnerAssetsSync(ctx, run.Chain, run.UserMetamaskId)
+	}
+	status, reason := models.SYNC_STATUS_SUCCESS, ""
+	if errors.Is(ctx.Err(), context.Canceled) {
+		status = models.SYNC_STATUS_CANCELLED
+	} else if err != nil {
+		status, reason = models.SYNC_STATUS_FAILED, err.Error()
+	}
+
+	if finishErr := models.FinishSyncRun(run.Id, status, reason); finishErr != nil {
+		logs.FromContext(ctx).Error(finishErr)
+	}
+	run.Status, run.Error = status, reason
+	stopped(run.Chain + "|" + run.UserMetamaskId)
+	return err
+}
+
+// stopped forget the sync of key and release whoever waits for it to stop
+func stopped(key string) {
+	runningSyncsLock.Lock()
+	defer runningSyncsLock.Unlock()
+	if running, ok := runningSyncs[key]; ok {
+		running.cancel()
+		close(running.done)
+		delete(runningSyncs, key)
+	}
+}
+
+// Cancel stop the running sync of a wallet, nil when there is none
+func Cancel(network, user string) *models.SyncRun {
+	runningSyncsLock.Lock()
+	defer runningSyncsLock.Unlock()
+	running, ok := runningSyncs[network+"|"+user]
+	if !ok {
+		return nil
+	}
+	running.cancel()
+	return running.run
+}
+
+// CancelWallet stop the running syncs of a wallet on every chain and wait until they stopped writing,
+// the runs cancelled
+func CancelWallet(user string) []*models.SyncRun {
+	var (
+		runs []*models.SyncRun
+		done []chan struct{}
+	)
+	runningSyncsLock.Lock()
+	for _, running := range runningSyncs {
+		if running.run.UserMetamaskId == user {
+			running.cancel()
+			runs = append(runs, running.run)
+			done = append(done, running.done)
+		}
+	}
+	runningSyncsLock.Unlock()
+	for _, d := range done {
+		<-d
+	}
+	return runs
+}
+
+// openSeaOwnerAssetsSync get all assets by owner
+func openSeaOwnerAssetsSync(ctx context.Context, network, user string) error {
+	defer metrics.ObserveSync("assets", time.Now())
+	var n int64 = 1
+	refreshTime := time.Now().UnixMilli()
+	for {
+		// If the number of requests is too many, a 429 error code will be thrown
+		if err := utils.Sleep(ctx, time.Second*2); err != nil {
+			return err
+		}
+		content, err := utils.RequestOpenSeaAssets(ctx, network, user, 50*(n-1), 50)
+		if err != nil {
+			logs.FromContext(ctx).Error(err)
+			return err
+		}
+		var assets models.OwnerAsset
+		if err = json.Unmarshal(content, &assets); err != nil {
+			logs.FromContext(ctx).Error(err)
+			return err
+		}
+		if len(assets.Assets) < 1 {
+			break
+		}
+		if err = models.InsertOpenSeaAsset(ctx, network, &assets, user, refreshTime); err != nil {
+			logs.FromContext(ctx).Error(err)
+			return err
+		}
+		if len(assets.Assets) < 50 {
+			break
+		}
+		n++
+
+	}
+
+	return nil
+}
+
+// openSeaOwnerCollectionsSync get all collections by owner
+func openSeaOwnerCollectionsSync(ctx context.Context, network, user string) error {
+	defer metrics.ObserveSync("collections", time.Now())
+	var n int64 = 1
+	refreshTime := time.Now().UnixMilli()
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		content, err := utils.RequestOpenSeaCollections(ctx, network, user, 300*(n-1), 300*n)
+		if err != nil {
+			logs.FromContext(ctx).Error(err)
+			return err
+		}
+		var collections models.OwnerCollection
+		if err = json.Unmarshal(content, &collections.Collections); err != nil {
+			logs.FromContext(ctx).Error(err)
+			return err
+		}
+		if err = models.InsertOpenSeaCollection(ctx, network, &collections, user, refreshTime); err != nil {
+			return err
+		}
+		if len(collections.Collections) < 300 {
+			break
+		}
+		n++
+		if err := utils.Sleep(ctx, time.Second); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
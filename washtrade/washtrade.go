@@ -0,0 +1,69 @@
+package washtrade
+
+import (
+	"context"
+	"openseasync/config"
+	"openseasync/logs"
+	"openseasync/models"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultInterval        = 15 * time.Minute
+	defaultLookback        = 30 * 24 * time.Hour
+	defaultRoundTripWindow = 72 * time.Hour
+	defaultPriceDeviation  = 5
+)
+
+// Run flag the suspicious sales until ctx is done
+func Run(ctx context.Context) {
+	interval := defaultInterval
+	if seconds := config.GetConfig().WashTrade.Interval; seconds > 0 {
+		interval = time.Duration(seconds) * time.Second
+	}
+	ctx = logs.WithFields(ctx, logrus.Fields{"component": "washtrade"})
+
+	for {
+		if _, err := AnalyzeOnce(ctx); err != nil {
+			logs.FromContext(ctx).Error(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// AnalyzeOnce flag the suspicious sales of the configured lookback, the number of sales whose flag changed
+func AnalyzeOnce(ctx context.Context) (int64, error) {
+	start := time.Now()
+	changed, err := models.FlagSuspiciousSales(ctx, Rules(start))
+	if err != nil {
+		return changed, err
+	}
+	logs.FromContext(ctx).WithFields(logrus.Fields{"changed": changed, "duration": time.Since(start).String()}).Debug("suspicious sales flagged")
+	return changed, nil
+}
+
+// Rules the configured thresholds as of now, the defaults for those left out
+func Rules(now time.Time) models.WashTradeRules {
+	conf := config.GetConfig().WashTrade
+	rules := models.WashTradeRules{
+		Since:           now.Add(-defaultLookback).UnixMilli(),
+		RoundTripWindow: defaultRoundTripWindow,
+		PriceDeviation:  defaultPriceDeviation,
+	}
+	if conf.Lookback > 0 {
+		rules.Since = now.Add(-time.Duration(conf.Lookback) * 24 * time.Hour).UnixMilli()
+	}
+	if conf.RoundTripWindow > 0 {
+		rules.RoundTripWindow = time.Duration(conf.RoundTripWindow) * time.Hour
+	}
+	if conf.PriceDeviation > 1 {
+		rules.PriceDeviation = conf.PriceDeviation
+	}
+	return rules
+}
@@ -0,0 +1,68 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestValidateTarget(t *testing.T) {
+	tests := []struct {
+		url string
+		ok  bool
+	}{
+		{"https://93.184.216.34/hook", true},
+		{"http://[2606:2800:220:1:248:1893:25c8:1946]/hook", true},
+		{"ftp://93.184.216.34/hook", false},
+		{"https:///hook", false},
+		{"http://127.0.0.1:8080/hook", false},
+		{"http://localhost/hook", false},
+		{"http://10.0.0.8/hook", false},
+		{"http://172.16.3.4/hook", false},
+		{"http://192.168.1.1/hook", false},
+		{"http://169.254.169.254/latest/meta-data", false},
+		{"http://100.64.0.1/hook", false},
+		{"http://0.0.0.0/hook", false},
+		{"http://[::1]/hook", false},
+		{"http://[fd00::1]/hook", false},
+		{"http://[fe80::1]/hook", false},
+	}
+	for _, tt := range tests {
+		err := ValidateTarget(context.Background(), tt.url)
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: got %v, want ok %v", tt.url, err, tt.ok)
+		}
+	}
+}
+
+func TestDeliveryRefusesPrivateTargets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := newHTTPClient(time.Second)
+	if _, err := client.Post(server.URL, "application/json", nil); err == nil {
+		t.Fatal("a loopback target was delivered to")
+	}
+}
+
+func TestDeliveryDoesNotFollowRedirects(t *testing.T) {
+	client := newHTTPClient(time.Second)
+	client.Transport = http.DefaultTransport // the test server is on loopback
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://169.254.169.254/latest/meta-data", http.StatusFound)
+	}))
+	defer server.Close()
+
+	resp, err := client.Post(server.URL, "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("status %d, want the redirect itself", resp.StatusCode)
+	}
+}
@@ -0,0 +1,248 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"openseasync/config"
+	"openseasync/events"
+	"openseasync/logs"
+	"openseasync/models"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	SignatureHeader = "X-Openseasync-Signature" // sha256=hex(hmac(secret, timestamp + "." + body))
+	TimestampHeader = "X-Openseasync-Timestamp" // unix seconds the signature was computed at
+	EventHeader     = "X-Openseasync-Event"
+	DeliveryHeader  = "X-Openseasync-Delivery"
+)
+
+// EventTypes events a webhook can subscribe to, everything else on the bus is ignored
+var EventTypes = map[string]bool{
+	events.EVENT_TYPE_SALE:                 true,
+	events.EVENT_TYPE_BID:                  true,
+	events.EVENT_TYPE_ASSET_REMOVED:        true,
+	events.EVENT_TYPE_FLOOR_PRICE_CHANGED:  true,
+	events.EVENT_TYPE_ORDER_STATUS_CHANGED: true,
+}
+
+var (
+	queue      chan *models.WebhookDelivery
+	httpClient *http.Client
+)
+
+// Start run the delivery workers and send every published event to the matching subscriptions
+func Start() {
+	conf := config.GetConfig().Webhook
+	workers := conf.Workers
+	if workers < 1 {
+		workers = 4
+	}
+	queueSize := conf.QueueSize
+	if queueSize < 1 {
+		queueSize = 1000
+	}
+	timeout := conf.Timeout
+	if timeout < 1 {
+		timeout = 10
+	}
+
+	httpClient = newHTTPClient(time.Duration(timeout) * time.Second)
+	queue = make(chan *models.WebhookDelivery, queueSize)
+	for i := 0; i < workers; i++ {
+		go worker()
+	}
+	events.Subscribe(dispatch)
+}
+
+// Sign the signature sent in SignatureHeader, receivers recompute it to authenticate a delivery
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Redeliver queue a dead letter again from its first attempt
+func Redeliver(ctx context.Context, delivery *models.WebhookDelivery) error {
+	if err := models.DeleteWebhookDeadLetterById(delivery.Id); err != nil {
+		return err
+	}
+	delivery.Attempts = 0
+	delivery.Status = models.WEBHOOK_DELIVERY_STATUS_PENDING
+	delivery.LastError = ""
+	if err := models.SaveWebhookDelivery(delivery); err != nil {
+		return err
+	}
+	enqueue(ctx, delivery)
+	return nil
+}
+
+// dispatch log a delivery for each subscription matching event and queue it
+func dispatch(ctx context.Context, event events.Event) {
+	if queue == nil || !EventTypes[event.Type] {
+		return
+	}
+	webhooks, err := models.FindWebhooksForEvent(event)
+	if err != nil {
+		logs.FromContext(ctx).Error(err)
+		return
+	}
+	if len(webhooks) == 0 {
+		return
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		logs.FromContext(ctx).Error(err)
+		return
+	}
+
+	for _, v := range webhooks {
+		delivery := &models.WebhookDelivery{
+			Id:         uuid.New().String(),
+			WebhookId:  v.Id,
+			Url:        v.Url,
+			EventType:  event.Type,
+			Payload:    string(payload),
+			Status:     models.WEBHOOK_DELIVERY_STATUS_PENDING,
+			CreateDate: time.Now().UnixMilli(),
+		}
+		if err := models.SaveWebhookDelivery(delivery); err != nil {
+			logs.FromContext(ctx).Error(err)
+			continue
+		}
+		enqueue(ctx, delivery)
+	}
+}
+
+// enqueue hand delivery to a worker, dead-letter it when the queue is full
+func enqueue(ctx context.Context, delivery *models.WebhookDelivery) {
+	select {
+	case queue <- delivery:
+	default:
+		logs.FromContext(ctx).WithField("delivery", delivery.Id).Warn("webhook queue is full")
+		deadLetter(delivery, "webhook queue is full")
+	}
+}
+
+func worker() {
+	for delivery := range queue {
+		deliver(delivery)
+	}
+}
+
+// deliver make one attempt, scheduling the next one with backoff when it fails
+func deliver(delivery *models.WebhookDelivery) {
+	logger := logs.GetLogger().WithFields(logrus.Fields{"webhook": delivery.WebhookId, "delivery": delivery.Id})
+	// a delivery erased with the wallet it names while queued or waiting for a retry is dropped
+	stored, err := models.FindWebhookDeliveryById(delivery.Id)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+	if stored == nil {
+		logger.Info("webhook delivery was erased")
+		return
+	}
+	webhook, err := models.FindWebhookById(delivery.WebhookId)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+	if webhook == nil {
+		deadLetter(delivery, "webhook was deleted")
+		return
+	}
+	// the target may resolve elsewhere than when it was registered
+	if err := ValidateTarget(context.Background(), webhook.Url); err != nil {
+		deadLetter(delivery, err.Error())
+		return
+	}
+
+	delivery.Attempts++
+	delivery.ResponseCode, err = post(webhook, delivery)
+	if err == nil {
+		delivery.Status = models.WEBHOOK_DELIVERY_STATUS_SUCCESS
+		delivery.LastError = ""
+		if err := models.SaveWebhookDelivery(delivery); err != nil {
+			logger.Error(err)
+		}
+		return
+	}
+
+	logger.WithField("attempt", delivery.Attempts).Warn(err)
+	conf := config.GetConfig().Webhook
+	maxAttempts := conf.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 5
+	}
+	if delivery.Attempts >= maxAttempts {
+		deadLetter(delivery, err.Error())
+		return
+	}
+
+	delivery.Status = models.WEBHOOK_DELIVERY_STATUS_RETRY
+	delivery.LastError = err.Error()
+	if err := models.SaveWebhookDelivery(delivery); err != nil {
+		logger.Error(err)
+	}
+	interval := conf.RetryInterval
+	if interval < 1 {
+		interval = 10
+	}
+	backoff := time.Duration(interval) * time.Second << (delivery.Attempts - 1)
+	time.AfterFunc(backoff, func() {
+		enqueue(context.Background(), delivery)
+	})
+}
+
+// post send the signed payload, any status outside 2xx is an error
+func post(webhook *models.Webhook, delivery *models.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	timestamp := time.Now().Unix()
+	req, err := http.NewRequest(http.MethodPost, webhook.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(webhook.Secret, timestamp, body))
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, delivery.Id)
+
+	// redirects are not followed, a 3xx answer is a failed delivery
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook answered %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// deadLetter give up on delivery and keep it for a later redelivery
+func deadLetter(delivery *models.WebhookDelivery, reason string) {
+	delivery.Status = models.WEBHOOK_DELIVERY_STATUS_DEAD
+	delivery.LastError = reason
+	if err := models.SaveWebhookDelivery(delivery); err != nil {
+		logs.GetLogger().Error(err)
+	}
+	if err := models.InsertWebhookDeadLetter(delivery); err != nil {
+		logs.GetLogger().Error(err)
+	}
+}